@@ -3,30 +3,40 @@ package admin
 import (
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models/dto"
 	st "github.com/Worldcoin/hubble-commander/storage"
 )
 
 type API struct {
 	cfg                 *config.APIConfig
+	backupCfg           *config.BackupConfig
 	storage             *st.Storage
 	client              *eth.Client
 	enableBatchCreation func(enable bool)
 	enableTxsAcceptance func(enable bool)
+	isAcceptingTxs      func() bool
+	getRollupStatus     func() dto.RollupStatus
 }
 
 func NewAPI(
 	cfg *config.APIConfig,
+	backupCfg *config.BackupConfig,
 	storage *st.Storage,
 	client *eth.Client,
 	enableBatchCreation func(enable bool),
 	enableTxsAcceptance func(enable bool),
+	isAcceptingTxs func() bool,
+	getRollupStatus func() dto.RollupStatus,
 ) *API {
 	return &API{
 		cfg:                 cfg,
+		backupCfg:           backupCfg,
 		storage:             storage,
 		client:              client,
 		enableBatchCreation: enableBatchCreation,
 		enableTxsAcceptance: enableTxsAcceptance,
+		isAcceptingTxs:      isAcceptingTxs,
+		getRollupStatus:     getRollupStatus,
 	}
 }
 
@@ -36,6 +46,6 @@ func NewTestAPI(
 	client *eth.Client,
 ) *API {
 	return NewAPI(
-		cfg, storage, client, nil, nil,
+		cfg, &config.BackupConfig{}, storage, client, nil, nil, nil, nil,
 	)
 }
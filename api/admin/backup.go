@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/pkg/errors"
+)
+
+var (
+	errBackupsDisabled   = fmt.Errorf("backups are disabled, set backup.dir to enable them")
+	errMissingBackupPath = fmt.Errorf("missing backup path")
+	errInvalidBackupPath = fmt.Errorf("backup path must be a file name inside backup.dir")
+)
+
+// Backup streams the database to a file in backup.dir on the commander host without stopping the commander
+func (a *API) Backup(ctx context.Context, params dto.BackupParams) (*dto.Backup, error) {
+	err := a.verifyAuthKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path, err := a.backupFilePath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	version, err := a.writeBackup(file, params.Since)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	return &dto.Backup{
+		Path:    path,
+		Since:   params.Since,
+		Version: version,
+	}, nil
+}
+
+// backupFilePath only accepts a bare file name so that the backup cannot be written outside of backup.dir
+func (a *API) backupFilePath(name string) (string, error) {
+	if a.backupCfg == nil || a.backupCfg.Dir == "" {
+		return "", errBackupsDisabled
+	}
+	if name == "" {
+		return "", errMissingBackupPath
+	}
+	if filepath.Base(name) != name || name == "." || name == ".." {
+		return "", errInvalidBackupPath
+	}
+	return filepath.Join(a.backupCfg.Dir, name), nil
+}
+
+func (a *API) writeBackup(file *os.File, since uint64) (uint64, error) {
+	writer := bufio.NewWriter(file)
+	version, err := a.storage.Backup(writer, since)
+	if err != nil {
+		return 0, err
+	}
+	err = writer.Flush()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return version, errors.WithStack(file.Sync())
+}
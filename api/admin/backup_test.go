@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BackupTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+	dir     string
+}
+
+func (s *BackupTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *BackupTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.dir, err = os.MkdirTemp("", "admin_backup_test")
+	s.NoError(err)
+	s.api = &API{
+		cfg:       &config.APIConfig{AuthenticationKey: authKeyValue},
+		backupCfg: &config.BackupConfig{Dir: s.dir},
+		storage:   s.storage.Storage,
+	}
+}
+
+func (s *BackupTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+	err = os.RemoveAll(s.dir)
+	s.NoError(err)
+}
+
+func (s *BackupTestSuite) TestBackup() {
+	path := filepath.Join(s.dir, "full.bak")
+
+	backup, err := s.api.Backup(contextWithAuthKey(authKeyValue), dto.BackupParams{Path: "full.bak"})
+	s.NoError(err)
+	s.Equal(path, backup.Path)
+	s.NotZero(backup.Version)
+
+	info, err := os.Stat(path)
+	s.NoError(err)
+	s.NotZero(info.Size())
+}
+
+func (s *BackupTestSuite) TestBackup_RefusesToOverwriteExistingFile() {
+	path := filepath.Join(s.dir, "existing.bak")
+	err := os.WriteFile(path, []byte{1, 2, 3}, 0600)
+	s.NoError(err)
+
+	_, err = s.api.Backup(contextWithAuthKey(authKeyValue), dto.BackupParams{Path: "existing.bak"})
+	s.ErrorIs(err, os.ErrExist)
+
+	content, err := os.ReadFile(path)
+	s.NoError(err)
+	s.Equal([]byte{1, 2, 3}, content)
+}
+
+func (s *BackupTestSuite) TestBackup_MissingPath() {
+	_, err := s.api.Backup(contextWithAuthKey(authKeyValue), dto.BackupParams{})
+	s.ErrorIs(err, errMissingBackupPath)
+}
+
+func (s *BackupTestSuite) TestBackup_RejectsPathsOutsideBackupDir() {
+	paths := []string{
+		filepath.Join(s.dir, "full.bak"),
+		"../full.bak",
+		"nested/full.bak",
+		"..",
+	}
+	for _, path := range paths {
+		_, err := s.api.Backup(contextWithAuthKey(authKeyValue), dto.BackupParams{Path: path})
+		s.ErrorIs(err, errInvalidBackupPath, path)
+	}
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(s.dir), "full.bak"))
+	s.ErrorIs(err, os.ErrNotExist)
+}
+
+func (s *BackupTestSuite) TestBackup_DisabledWithoutBackupDir() {
+	s.api.backupCfg = &config.BackupConfig{}
+	_, err := s.api.Backup(contextWithAuthKey(authKeyValue), dto.BackupParams{Path: "full.bak"})
+	s.ErrorIs(err, errBackupsDisabled)
+}
+
+func (s *BackupTestSuite) TestBackup_RequiresAuthentication() {
+	_, err := s.api.Backup(context.Background(), dto.BackupParams{Path: "full.bak"})
+	s.ErrorIs(err, errMissingAuthKey)
+}
+
+func TestBackupTestSuite(t *testing.T) {
+	suite.Run(t, new(BackupTestSuite))
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+)
+
+// GetNodeStatus returns the sync progress of the commander and the state of its rollup loop
+func (a *API) GetNodeStatus(ctx context.Context) (*dto.NodeStatus, error) {
+	err := a.verifyAuthKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := dto.NodeStatus{
+		Version:    a.cfg.Version,
+		LocalBlock: a.storage.GetLatestBlockNumber(),
+	}
+
+	syncedBlock, err := a.storage.GetSyncedBlock()
+	if err != nil {
+		return nil, err
+	}
+	status.SyncedBlock = *syncedBlock
+
+	remoteBlock, err := a.client.Blockchain.GetLatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	status.RemoteBlock = *remoteBlock
+
+	latestBatch, err := a.storage.GetLatestSubmittedBatch()
+	if err != nil && !st.IsNotFoundError(err) {
+		return nil, err
+	}
+	if latestBatch != nil {
+		status.LatestBatch = &latestBatch.ID
+	}
+
+	status.IsActiveProposer, err = a.client.IsActiveProposer()
+	if err != nil {
+		return nil, err
+	}
+
+	if a.isAcceptingTxs != nil {
+		status.AcceptingTransactions = a.isAcceptingTxs()
+	}
+	if a.getRollupStatus != nil {
+		status.Rollup = a.getRollupStatus()
+	}
+
+	mempoolCounts, err := a.storage.CountMempoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	status.Mempool = dto.MempoolStatus{
+		Transfer:        mempoolCounts[txtype.Transfer],
+		Create2Transfer: mempoolCounts[txtype.Create2Transfer],
+		MassMigration:   mempoolCounts[txtype.MassMigration],
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetNodeStatusTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api          *API
+	storage      *st.TestStorage
+	client       *eth.TestClient
+	rollupStatus dto.RollupStatus
+}
+
+func (s *GetNodeStatusTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *GetNodeStatusTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.client, err = eth.NewTestClient()
+	s.NoError(err)
+
+	s.rollupStatus = dto.RollupStatus{
+		LoopActive:           true,
+		BatchCreationEnabled: true,
+		PendingL1Txs:         2,
+	}
+	s.api = &API{
+		cfg:             &config.APIConfig{AuthenticationKey: authKeyValue, Version: "v0123"},
+		storage:         s.storage.Storage,
+		client:          s.client.Client,
+		isAcceptingTxs:  func() bool { return false },
+		getRollupStatus: func() dto.RollupStatus { return s.rollupStatus },
+	}
+
+	err = s.storage.SetSyncedBlock(10)
+	s.NoError(err)
+	s.storage.SetLatestBlockNumber(12)
+}
+
+func (s *GetNodeStatusTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *GetNodeStatusTestSuite) TestGetNodeStatus() {
+	batch := models.Batch{
+		ID:              models.MakeUint256(3),
+		Type:            batchtype.Transfer,
+		TransactionHash: utils.RandomHash(),
+		Hash:            utils.NewRandomHash(),
+	}
+	err := s.storage.AddBatch(&batch)
+	s.NoError(err)
+
+	remoteBlock, err := s.client.GetLatestBlockNumber()
+	s.NoError(err)
+
+	status, err := s.api.GetNodeStatus(contextWithAuthKey(authKeyValue))
+	s.NoError(err)
+	s.Equal("v0123", status.Version)
+	s.EqualValues(12, status.LocalBlock)
+	s.EqualValues(10, status.SyncedBlock)
+	s.Equal(*remoteBlock, status.RemoteBlock)
+	s.Equal(batch.ID, *status.LatestBatch)
+	s.True(status.IsActiveProposer)
+	s.False(status.AcceptingTransactions)
+	s.Equal(s.rollupStatus, status.Rollup)
+	s.Equal(dto.MempoolStatus{}, status.Mempool)
+}
+
+func (s *GetNodeStatusTestSuite) TestGetNodeStatus_NoBatches() {
+	status, err := s.api.GetNodeStatus(contextWithAuthKey(authKeyValue))
+	s.NoError(err)
+	s.Nil(status.LatestBatch)
+}
+
+func (s *GetNodeStatusTestSuite) TestGetNodeStatus_RequiresAuthKey() {
+	_, err := s.api.GetNodeStatus(contextWithAuthKey("invalid"))
+	s.Error(err)
+}
+
+func TestGetNodeStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(GetNodeStatusTestSuite))
+}
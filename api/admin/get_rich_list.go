@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+)
+
+const maxRichListSize = 1000
+
+// GetRichList returns up to limit batched user states of the token with the highest balances, in descending order
+func (a *API) GetRichList(ctx context.Context, tokenID models.Uint256, limit *uint32) ([]dto.UserStateWithID, error) {
+	err := a.verifyAuthKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	size := uint32(maxRichListSize)
+	if limit != nil && *limit > 0 && *limit < maxRichListSize {
+		size = *limit
+	}
+
+	leaves, err := a.storage.GetRichestStateLeaves(tokenID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	userStates := make([]dto.UserStateWithID, 0, len(leaves))
+	for i := range leaves {
+		userStates = append(userStates, dto.MakeUserStateWithID(leaves[i].StateID, &leaves[i].UserState))
+	}
+	return userStates, nil
+}
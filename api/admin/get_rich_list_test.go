@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetRichListTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+}
+
+func (s *GetRichListTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *GetRichListTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.api = &API{
+		cfg:     &config.APIConfig{AuthenticationKey: authKeyValue},
+		storage: s.storage.Storage,
+	}
+
+	balances := []uint64{100, 700, 300, 500}
+	for i := range balances {
+		_, err = s.storage.StateTree.Set(uint32(i), &models.UserState{
+			PubKeyID: uint32(i),
+			TokenID:  models.MakeUint256(uint64(i % 2)),
+			Balance:  models.MakeUint256(balances[i]),
+			Nonce:    models.MakeUint256(0),
+		})
+		s.NoError(err)
+	}
+}
+
+func (s *GetRichListTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *GetRichListTestSuite) TestGetRichList() {
+	userStates, err := s.api.GetRichList(contextWithAuthKey(authKeyValue), models.MakeUint256(1), ref.Uint32(1))
+	s.NoError(err)
+	s.Len(userStates, 1)
+	s.EqualValues(1, userStates[0].StateID)
+	s.Equal(models.MakeUint256(700), userStates[0].Balance)
+
+	userStates, err = s.api.GetRichList(contextWithAuthKey(authKeyValue), models.MakeUint256(0), nil)
+	s.NoError(err)
+	s.Len(userStates, 2)
+	s.EqualValues(2, userStates[0].StateID)
+	s.EqualValues(0, userStates[1].StateID)
+}
+
+func (s *GetRichListTestSuite) TestGetRichList_RequiresAuthKey() {
+	_, err := s.api.GetRichList(context.Background(), models.MakeUint256(0), nil)
+	s.Error(err)
+}
+
+func TestGetRichListTestSuite(t *testing.T) {
+	suite.Run(t, new(GetRichListTestSuite))
+}
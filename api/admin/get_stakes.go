@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/Worldcoin/hubble-commander/models/dto"
+)
+
+// GetStakes returns the stakes locked with batches submitted by this commander which were not withdrawn yet
+func (a *API) GetStakes(ctx context.Context) ([]dto.Stake, error) {
+	err := a.verifyAuthKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingStakes, err := a.storage.GetPendingStakeWithdrawals()
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlockNumber, err := a.client.Blockchain.GetLatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	stakeAmount := a.client.GetStakeAmount()
+	stakes := make([]dto.Stake, 0, len(pendingStakes))
+	for i := range pendingStakes {
+		stakes = append(stakes, dto.Stake{
+			BatchID:           pendingStakes[i].BatchID,
+			Amount:            stakeAmount,
+			FinalisationBlock: pendingStakes[i].FinalisationBlock,
+			Withdrawable:      uint64(pendingStakes[i].FinalisationBlock) <= *latestBlockNumber,
+		})
+	}
+	return stakes, nil
+}
@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetStakesTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+	client  *eth.TestClient
+}
+
+func (s *GetStakesTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *GetStakesTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.client, err = eth.NewTestClient()
+	s.NoError(err)
+	s.api = &API{
+		cfg:     &config.APIConfig{AuthenticationKey: authKeyValue},
+		storage: s.storage.Storage,
+		client:  s.client.Client,
+	}
+}
+
+func (s *GetStakesTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *GetStakesTestSuite) TestGetStakes() {
+	latestBlockNumber, err := s.client.GetLatestBlockNumber()
+	s.NoError(err)
+
+	pendingStakes := []models.PendingStakeWithdrawal{
+		{
+			BatchID:           models.MakeUint256(1),
+			FinalisationBlock: uint32(*latestBlockNumber),
+		},
+		{
+			BatchID:           models.MakeUint256(2),
+			FinalisationBlock: uint32(*latestBlockNumber) + 100,
+		},
+	}
+	for i := range pendingStakes {
+		err = s.storage.AddPendingStakeWithdrawal(&pendingStakes[i])
+		s.NoError(err)
+	}
+
+	stakes, err := s.api.GetStakes(contextWithAuthKey(authKeyValue))
+	s.NoError(err)
+	s.Len(stakes, 2)
+
+	for i := range stakes {
+		s.Equal(pendingStakes[i].BatchID, stakes[i].BatchID)
+		s.Equal(pendingStakes[i].FinalisationBlock, stakes[i].FinalisationBlock)
+		s.Equal(s.client.GetStakeAmount(), stakes[i].Amount)
+	}
+	s.True(stakes[0].Withdrawable)
+	s.False(stakes[1].Withdrawable)
+}
+
+func (s *GetStakesTestSuite) TestGetStakes_NoStakes() {
+	stakes, err := s.api.GetStakes(contextWithAuthKey(authKeyValue))
+	s.NoError(err)
+	s.Len(stakes, 0)
+}
+
+func TestGetStakesTestSuite(t *testing.T) {
+	suite.Run(t, new(GetStakesTestSuite))
+}
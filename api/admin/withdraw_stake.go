@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var errStakeNotFinalised = fmt.Errorf("stake cannot be withdrawn before the batch is finalised")
+
+// WithdrawStake withdraws the stake of a finalised batch without waiting for the new block loop to do it
+func (a *API) WithdrawStake(ctx context.Context, batchID models.Uint256) (*common.Hash, error) {
+	err := a.verifyAuthKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stake, err := a.storage.GetPendingStakeWithdrawal(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlockNumber, err := a.client.Blockchain.GetLatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(stake.FinalisationBlock) > *latestBlockNumber {
+		return nil, errStakeNotFinalised
+	}
+
+	// the stake is taken before sending so that the new block loop does not withdraw it at the same time
+	stake, err = a.storage.TakePendingStakeWithdrawal(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.client.WithdrawStake(&batchID)
+	if err != nil {
+		restoreErr := a.storage.AddPendingStakeWithdrawal(stake)
+		if restoreErr != nil {
+			log.Errorf("Failed to restore pending stake withdrawal of batch #%s: %+v", batchID.String(), restoreErr)
+		}
+		return nil, err
+	}
+
+	txHash := tx.Hash()
+	return &txHash, nil
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/eth/deployer/rollup"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WithdrawStakeTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+	client  *eth.TestClient
+}
+
+func (s *WithdrawStakeTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *WithdrawStakeTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	// finalize instantly
+	s.client, err = eth.NewConfiguredTestClient(&rollup.DeploymentConfig{
+		Params: rollup.Params{BlocksToFinalise: models.NewUint256(0)},
+	}, &eth.TestClientConfig{})
+	s.NoError(err)
+	s.api = &API{
+		cfg:     &config.APIConfig{AuthenticationKey: authKeyValue},
+		storage: s.storage.Storage,
+		client:  s.client.Client,
+	}
+}
+
+func (s *WithdrawStakeTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *WithdrawStakeTestSuite) TestWithdrawStake() {
+	batchID := models.MakeUint256(1)
+	s.submitBatch(&batchID)
+
+	err := s.storage.AddPendingStakeWithdrawal(&models.PendingStakeWithdrawal{
+		BatchID:           batchID,
+		FinalisationBlock: 0,
+	})
+	s.NoError(err)
+
+	txHash, err := s.api.WithdrawStake(contextWithAuthKey(authKeyValue), batchID)
+	s.NoError(err)
+
+	tx, _, err := s.client.Blockchain.GetBackend().TransactionByHash(context.Background(), *txHash)
+	s.NoError(err)
+	receipt, err := s.client.WaitToBeMined(tx)
+	s.NoError(err)
+	s.EqualValues(1, receipt.Status)
+
+	_, err = s.storage.GetPendingStakeWithdrawal(batchID)
+	s.ErrorIs(err, st.NewNotFoundError("pending stake withdrawal"))
+}
+
+func (s *WithdrawStakeTestSuite) TestWithdrawStake_BatchNotFinalised() {
+	batchID := models.MakeUint256(1)
+	err := s.storage.AddPendingStakeWithdrawal(&models.PendingStakeWithdrawal{
+		BatchID:           batchID,
+		FinalisationBlock: 1000,
+	})
+	s.NoError(err)
+
+	_, err = s.api.WithdrawStake(contextWithAuthKey(authKeyValue), batchID)
+	s.ErrorIs(err, errStakeNotFinalised)
+}
+
+func (s *WithdrawStakeTestSuite) TestWithdrawStake_NonexistentStake() {
+	_, err := s.api.WithdrawStake(contextWithAuthKey(authKeyValue), models.MakeUint256(1))
+	s.ErrorIs(err, st.NewNotFoundError("pending stake withdrawal"))
+}
+
+func (s *WithdrawStakeTestSuite) submitBatch(batchID *models.Uint256) {
+	stateRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+
+	commitment := models.TxCommitmentWithTxs{
+		TxCommitment: models.TxCommitment{
+			CommitmentBase: models.CommitmentBase{
+				Type:          batchtype.Transfer,
+				PostStateRoot: *stateRoot,
+			},
+		},
+		Transactions: []byte{},
+	}
+	_, err = s.client.SubmitTransfersBatchAndWait(batchID, []models.CommitmentWithTxs{&commitment})
+	s.NoError(err)
+}
+
+func TestWithdrawStakeTestSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawStakeTestSuite))
+}
@@ -6,15 +6,20 @@ import (
 
 	"github.com/Worldcoin/hubble-commander/api/admin"
 	"github.com/Worldcoin/hubble-commander/api/middleware"
+	"github.com/Worldcoin/hubble-commander/api/replication"
 	"github.com/Worldcoin/hubble-commander/api/rpc"
 	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/commander/leader"
+	"github.com/Worldcoin/hubble-commander/commander/registrar"
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/eth"
 	"github.com/Worldcoin/hubble-commander/metrics"
 	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
 	st "github.com/Worldcoin/hubble-commander/storage"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/ybbus/jsonrpc/v2"
 )
 
 type API struct {
@@ -26,6 +31,10 @@ type API struct {
 	disableSignatures       bool
 	isAcceptingTransactions bool
 	isMigrating             func() bool
+	withdrawTrees           *withdrawTreeCache
+	registrar               *registrar.Registrar
+	elector                 *leader.Elector   // nil when leader election is disabled
+	primary                 jsonrpc.RPCClient // set on replicas
 }
 
 func NewServer(
@@ -35,20 +44,67 @@ func NewServer(
 	commanderMetrics *metrics.CommanderMetrics,
 	enableBatchCreation func(enable bool),
 	isMigrating func() bool,
+	getRollupStatus func() dto.RollupStatus,
+	accountsRegistrar *registrar.Registrar,
+	elector *leader.Elector,
 ) (*http.Server, error) {
 	server, err := getAPIServer(
 		cfg.API,
+		cfg.Backup,
 		storage,
 		client,
 		commanderMetrics,
 		cfg.Rollup.DisableSignatures,
 		enableBatchCreation,
 		isMigrating,
+		getRollupStatus,
+		accountsRegistrar,
+		elector,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	return newHTTPServer(cfg, storage, commanderMetrics, server), nil
+}
+
+// NewReplicaServer serves the hubble namespace from a database replicated from the primary commander,
+// the methods which write anything are forwarded to the primary
+func NewReplicaServer(
+	cfg *config.Config,
+	storage *st.Storage,
+	client *eth.Client,
+	commanderMetrics *metrics.CommanderMetrics,
+	primaryURL string,
+) (*http.Server, error) {
+	hubbleAPI := &API{
+		cfg:                     cfg.API,
+		storage:                 storage,
+		client:                  client,
+		commanderMetrics:        commanderMetrics,
+		disableSignatures:       cfg.Rollup.DisableSignatures,
+		isAcceptingTransactions: true,
+		isMigrating:             func() bool { return false },
+		withdrawTrees:           newWithdrawTreeCache(),
+		primary:                 jsonrpc.NewClient(primaryURL),
+	}
+	if err := hubbleAPI.initSignature(); err != nil {
+		return nil, errors.WithMessage(err, "failed to create mock signature")
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("hubble", hubbleAPI); err != nil {
+		return nil, err
+	}
+	return newHTTPServer(cfg, storage, commanderMetrics, server), nil
+}
+
+func newHTTPServer(
+	cfg *config.Config,
+	storage *st.Storage,
+	commanderMetrics *metrics.CommanderMetrics,
+	server *rpc.Server,
+) *http.Server {
 	var handler http.Handler = server
 
 	if cfg.Tracing.Enabled {
@@ -71,9 +127,10 @@ func NewServer(
 		}
 		w.WriteHeader(200)
 	}))
+	mux.Handle(replication.Endpoint, replication.NewHandler(cfg.API, storage))
 
 	addr := fmt.Sprintf(":%s", cfg.API.Port)
-	return &http.Server{Addr: addr, Handler: mux}, nil
+	return &http.Server{Addr: addr, Handler: mux}
 }
 
 func NewTestAPI(
@@ -87,17 +144,22 @@ func NewTestAPI(
 		commanderMetrics:        metrics.NewCommanderMetrics(),
 		disableSignatures:       true,
 		isAcceptingTransactions: true,
+		withdrawTrees:           newWithdrawTreeCache(),
 	}
 }
 
 func getAPIServer(
 	cfg *config.APIConfig,
+	backupCfg *config.BackupConfig,
 	storage *st.Storage,
 	client *eth.Client,
 	commanderMetrics *metrics.CommanderMetrics,
 	disableSignatures bool,
 	enableBatchCreation func(enable bool),
 	isMigrating func() bool,
+	getRollupStatus func() dto.RollupStatus,
+	accountsRegistrar *registrar.Registrar,
+	elector *leader.Elector,
 ) (*rpc.Server, error) {
 	hubbleAPI := &API{
 		cfg:                     cfg,
@@ -107,12 +169,24 @@ func getAPIServer(
 		disableSignatures:       disableSignatures,
 		isAcceptingTransactions: true,
 		isMigrating:             isMigrating,
+		withdrawTrees:           newWithdrawTreeCache(),
+		registrar:               accountsRegistrar,
+		elector:                 elector,
 	}
 	if err := hubbleAPI.initSignature(); err != nil {
 		return nil, errors.WithMessage(err, "failed to create mock signature")
 	}
 
-	adminAPI := admin.NewAPI(cfg, storage, client, enableBatchCreation, hubbleAPI.enableTxsAcceptance)
+	adminAPI := admin.NewAPI(
+		cfg,
+		backupCfg,
+		storage,
+		client,
+		enableBatchCreation,
+		hubbleAPI.enableTxsAcceptance,
+		hubbleAPI.isAcceptingTxs,
+		getRollupStatus,
+	)
 
 	server := rpc.NewServer()
 	if err := server.RegisterName("hubble", hubbleAPI); err != nil {
@@ -144,3 +218,8 @@ func (a *API) initSignature() error {
 func (a *API) enableTxsAcceptance(enable bool) {
 	a.isAcceptingTransactions = enable
 }
+
+// isAcceptingTxs returns false on a standby, only the leader creates batches out of its mempool
+func (a *API) isAcceptingTxs() bool {
+	return a.isAcceptingTransactions && a.elector.IsLeader()
+}
@@ -30,12 +30,16 @@ func TestStartApiServer(t *testing.T) {
 	commanderMetrics := metrics.NewCommanderMetrics()
 	server, err := getAPIServer(
 		&cfg,
+		&config.BackupConfig{},
 		nil,
 		eth.DomainOnlyTestClient,
 		commanderMetrics,
 		false,
 		func(enable bool) {},
 		func() bool { return false },
+		nil,
+		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/ybbus/jsonrpc/v2"
+)
+
+var APIErrPrimaryUnavailable = NewAPIError(
+	99009,
+	"primary commander is unavailable",
+)
+
+func (a *API) forwardSendTransaction(tx dto.Transaction) (*common.Hash, error) {
+	var transactionHash common.Hash
+	err := a.forwardToPrimary(&transactionHash, "hubble_sendTransaction", tx.Parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionHash, nil
+}
+
+func (a *API) forwardSendTransactions(txs []dto.Transaction) ([]dto.SendTransactionResult, error) {
+	parsedTxs := make([]interface{}, 0, len(txs))
+	for i := range txs {
+		parsedTxs = append(parsedTxs, txs[i].Parsed)
+	}
+
+	var results []dto.SendTransactionResult
+	err := a.forwardToPrimary(&results, "hubble_sendTransactions", parsedTxs)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (a *API) forwardRegisterPublicKey(publicKey *models.PublicKey, proofOfPossession *models.Signature) (*uint32, error) {
+	var pubKeyID uint32
+	err := a.forwardToPrimary(&pubKeyID, "hubble_registerPublicKey", publicKey, proofOfPossession)
+	if err != nil {
+		return nil, err
+	}
+	return &pubKeyID, nil
+}
+
+// forwardToPrimary passes the API errors returned by the primary commander through unchanged
+func (a *API) forwardToPrimary(result interface{}, method string, params ...interface{}) error {
+	err := a.primary.CallFor(result, method, params)
+	var rpcError *jsonrpc.RPCError
+	if errors.As(err, &rpcError) {
+		return &APIError{
+			Code:    rpcError.Code,
+			Message: rpcError.Message,
+			Data:    rpcError.Data,
+		}
+	}
+	if err != nil {
+		log.WithError(err).Warnf("Failed to forward %s to the primary commander", method)
+		return APIErrPrimaryUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/api/rpc"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/ybbus/jsonrpc/v2"
+)
+
+var forwardedTxHash = utils.RandomHash()
+
+type primaryHubbleAPI struct{}
+
+func (a *primaryHubbleAPI) SendTransaction(tx dto.Transaction) (*common.Hash, error) {
+	transfer, ok := tx.Parsed.(dto.Transfer)
+	if !ok {
+		return nil, APIErrAnyMissingField
+	}
+	if transfer.Nonce.CmpN(0) == 0 {
+		return nil, APIErrNonceTooLow
+	}
+	return &forwardedTxHash, nil
+}
+
+type ForwardToPrimaryTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	primary *httptest.Server
+	api     *API
+}
+
+func (s *ForwardToPrimaryTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ForwardToPrimaryTestSuite) SetupTest() {
+	server := rpc.NewServer()
+	err := server.RegisterName("hubble", &primaryHubbleAPI{})
+	s.NoError(err)
+
+	s.primary = httptest.NewServer(server)
+	s.api = &API{
+		isAcceptingTransactions: true,
+		primary:                 jsonrpc.NewClient(s.primary.URL),
+	}
+}
+
+func (s *ForwardToPrimaryTestSuite) TearDownTest() {
+	s.primary.Close()
+}
+
+func (s *ForwardToPrimaryTestSuite) TestSendTransaction_ForwardsToPrimary() {
+	hash, err := s.api.SendTransaction(context.Background(), dto.MakeTransaction(s.transfer(1)))
+	s.NoError(err)
+	s.Equal(forwardedTxHash, *hash)
+}
+
+func (s *ForwardToPrimaryTestSuite) TestSendTransaction_ReturnsAPIErrorOfPrimary() {
+	_, err := s.api.SendTransaction(context.Background(), dto.MakeTransaction(s.transfer(0)))
+	s.Equal(APIErrNonceTooLow, err)
+}
+
+func (s *ForwardToPrimaryTestSuite) TestSendTransaction_PrimaryUnavailable() {
+	s.primary.Close()
+
+	_, err := s.api.SendTransaction(context.Background(), dto.MakeTransaction(s.transfer(1)))
+	s.Equal(APIErrPrimaryUnavailable, err)
+}
+
+func (s *ForwardToPrimaryTestSuite) transfer(nonce uint64) dto.Transfer {
+	return dto.Transfer{
+		FromStateID: ref.Uint32(1),
+		ToStateID:   ref.Uint32(2),
+		Amount:      models.NewUint256(50),
+		Fee:         models.NewUint256(10),
+		Nonce:       models.NewUint256(nonce),
+		Signature:   &models.Signature{1, 2, 3},
+	}
+}
+
+func TestForwardToPrimaryTestSuite(t *testing.T) {
+	suite.Run(t, new(ForwardToPrimaryTestSuite))
+}
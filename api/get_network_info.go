@@ -42,6 +42,12 @@ func (a *API) unsafeGetNetworkInfo() (*dto.NetworkInfo, error) {
 	}
 	networkInfo.AccountCount = *accountCount
 
+	syncedBlock, err := a.storage.GetSyncedBlock()
+	if err != nil {
+		return nil, err
+	}
+	networkInfo.SyncedBlock = *syncedBlock
+
 	latestBatch, err := a.storage.GetLatestSubmittedBatch()
 	if err != nil && !storage.IsNotFoundError(err) {
 		return nil, err
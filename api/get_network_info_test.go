@@ -121,6 +121,8 @@ func (s *NetworkInfoTestSuite) TestGetNetworkInfo() {
 	s.NoError(err)
 
 	s.api.storage.SetLatestBlockNumber(1)
+	err = s.api.storage.SetSyncedBlock(1)
+	s.NoError(err)
 
 	networkInfo, err := s.api.GetNetworkInfo()
 	s.NoError(err)
@@ -132,6 +134,7 @@ func (s *NetworkInfoTestSuite) TestGetNetworkInfo() {
 	s.Equal(s.testClient.ChainState.DepositManager, networkInfo.DepositManager)
 	s.Equal(s.testClient.ChainState.Rollup, networkInfo.Rollup)
 	s.EqualValues(1, networkInfo.BlockNumber)
+	s.EqualValues(1, networkInfo.SyncedBlock)
 	s.EqualValues(1, networkInfo.TransactionCount)
 	s.EqualValues(0, networkInfo.AccountCount)
 	s.Equal(models.NewUint256(2000), networkInfo.LatestBatch)
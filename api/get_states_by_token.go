@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+)
+
+const maxStatesPageSize = 1000
+
+// GetStatesByToken returns a page of batched user states of the token ordered by state ID. The NextCursor of the
+// result is passed as cursor to get the next page, it is nil on the last one.
+func (a *API) GetStatesByToken(tokenID models.Uint256, cursor, limit *uint32) (*dto.UserStatesPage, error) {
+	page, err := a.unsafeGetStatesByToken(tokenID, cursor, limit)
+	if err != nil {
+		return nil, sanitizeCommonError(err, commonErrors)
+	}
+
+	return page, nil
+}
+
+func (a *API) unsafeGetStatesByToken(tokenID models.Uint256, cursor, limit *uint32) (*dto.UserStatesPage, error) {
+	startStateID := uint32(0)
+	if cursor != nil {
+		startStateID = *cursor
+	}
+
+	leaves, nextStateID, err := a.storage.GetStateLeavesByTokenID(tokenID, startStateID, statesPageSize(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserStatesPage{
+		States:     makeUserStatesWithID(leaves),
+		NextCursor: nextStateID,
+	}, nil
+}
+
+func statesPageSize(limit *uint32) uint32 {
+	if limit == nil || *limit == 0 || *limit > maxStatesPageSize {
+		return maxStatesPageSize
+	}
+	return *limit
+}
+
+func makeUserStatesWithID(leaves []models.StateLeaf) []dto.UserStateWithID {
+	userStates := make([]dto.UserStateWithID, 0, len(leaves))
+	for i := range leaves {
+		userStates = append(userStates, dto.MakeUserStateWithID(leaves[i].StateID, &leaves[i].UserState))
+	}
+	return userStates
+}
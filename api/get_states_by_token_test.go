@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetStatesByTokenTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api      *API
+	teardown func() error
+}
+
+func (s *GetStatesByTokenTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *GetStatesByTokenTestSuite) SetupTest() {
+	testStorage, err := st.NewTestStorage()
+	s.NoError(err)
+	s.teardown = testStorage.Teardown
+	s.api = NewTestAPI(testStorage.Storage, eth.DomainOnlyTestClient)
+
+	for stateID := uint32(0); stateID < 5; stateID++ {
+		_, err = testStorage.StateTree.Set(stateID, &models.UserState{
+			PubKeyID: stateID,
+			TokenID:  models.MakeUint256(uint64(stateID % 2)),
+			Balance:  models.MakeUint256(100),
+			Nonce:    models.MakeUint256(0),
+		})
+		s.NoError(err)
+	}
+}
+
+func (s *GetStatesByTokenTestSuite) TearDownTest() {
+	err := s.teardown()
+	s.NoError(err)
+}
+
+func (s *GetStatesByTokenTestSuite) TestGetStatesByToken_Paginates() {
+	page, err := s.api.GetStatesByToken(models.MakeUint256(0), nil, ref.Uint32(2))
+	s.NoError(err)
+	s.Len(page.States, 2)
+	s.EqualValues(0, page.States[0].StateID)
+	s.EqualValues(2, page.States[1].StateID)
+	s.EqualValues(4, *page.NextCursor)
+
+	page, err = s.api.GetStatesByToken(models.MakeUint256(0), page.NextCursor, ref.Uint32(2))
+	s.NoError(err)
+	s.Len(page.States, 1)
+	s.EqualValues(4, page.States[0].StateID)
+	s.Nil(page.NextCursor)
+}
+
+func (s *GetStatesByTokenTestSuite) TestGetStatesByToken_UnknownToken() {
+	page, err := s.api.GetStatesByToken(models.MakeUint256(7), nil, nil)
+	s.NoError(err)
+	s.Len(page.States, 0)
+	s.Nil(page.NextCursor)
+}
+
+func TestGetStatesByTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(GetStatesByTokenTestSuite))
+}
@@ -7,10 +7,10 @@ import (
 	"github.com/Worldcoin/hubble-commander/encoder"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchstatus"
 	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
 	"github.com/Worldcoin/hubble-commander/storage"
 	"github.com/Worldcoin/hubble-commander/utils/merkletree"
-	"github.com/Worldcoin/hubble-commander/utils/ref"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 )
@@ -62,6 +62,26 @@ func (a *API) unsafeGetWithdrawProof(
 		return nil, errors.WithStack(ErrOnlyMassMigrationBatches)
 	}
 
+	withdrawTree, err := a.getWithdrawTree(batch, commitmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	massMigrationIndex := withdrawTree.indexOf(transactionHash)
+	if massMigrationIndex == nil {
+		return nil, errors.WithStack(ErrMassMigrationWithTxHashNotFound)
+	}
+
+	return withdrawTree.proof(*massMigrationIndex), nil
+}
+
+// getWithdrawTree returns the withdraw tree of a given mass migration commitment.
+// Trees of finalised batches are cached as they can no longer change.
+func (a *API) getWithdrawTree(batch *models.Batch, commitmentID models.CommitmentID) (*withdrawTree, error) {
+	if tree := a.withdrawTrees.get(commitmentID); tree != nil {
+		return tree, nil
+	}
+
 	unsortedTransactions, err := a.storage.GetTransactionsByCommitmentID(commitmentID)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -73,72 +93,54 @@ func (a *API) unsafeGetWithdrawProof(
 	txQueue := executor.NewTxQueue(unsortedTransactions)
 	massMigrations := txQueue.PickTxsForCommitment().ToMassMigrationArray()
 
-	withdrawTree, targetUserState, massMigrationIndex, err := a.generateWithdrawTreeForWithdrawProof(massMigrations, transactionHash)
+	tree, err := a.generateWithdrawTree(massMigrations)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.WithdrawProof{
-		UserState: targetUserState,
-		Path: dto.MerklePath{
-			Path:  *massMigrationIndex,
-			Depth: withdrawTree.Depth(),
-		},
-		Witness: withdrawTree.GetWitness(*massMigrationIndex),
-		Root:    withdrawTree.Root(),
-	}, nil
+	if *calculateBatchStatus(a.storage.GetLatestBlockNumber(), batch) == batchstatus.Finalised {
+		a.withdrawTrees.add(commitmentID, tree)
+	}
+	return tree, nil
 }
 
-func (a *API) generateWithdrawTreeForWithdrawProof(
-	massMigrations []models.MassMigration,
-	transactionHash common.Hash,
-) (
-	withdrawTree *merkletree.MerkleTree,
-	targetUserState *dto.UserState,
-	massMigrationIndex *uint32,
-	err error,
-) {
+func (a *API) generateWithdrawTree(massMigrations []models.MassMigration) (*withdrawTree, error) {
 	tokenID := models.MakeUint256(0)
 	hashes := make([]common.Hash, 0, len(massMigrations))
+	userStates := make([]models.UserState, 0, len(massMigrations))
 
 	for i := range massMigrations {
-		var senderLeaf *models.StateLeaf
-		senderLeaf, err = a.storage.StateTree.Leaf(massMigrations[i].FromStateID)
+		senderLeaf, err := a.storage.StateTree.Leaf(massMigrations[i].FromStateID)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		}
 		if i == 0 {
 			tokenID = senderLeaf.TokenID
 		}
 
-		massMigrationUserState := &models.UserState{
+		massMigrationUserState := models.UserState{
 			PubKeyID: senderLeaf.PubKeyID,
 			TokenID:  tokenID,
 			Balance:  massMigrations[i].Amount,
 			Nonce:    models.MakeUint256(0),
 		}
 
-		var hash *common.Hash
-		hash, err = encoder.HashUserState(massMigrationUserState)
+		hash, err := encoder.HashUserState(&massMigrationUserState)
 		if err != nil {
-			return nil, nil, nil, errors.WithStack(err)
+			return nil, errors.WithStack(err)
 		}
 		hashes = append(hashes, *hash)
-
-		if massMigrations[i].Hash == transactionHash {
-			dtoMassMigrationUserState := dto.MakeUserState(massMigrationUserState)
-			targetUserState = &dtoMassMigrationUserState
-			massMigrationIndex = ref.Uint32(uint32(i))
-		}
-	}
-	if targetUserState == nil {
-		return nil, nil, nil, errors.WithStack(ErrMassMigrationWithTxHashNotFound)
+		userStates = append(userStates, massMigrationUserState)
 	}
 
-	withdrawTree, err = merkletree.NewMerkleTree(hashes)
+	tree, err := merkletree.NewMerkleTree(hashes)
 	if err != nil {
-		return nil, nil, nil, errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	return withdrawTree, targetUserState, massMigrationIndex, nil
+	return &withdrawTree{
+		tree:           tree,
+		massMigrations: massMigrations,
+		userStates:     userStates,
+	}, nil
 }
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchstatus"
+	"github.com/Worldcoin/hubble-commander/storage"
+	"github.com/pkg/errors"
+)
+
+var APIErrCannotGenerateWithdrawProofsByPublicKey = NewAPIError(
+	50010,
+	"withdraw proofs could not be calculated for a given public key",
+)
+
+var getWithdrawProofsByPublicKeyAPIErrors = map[error]*APIError{
+	storage.AnyNotFoundError: APIErrCannotGenerateWithdrawProofsByPublicKey,
+}
+
+// GetWithdrawProofsByPublicKey returns claimTokens arguments for all mass migrations
+// from finalised batches which were sent from states owned by the given public key
+// and were not claimed yet.
+// The BLS signature of the recipient address still has to be provided by the caller.
+func (a *API) GetWithdrawProofsByPublicKey(publicKey *models.PublicKey) ([]dto.WithdrawClaim, error) {
+	if !a.cfg.EnableProofMethods {
+		return nil, APIErrProofMethodsDisabled
+	}
+	claims, err := a.unsafeGetWithdrawProofsByPublicKey(publicKey)
+	if err != nil {
+		return nil, sanitizeError(err, getWithdrawProofsByPublicKeyAPIErrors)
+	}
+	return claims, nil
+}
+
+func (a *API) unsafeGetWithdrawProofsByPublicKey(publicKey *models.PublicKey) ([]dto.WithdrawClaim, error) {
+	claims := make([]dto.WithdrawClaim, 0)
+
+	leaves, err := a.storage.GetStateLeavesByPublicKey(publicKey)
+	if storage.IsNotFoundError(err) {
+		return claims, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	massMigrations, err := a.getMassMigrationsFromStates(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlockNumber := a.storage.GetLatestBlockNumber()
+	batches := make(map[models.Uint256]*models.Batch)
+	for i := range massMigrations {
+		batchID := massMigrations[i].Slot.BatchID
+		batch, ok := batches[batchID]
+		if !ok {
+			batch, err = a.storage.GetBatch(batchID)
+			if err != nil {
+				return nil, err
+			}
+			batches[batchID] = batch
+		}
+		if *calculateBatchStatus(latestBlockNumber, batch) != batchstatus.Finalised {
+			continue
+		}
+
+		var claim *dto.WithdrawClaim
+		claim, err = a.getUnclaimedWithdrawClaim(batch, &massMigrations[i])
+		if err != nil {
+			return nil, err
+		}
+		if claim != nil {
+			claims = append(claims, *claim)
+		}
+	}
+	return claims, nil
+}
+
+// getMassMigrationsFromStates returns the batched mass migrations sent from the states ordered by slot
+func (a *API) getMassMigrationsFromStates(leaves []models.StateLeaf) ([]storage.IndexedMassMigration, error) {
+	massMigrations := make([]storage.IndexedMassMigration, 0)
+	for i := range leaves {
+		stateMassMigrations, err := a.storage.GetBatchedMassMigrationsByFromStateID(leaves[i].StateID)
+		if err != nil {
+			return nil, err
+		}
+		massMigrations = append(massMigrations, stateMassMigrations...)
+	}
+
+	sort.Slice(massMigrations, func(i, j int) bool {
+		return bytes.Compare(massMigrations[i].Slot.Bytes(), massMigrations[j].Slot.Bytes()) < 0
+	})
+	return massMigrations, nil
+}
+
+// getUnclaimedWithdrawClaim returns nil when the tokens of the mass migration were already claimed on chain
+func (a *API) getUnclaimedWithdrawClaim(
+	batch *models.Batch,
+	massMigration *storage.IndexedMassMigration,
+) (*dto.WithdrawClaim, error) {
+	commitmentID := *massMigration.Slot.CommitmentID()
+	withdrawTree, err := a.getWithdrawTree(batch, commitmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := withdrawTree.indexOf(massMigration.Hash)
+	if index == nil {
+		return nil, errors.WithStack(storage.NewNotFoundError("mass migration"))
+	}
+
+	proof := withdrawTree.proof(*index)
+	claimed, err := a.client.IsWithdrawClaimed(proof.Root, *index)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	publicKeyProof, err := a.unsafeGetPublicKeyProofByPubKeyID(proof.UserState.PubKeyID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &dto.WithdrawClaim{
+		CommitmentID:     commitmentID,
+		TransactionHash:  massMigration.Hash,
+		WithdrawProof:    *proof,
+		PublicKey:        publicKeyProof.PublicKey,
+		PublicKeyWitness: publicKeyProof.Witness,
+	}, nil
+}
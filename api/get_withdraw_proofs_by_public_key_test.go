@@ -0,0 +1,174 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetWithdrawProofsByPublicKeyTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api            *API
+	storage        *st.TestStorage
+	client         *eth.TestClient
+	massMigrations []models.MassMigration
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.client, err = eth.NewTestClient()
+	s.NoError(err)
+	s.api = &API{
+		storage:       s.storage.Storage,
+		client:        s.client.Client,
+		cfg:           &config.APIConfig{EnableProofMethods: true},
+		withdrawTrees: newWithdrawTreeCache(),
+	}
+
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{PubKeyID: 1, PublicKey: models.PublicKey{1, 2, 3}})
+	s.NoError(err)
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{PubKeyID: 2, PublicKey: models.PublicKey{2, 3, 4}})
+	s.NoError(err)
+
+	for stateID, pubKeyID := range []uint32{1, 2, 1} {
+		_, err = s.storage.StateTree.Set(uint32(stateID), &models.UserState{
+			PubKeyID: pubKeyID,
+			TokenID:  models.MakeUint256(10),
+			Balance:  models.MakeUint256(100),
+			Nonce:    models.MakeUint256(1),
+		})
+		s.NoError(err)
+	}
+
+	s.massMigrations = []models.MassMigration{
+		s.makeMassMigration(common.Hash{1}, 0, 1, 0),
+		s.makeMassMigration(common.Hash{2}, 1, 1, 1),
+		s.makeMassMigration(common.Hash{3}, 2, 1, 2),
+		s.makeMassMigration(common.Hash{4}, 0, 2, 0),
+	}
+	s.addMassMigrationBatch(1, ref.Uint32(5), s.massMigrations[:3])
+	s.addMassMigrationBatch(2, ref.Uint32(50), s.massMigrations[3:])
+
+	s.storage.SetLatestBlockNumber(10)
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) TestGetWithdrawProofsByPublicKey_ReturnsClaimsFromFinalisedBatches() {
+	claims, err := s.api.GetWithdrawProofsByPublicKey(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+	s.Len(claims, 2)
+
+	commitmentID := models.CommitmentID{BatchID: models.MakeUint256(1), IndexInBatch: 0}
+	for i, massMigration := range []models.MassMigration{s.massMigrations[0], s.massMigrations[2]} {
+		expectedProof, err := s.api.GetWithdrawProof(commitmentID, massMigration.Hash)
+		s.NoError(err)
+
+		publicKeyProof, err := s.api.GetPublicKeyProofByPubKeyID(1)
+		s.NoError(err)
+
+		s.Equal(commitmentID, claims[i].CommitmentID)
+		s.Equal(massMigration.Hash, claims[i].TransactionHash)
+		s.Equal(*expectedProof, claims[i].WithdrawProof)
+		s.Equal(publicKeyProof.PublicKey, claims[i].PublicKey)
+		s.Equal(publicKeyProof.Witness, claims[i].PublicKeyWitness)
+	}
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) TestGetWithdrawProofsByPublicKey_UnknownPublicKey() {
+	claims, err := s.api.GetWithdrawProofsByPublicKey(&models.PublicKey{9, 9, 9})
+	s.NoError(err)
+	s.Len(claims, 0)
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) TestGetWithdrawProofsByPublicKey_ProofMethodsDisabled() {
+	s.api.cfg.EnableProofMethods = false
+
+	_, err := s.api.GetWithdrawProofsByPublicKey(&models.PublicKey{1, 2, 3})
+	s.Equal(APIErrProofMethodsDisabled, err)
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) TestGetWithdrawProofsByPublicKey_CachesOnlyFinalisedWithdrawTrees() {
+	_, err := s.api.GetWithdrawProofsByPublicKey(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+	_, err = s.api.GetWithdrawProof(models.CommitmentID{BatchID: models.MakeUint256(2)}, s.massMigrations[3].Hash)
+	s.NoError(err)
+
+	s.NotNil(s.api.withdrawTrees.get(models.CommitmentID{BatchID: models.MakeUint256(1)}))
+	s.Nil(s.api.withdrawTrees.get(models.CommitmentID{BatchID: models.MakeUint256(2)}))
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) makeMassMigration(
+	hash common.Hash,
+	from uint32,
+	batchID uint64,
+	indexInCommitment uint8,
+) models.MassMigration {
+	return makeMassMigration(
+		hash,
+		from,
+		1,
+		models.NewTimestamp(time.Unix(140, 0).UTC()),
+		models.CommitmentSlot{
+			BatchID:           models.MakeUint256(batchID),
+			IndexInBatch:      0,
+			IndexInCommitment: indexInCommitment,
+		},
+	)
+}
+
+func (s *GetWithdrawProofsByPublicKeyTestSuite) addMassMigrationBatch(
+	batchID uint64,
+	finalisationBlock *uint32,
+	massMigrations []models.MassMigration,
+) {
+	err := s.storage.BatchAddTransaction(models.MakeMassMigrationArray(massMigrations...))
+	s.NoError(err)
+
+	stateRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+
+	commitment := makeMassMigrationCommitment(
+		s.Assertions,
+		s.storage,
+		models.CommitmentID{BatchID: models.MakeUint256(batchID)},
+		1,
+		*stateRoot,
+		utils.RandomHash(),
+		massMigrations,
+	)
+	err = s.storage.AddCommitment(&commitment)
+	s.NoError(err)
+
+	err = s.storage.AddBatch(&models.Batch{
+		ID:                models.MakeUint256(batchID),
+		Type:              batchtype.MassMigration,
+		FinalisationBlock: finalisationBlock,
+	})
+	s.NoError(err)
+}
+
+func TestGetWithdrawProofsByPublicKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(GetWithdrawProofsByPublicKeyTestSuite))
+}
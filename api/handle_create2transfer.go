@@ -14,7 +14,11 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func (a *API) handleCreate2Transfer(ctx context.Context, create2TransferDTO dto.Create2Transfer) (*common.Hash, error) {
+func (a *API) handleCreate2Transfer(
+	ctx context.Context,
+	create2TransferDTO dto.Create2Transfer,
+	signatureVerified bool,
+) (*common.Hash, error) {
 	create2Transfer, err := sanitizeCreate2Transfer(create2TransferDTO)
 	if err != nil {
 		a.countRejectedTx(txtype.Create2Transfer)
@@ -55,7 +59,7 @@ func (a *API) handleCreate2Transfer(ctx context.Context, create2TransferDTO dto.
 			mockSignature = nil
 		}
 
-		if innerErr := validateCreate2Transfer(txStorage, create2Transfer, signatureDomain, mockSignature); innerErr != nil {
+		if innerErr := validateCreate2Transfer(txStorage, create2Transfer, signatureDomain, mockSignature, signatureVerified); innerErr != nil {
 			a.countRejectedTx(txtype.Create2Transfer)
 			return innerErr
 		}
@@ -110,6 +114,7 @@ func validateCreate2Transfer(
 	create2Transfer *models.Create2Transfer,
 	signatureDomain *bls.Domain,
 	mockSignature *models.Signature,
+	signatureVerified bool,
 ) error {
 	if vErr := validateAmount(&create2Transfer.Amount); vErr != nil {
 		return vErr
@@ -140,6 +145,9 @@ func validateCreate2Transfer(
 		create2Transfer.Signature = *mockSignature
 		return nil
 	}
+	if signatureVerified {
+		return nil
+	}
 
 	return validateSignature(
 		txStorage,
@@ -11,7 +11,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-func (a *API) handleMassMigration(massMigrationDTO dto.MassMigration) (*common.Hash, error) {
+func (a *API) handleMassMigration(massMigrationDTO dto.MassMigration, signatureVerified bool) (*common.Hash, error) {
 	massMigration, err := sanitizeMassMigration(massMigrationDTO)
 	if err != nil {
 		a.countRejectedTx(txtype.MassMigration)
@@ -48,7 +48,7 @@ func (a *API) handleMassMigration(massMigrationDTO dto.MassMigration) (*common.H
 		}
 
 		// TODO: this needs to read from txStorage, so we need to refactor?
-		if innerErr := validateMassMigration(txStorage, massMigration, signatureDomain, mockSignature); innerErr != nil {
+		if innerErr := validateMassMigration(txStorage, massMigration, signatureDomain, mockSignature, signatureVerified); innerErr != nil {
 			a.countRejectedTx(massMigration.TxType)
 			return innerErr
 		}
@@ -104,6 +104,7 @@ func validateMassMigration(
 	massMigration *models.MassMigration,
 	signatureDomain *bls.Domain,
 	mockSignature *models.Signature,
+	signatureVerified bool,
 ) error {
 	if vErr := validateAmount(&massMigration.Amount); vErr != nil {
 		return vErr
@@ -135,6 +136,9 @@ func validateMassMigration(
 		massMigration.Signature = *mockSignature
 		return nil
 	}
+	if signatureVerified {
+		return nil
+	}
 
 	return validateSignature(
 		txStorage,
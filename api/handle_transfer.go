@@ -16,7 +16,7 @@ import (
 )
 
 // TODO: this is functionally exactly the same as handleC2T and handleMM, merge them
-func (a *API) handleTransfer(ctx context.Context, transferDTO dto.Transfer) (*common.Hash, error) {
+func (a *API) handleTransfer(ctx context.Context, transferDTO dto.Transfer, signatureVerified bool) (*common.Hash, error) {
 	transfer, err := sanitizeTransfer(transferDTO)
 	if err != nil {
 		a.countRejectedTx(txtype.Transfer)
@@ -62,7 +62,7 @@ func (a *API) handleTransfer(ctx context.Context, transferDTO dto.Transfer) (*co
 			mockSignature = nil
 		}
 
-		if innerErr := validateTransfer(txStorage, transfer, signatureDomain, mockSignature); innerErr != nil {
+		if innerErr := validateTransfer(txStorage, transfer, signatureDomain, mockSignature, signatureVerified); innerErr != nil {
 			a.countRejectedTx(txtype.Transfer)
 			return innerErr
 		}
@@ -118,6 +118,7 @@ func validateTransfer(
 	transfer *models.Transfer,
 	signatureDomain *bls.Domain,
 	mockSignature *models.Signature,
+	signatureVerified bool,
 ) error {
 	if vErr := validateAmount(&transfer.Amount); vErr != nil {
 		return vErr
@@ -168,6 +169,9 @@ func validateTransfer(
 		transfer.Signature = *mockSignature
 		return nil
 	}
+	if signatureVerified {
+		return nil
+	}
 
 	return validateSignature(
 		txStorage,
@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/commander/leader"
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/eth"
 	"github.com/Worldcoin/hubble-commander/metrics"
@@ -230,6 +232,13 @@ func (s *SendTransferTestSuite) TestSendTransaction_DoesNotAcceptTransactions()
 	s.Equal(APIErrSendTxMethodDisabled, err)
 }
 
+func (s *SendTransferTestSuite) TestSendTransaction_StandbyDoesNotAcceptTransactions() {
+	// an elector which has not acquired the lease yet
+	s.api.elector = leader.NewElectorWithLock(nil, "standby", 5*time.Second, time.Second)
+	_, err := s.api.SendTransaction(context.Background(), dto.MakeTransaction(s.transfer))
+	s.Equal(APIErrSendTxMethodDisabled, err)
+}
+
 func (s *SendTransferTestSuite) TestSendTransaction_SendsTxToTxPool() {
 	hash, err := s.api.SendTransaction(context.Background(), dto.MakeTransaction(s.transfer))
 	s.NoError(err)
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/commander/registrar"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/storage"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidProofOfPossession = fmt.Errorf("invalid proof of possession")
+
+	APIErrRegistrationDisabled = NewAPIError(
+		99005,
+		"public key registration is disabled",
+	)
+	APIErrRegistrationRateLimitExceeded = NewAPIError(
+		99006,
+		"public key registration rate limit exceeded, try again later",
+	)
+	APIErrPublicKeyNotAllowed = NewAPIError(
+		99007,
+		"public key is not allowed to be registered",
+	)
+	APIErrInvalidProofOfPossession = NewAPIError(
+		99008,
+		"invalid proof of possession",
+	)
+	APIErrRegistrationNotProcessed = NewAPIError(
+		99010,
+		"public key registrations are not processed by this commander right now, try again later",
+	)
+)
+
+var registerPublicKeyAPIErrors = map[error]*APIError{
+	registrar.ErrRegistrationDisabled: APIErrRegistrationDisabled,
+	registrar.ErrRateLimitExceeded:    APIErrRegistrationRateLimitExceeded,
+	registrar.ErrPublicKeyNotAllowed:  APIErrPublicKeyNotAllowed,
+	registrar.ErrNotFlushing:          APIErrRegistrationNotProcessed,
+	ErrInvalidProofOfPossession:       APIErrInvalidProofOfPossession,
+}
+
+// RegisterPublicKey queues a registration of the public key in the AccountRegistry and
+// waits until the registration transaction is mined. The proof of possession is
+// a signature of the public key bytes created with the matching private key.
+func (a *API) RegisterPublicKey(
+	ctx context.Context,
+	publicKey models.PublicKey,
+	proofOfPossession models.Signature,
+) (*uint32, error) {
+	if a.primary != nil {
+		return a.forwardRegisterPublicKey(&publicKey, &proofOfPossession)
+	}
+	pubKeyID, err := a.unsafeRegisterPublicKey(ctx, &publicKey, &proofOfPossession)
+	if err != nil {
+		return nil, sanitizeError(err, registerPublicKeyAPIErrors)
+	}
+	return pubKeyID, nil
+}
+
+func (a *API) unsafeRegisterPublicKey(
+	ctx context.Context,
+	publicKey *models.PublicKey,
+	proofOfPossession *models.Signature,
+) (*uint32, error) {
+	if a.registrar == nil {
+		return nil, errors.WithStack(registrar.ErrRegistrationDisabled)
+	}
+
+	err := a.validateProofOfPossession(publicKey, proofOfPossession)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeyID, err := a.storage.GetFirstPubKeyID(publicKey)
+	if err == nil {
+		return pubKeyID, nil
+	}
+	if !storage.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	registration, err := a.registrar.Register(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return registration.Wait(ctx)
+}
+
+func (a *API) validateProofOfPossession(publicKey *models.PublicKey, proofOfPossession *models.Signature) error {
+	if a.disableSignatures {
+		return nil
+	}
+
+	domain, err := a.client.GetDomain()
+	if err != nil {
+		return err
+	}
+
+	signature, err := bls.NewSignatureFromBytes(proofOfPossession.Bytes(), *domain)
+	if err != nil {
+		return errors.WithStack(ErrInvalidProofOfPossession)
+	}
+
+	isValid, err := signature.Verify(publicKey.Bytes(), publicKey)
+	if err != nil || !isValid {
+		return errors.WithStack(ErrInvalidProofOfPossession)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/commander/registrar"
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegisterPublicKeyTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+	wallet  *bls.Wallet
+}
+
+func (s *RegisterPublicKeyTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *RegisterPublicKeyTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+
+	s.api = &API{
+		cfg:     &config.APIConfig{},
+		storage: s.storage.Storage,
+		client:  eth.DomainOnlyTestClient,
+		registrar: registrar.NewRegistrar(&config.RegistrationConfig{
+			Enabled:        true,
+			RateLimitBurst: 1,
+		}),
+	}
+	s.api.registrar.SetFlushing(true)
+
+	domain, err := s.api.client.GetDomain()
+	s.NoError(err)
+	s.wallet, err = bls.NewRandomWallet(*domain)
+	s.NoError(err)
+}
+
+func (s *RegisterPublicKeyTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *RegisterPublicKeyTestSuite) TestRegisterPublicKey_ReturnsExistingPubKeyID() {
+	err := s.storage.AccountTree.SetSingle(&models.AccountLeaf{
+		PubKeyID:  5,
+		PublicKey: *s.wallet.PublicKey(),
+	})
+	s.NoError(err)
+
+	pubKeyID, err := s.api.RegisterPublicKey(context.Background(), *s.wallet.PublicKey(), s.proofOfPossession())
+	s.NoError(err)
+	s.EqualValues(5, *pubKeyID)
+	s.Equal(0, s.api.registrar.PendingCount())
+}
+
+func (s *RegisterPublicKeyTestSuite) TestRegisterPublicKey_QueuesRegistration() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.api.RegisterPublicKey(ctx, *s.wallet.PublicKey(), s.proofOfPossession())
+	s.Error(err)
+	s.Equal(1, s.api.registrar.PendingCount())
+}
+
+func (s *RegisterPublicKeyTestSuite) TestRegisterPublicKey_InvalidProofOfPossession() {
+	signature, err := s.wallet.Sign([]byte{1, 2, 3})
+	s.NoError(err)
+
+	_, err = s.api.RegisterPublicKey(context.Background(), *s.wallet.PublicKey(), *signature.ModelsSignature())
+	s.Equal(APIErrInvalidProofOfPossession, err)
+}
+
+func (s *RegisterPublicKeyTestSuite) TestRegisterPublicKey_RegistrationDisabled() {
+	s.api.registrar = nil
+
+	_, err := s.api.RegisterPublicKey(context.Background(), *s.wallet.PublicKey(), s.proofOfPossession())
+	s.Equal(APIErrRegistrationDisabled, err)
+}
+
+func (s *RegisterPublicKeyTestSuite) TestRegisterPublicKey_NotProcessedWhileRollupLoopIsStopped() {
+	s.api.registrar.SetFlushing(false)
+
+	_, err := s.api.RegisterPublicKey(context.Background(), *s.wallet.PublicKey(), s.proofOfPossession())
+	s.Equal(APIErrRegistrationNotProcessed, err)
+}
+
+func (s *RegisterPublicKeyTestSuite) proofOfPossession() models.Signature {
+	signature, err := s.wallet.Sign(s.wallet.PublicKey().Bytes())
+	s.NoError(err)
+	return *signature.ModelsSignature()
+}
+
+func TestRegisterPublicKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterPublicKeyTestSuite))
+}
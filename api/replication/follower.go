@@ -0,0 +1,139 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils/consts"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrUnexpectedPrimaryResponse = fmt.Errorf("unexpected response of the primary commander")
+	ErrNotSynced                 = fmt.Errorf("replica is not synced with the primary commander")
+)
+
+// Follower keeps the local database in sync with the database of the primary commander
+type Follower struct {
+	url     string
+	authKey string
+	storage *st.Storage
+	client  *http.Client
+
+	body    io.ReadCloser
+	decoder *gob.Decoder
+}
+
+func NewFollower(primaryURL, authenticationKey string, storage *st.Storage) *Follower {
+	return &Follower{
+		url:     strings.TrimSuffix(primaryURL, "/") + Endpoint,
+		authKey: authenticationKey,
+		storage: storage,
+		client:  &http.Client{},
+	}
+}
+
+// Sync connects to the primary and overwrites the local database with its snapshot
+func (f *Follower) Sync(ctx context.Context) error {
+	f.Close()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, http.NoBody)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	request.Header.Set(consts.AuthKeyHeader, f.authKey)
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return errors.WithMessagef(ErrUnexpectedPrimaryResponse, "status %d", response.StatusCode)
+	}
+	f.body = response.Body
+	f.decoder = gob.NewDecoder(response.Body)
+
+	err = f.applySnapshot()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.storage.RefreshTransactionCount()
+}
+
+func (f *Follower) applySnapshot() error {
+	writer := f.storage.NewSnapshotWriter()
+	keys := 0
+	for {
+		var frame Frame
+		err := f.decoder.Decode(&frame)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if frame.SnapshotDone {
+			log.Infof("Replicated snapshot of %d keys from %s", keys, f.url)
+			return writer.Finish()
+		}
+
+		err = writer.Write(frame.Changes)
+		if err != nil {
+			return err
+		}
+		keys += len(frame.Changes)
+	}
+}
+
+// Follow applies the changes of the primary until the connection gets closed, Sync must be called first
+func (f *Follower) Follow() error {
+	if f.decoder == nil {
+		return errors.WithStack(ErrNotSynced)
+	}
+
+	for {
+		var frame Frame
+		err := f.decoder.Decode(&frame)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		err = f.storage.ApplyChanges(frame.Changes)
+		if err != nil {
+			return err
+		}
+		if !changesBatches(frame.Changes) {
+			continue
+		}
+		err = f.storage.RefreshTransactionCount()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// changesBatches tells whether the batched transactions count could have changed
+func changesBatches(changes []db.Change) bool {
+	for i := range changes {
+		if bytes.HasPrefix(changes[i].Key, stored.BatchPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Follower) Close() {
+	if f.body != nil {
+		_ = f.body.Close()
+	}
+	f.body = nil
+	f.decoder = nil
+}
@@ -0,0 +1,60 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/gob"
+	"net/http"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/db"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils/consts"
+	log "github.com/sirupsen/logrus"
+)
+
+// Endpoint is the path the API server streams the database on
+const Endpoint = "/replication"
+
+// Frame is a single gob encoded message of the replication stream
+type Frame struct {
+	Changes      []db.Change
+	SnapshotDone bool
+}
+
+// NewHandler streams a snapshot of the database followed by its changes to replicas.
+// Like the admin methods it requires the authentication key, it's disabled when the key isn't configured.
+func NewHandler(cfg *config.APIConfig, storage *st.Storage) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authKey := r.Header.Get(consts.AuthKeyHeader)
+		if authKey == "" || authKey != cfg.AuthenticationKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+
+		writer := bufio.NewWriter(w)
+		encoder := gob.NewEncoder(writer)
+		log.Infof("Replica %s connected", r.RemoteAddr)
+
+		err := storage.StreamChanges(r.Context(), func(changes []db.Change, snapshotDone bool) error {
+			err := encoder.Encode(&Frame{Changes: changes, SnapshotDone: snapshotDone})
+			if err != nil {
+				return err
+			}
+			err = writer.Flush()
+			if err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		log.WithError(err).Warnf("Replica %s disconnected", r.RemoteAddr)
+	})
+}
@@ -21,6 +21,13 @@ func NewInternalToAPIError(code int, message string, commanderErrors []error) *I
 	}
 }
 
+// APIErrDatabaseConflict is returned when the database transaction of a call was rolled back after a conflict
+// with a concurrent one, the call can be safely repeated
+var APIErrDatabaseConflict = NewAPIError(
+	40002,
+	"the database transaction conflicted with a concurrent one, try again",
+)
+
 var commonErrors = []*InternalToAPIError{
 	// Badger
 	NewInternalToAPIError(
@@ -33,6 +40,7 @@ var commonErrors = []*InternalToAPIError{
 		},
 	),
 	NewInternalToAPIError(40001, "an error occurred while iterating over Badger database", []error{db.ErrIteratorFinished}),
+	{apiError: APIErrDatabaseConflict, commanderErrors: []error{db.ErrConflict}},
 	// BLS
 	NewInternalToAPIError(99004, "an error occurred while fetching the domain for signing", []error{bls.ErrInvalidDomainLength}),
 }
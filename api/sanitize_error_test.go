@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/storage"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -128,3 +129,8 @@ func TestSanitizeCommonError(t *testing.T) {
 	apiError = sanitizeCommonError(fmt.Errorf("ducks"), testCommonErrors)
 	require.Equal(t, expectedUnknownError, *apiError)
 }
+
+func TestSanitizeError_DatabaseConflict(t *testing.T) {
+	apiError := sanitizeError(errors.WithStack(db.ErrConflict), sendTransactionAPIErrors)
+	require.Equal(t, APIErrDatabaseConflict, apiError)
+}
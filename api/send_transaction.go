@@ -119,11 +119,14 @@ var sendTransactionAPIErrors = map[error]*APIError{
 }
 
 func (a *API) SendTransaction(ctx context.Context, tx dto.Transaction) (*common.Hash, error) {
-	if !a.isAcceptingTransactions {
+	if a.primary != nil {
+		return a.forwardSendTransaction(tx)
+	}
+	if !a.isAcceptingTxs() {
 		return nil, sanitizeError(ErrSendTxMethodDisabled, sendTransactionAPIErrors)
 	}
 
-	transactionHash, err := a.unsafeSendTransaction(ctx, tx)
+	transactionHash, err := a.unsafeSendTransaction(ctx, tx, false)
 	if err != nil {
 		return nil, sanitizeError(err, sendTransactionAPIErrors)
 	}
@@ -131,14 +134,16 @@ func (a *API) SendTransaction(ctx context.Context, tx dto.Transaction) (*common.
 	return transactionHash, nil
 }
 
-func (a *API) unsafeSendTransaction(ctx context.Context, tx dto.Transaction) (*common.Hash, error) {
+// unsafeSendTransaction skips the signature check when signatureVerified is set by the batched verification of
+// hubble_sendTransactions
+func (a *API) unsafeSendTransaction(ctx context.Context, tx dto.Transaction, signatureVerified bool) (*common.Hash, error) {
 	switch t := tx.Parsed.(type) {
 	case dto.Transfer:
-		return a.handleTransfer(ctx, t)
+		return a.handleTransfer(ctx, t, signatureVerified)
 	case dto.Create2Transfer:
-		return a.handleCreate2Transfer(ctx, t)
+		return a.handleCreate2Transfer(ctx, t, signatureVerified)
 	case dto.MassMigration:
-		return a.handleMassMigration(t)
+		return a.handleMassMigration(t, signatureVerified)
 	default:
 		return nil, errors.WithStack(ErrUnsupportedTxType)
 	}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/encoder"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxBulkTransactions = 1000
+
+var APIErrTooManyTransactions = NewAPIError(
+	10018,
+	"too many transactions, at most 1000 can be sent at once",
+)
+
+// SendTransactions adds the transactions to the mempool one after another, the result of each of them is returned
+// at its index. With api.batch_signature_verification the signatures are checked all at once beforehand.
+func (a *API) SendTransactions(ctx context.Context, txs []dto.Transaction) ([]dto.SendTransactionResult, error) {
+	if len(txs) > maxBulkTransactions {
+		return nil, APIErrTooManyTransactions
+	}
+	if a.primary != nil {
+		return a.forwardSendTransactions(txs)
+	}
+	if !a.isAcceptingTxs() {
+		return nil, sanitizeError(ErrSendTxMethodDisabled, sendTransactionAPIErrors)
+	}
+
+	signaturesVerified := make([]bool, len(txs))
+	if a.cfg.BatchSignatureVerification && !a.disableSignatures {
+		signaturesVerified = a.batchVerifySignatures(txs)
+	}
+
+	results := make([]dto.SendTransactionResult, 0, len(txs))
+	for i := range txs {
+		transactionHash, err := a.unsafeSendTransaction(ctx, txs[i], signaturesVerified[i])
+		if err != nil {
+			apiErr := sanitizeError(err, sendTransactionAPIErrors)
+			results = append(results, dto.SendTransactionResult{
+				Error: &dto.SendTransactionError{Code: apiErr.Code, Message: apiErr.Message},
+			})
+			continue
+		}
+		results = append(results, dto.SendTransactionResult{Hash: transactionHash})
+	}
+	return results, nil
+}
+
+// batchVerifySignatures tells which of the signatures passed a batched check. When any of them is invalid none
+// of them is marked as verified and they are checked one by one, which also finds out which one is invalid.
+func (a *API) batchVerifySignatures(txs []dto.Transaction) []bool {
+	verified := make([]bool, len(txs))
+
+	domain, err := a.client.GetDomain()
+	if err != nil {
+		return verified
+	}
+
+	indices := make([]int, 0, len(txs))
+	signatures := make([]*bls.Signature, 0, len(txs))
+	messages := make([][]byte, 0, len(txs))
+	publicKeys := make([]*models.PublicKey, 0, len(txs))
+	for i := range txs {
+		fromStateID, message, signature, err := signedMessage(txs[i])
+		if err != nil {
+			continue
+		}
+		publicKey, err := a.storage.GetPublicKeyByStateID(fromStateID)
+		if err != nil {
+			continue
+		}
+		blsSignature, err := bls.NewSignatureFromBytes(signature.Bytes(), *domain)
+		if err != nil {
+			continue
+		}
+
+		indices = append(indices, i)
+		signatures = append(signatures, blsSignature)
+		messages = append(messages, message)
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	isValid, err := bls.VerifyBatch(*domain, signatures, messages, publicKeys)
+	if err != nil {
+		log.WithError(err).Warn("Batched signature verification failed")
+		return verified
+	}
+	if !isValid {
+		return verified
+	}
+	for _, i := range indices {
+		verified[i] = true
+	}
+	return verified
+}
+
+func signedMessage(tx dto.Transaction) (fromStateID uint32, message []byte, signature *models.Signature, err error) {
+	switch t := tx.Parsed.(type) {
+	case dto.Transfer:
+		transfer, sanitizeErr := sanitizeTransfer(t)
+		if sanitizeErr != nil {
+			return 0, nil, nil, sanitizeErr
+		}
+		message, err = encoder.EncodeTransferForSigning(transfer)
+		return transfer.FromStateID, message, &transfer.Signature, err
+	case dto.Create2Transfer:
+		create2Transfer, sanitizeErr := sanitizeCreate2Transfer(t)
+		if sanitizeErr != nil {
+			return 0, nil, nil, sanitizeErr
+		}
+		message, err = encoder.EncodeCreate2TransferForSigning(create2Transfer)
+		return create2Transfer.FromStateID, message, &create2Transfer.Signature, err
+	case dto.MassMigration:
+		massMigration, sanitizeErr := sanitizeMassMigration(t)
+		if sanitizeErr != nil {
+			return 0, nil, nil, sanitizeErr
+		}
+		return massMigration.FromStateID, encoder.EncodeMassMigrationForSigning(massMigration), &massMigration.Signature, nil
+	default:
+		return 0, nil, nil, ErrUnsupportedTxType
+	}
+}
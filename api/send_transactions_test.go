@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/metrics"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SendTransactionsTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	api     *API
+	storage *st.TestStorage
+	wallet  *bls.Wallet
+}
+
+func (s *SendTransactionsTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *SendTransactionsTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.api = &API{
+		cfg:                     &config.APIConfig{BatchSignatureVerification: true},
+		storage:                 s.storage.Storage,
+		client:                  eth.DomainOnlyTestClient,
+		commanderMetrics:        metrics.NewCommanderMetrics(),
+		isAcceptingTransactions: true,
+	}
+
+	domain, err := s.api.client.GetDomain()
+	s.NoError(err)
+	s.wallet, err = bls.NewRandomWallet(*domain)
+	s.NoError(err)
+
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{
+		PubKeyID:  123,
+		PublicKey: *s.wallet.PublicKey(),
+	})
+	s.NoError(err)
+
+	for stateID := uint32(1); stateID <= 2; stateID++ {
+		_, err = s.storage.StateTree.Set(stateID, &models.UserState{
+			PubKeyID: 123,
+			TokenID:  models.MakeUint256(1),
+			Balance:  models.MakeUint256(420),
+			Nonce:    models.MakeUint256(0),
+		})
+		s.NoError(err)
+	}
+}
+
+func (s *SendTransactionsTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *SendTransactionsTestSuite) TestSendTransactions_VerifiesSignaturesInBatch() {
+	txs := []dto.Transaction{s.signedTransfer(0), s.signedTransfer(1), s.signedTransfer(2)}
+
+	s.Equal([]bool{true, true, true}, s.api.batchVerifySignatures(txs))
+
+	results, err := s.api.SendTransactions(context.Background(), txs)
+	s.NoError(err)
+	s.Len(results, 3)
+	for i := range results {
+		s.Nil(results[i].Error)
+		s.NotNil(results[i].Hash)
+	}
+}
+
+func (s *SendTransactionsTestSuite) TestSendTransactions_InvalidSignature() {
+	invalidTransfer := s.signedTransfer(1)
+	transfer := invalidTransfer.Parsed.(dto.Transfer)
+	transfer.Amount = models.NewUint256(1)
+	invalidTransfer.Parsed = transfer
+
+	txs := []dto.Transaction{s.signedTransfer(0), invalidTransfer, s.signedTransfer(1)}
+
+	s.Equal([]bool{false, false, false}, s.api.batchVerifySignatures(txs))
+
+	results, err := s.api.SendTransactions(context.Background(), txs)
+	s.NoError(err)
+	s.Len(results, 3)
+	s.NotNil(results[0].Hash)
+	s.Nil(results[1].Hash)
+	s.Equal(APIErrInvalidSignature.Code, results[1].Error.Code)
+	s.NotNil(results[2].Hash)
+}
+
+func (s *SendTransactionsTestSuite) TestSendTransactions_TooManyTransactions() {
+	_, err := s.api.SendTransactions(context.Background(), make([]dto.Transaction, maxBulkTransactions+1))
+	s.Equal(APIErrTooManyTransactions, err)
+}
+
+func (s *SendTransactionsTestSuite) signedTransfer(nonce uint64) dto.Transaction {
+	transfer := transferWithoutSignature
+	transfer.Nonce = models.NewUint256(nonce)
+	signedTransfer, err := SignTransfer(s.wallet, transfer)
+	s.NoError(err)
+	return dto.MakeTransaction(*signedTransfer)
+}
+
+func TestSendTransactionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SendTransactionsTestSuite))
+}
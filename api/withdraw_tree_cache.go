@@ -0,0 +1,72 @@
+package api
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/utils/merkletree"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const withdrawTreeCacheSize = 1024
+
+type withdrawTree struct {
+	tree           *merkletree.MerkleTree
+	massMigrations []models.MassMigration
+	userStates     []models.UserState
+}
+
+func (t *withdrawTree) indexOf(massMigrationHash common.Hash) *uint32 {
+	for i := range t.massMigrations {
+		if t.massMigrations[i].Hash == massMigrationHash {
+			return ref.Uint32(uint32(i))
+		}
+	}
+	return nil
+}
+
+func (t *withdrawTree) proof(massMigrationIndex uint32) *dto.WithdrawProof {
+	userState := dto.MakeUserState(&t.userStates[massMigrationIndex])
+	return &dto.WithdrawProof{
+		UserState: &userState,
+		Path: dto.MerklePath{
+			Path:  massMigrationIndex,
+			Depth: t.tree.Depth(),
+		},
+		Witness: t.tree.GetWitness(massMigrationIndex),
+		Root:    t.tree.Root(),
+	}
+}
+
+// withdrawTreeCache stores withdraw trees of finalised commitments. Finalised commitments
+// can no longer be reverted so their withdraw trees never change.
+type withdrawTreeCache struct {
+	trees *lru.Cache
+}
+
+func newWithdrawTreeCache() *withdrawTreeCache {
+	trees, err := lru.New(withdrawTreeCacheSize)
+	if err != nil {
+		panic(err) // lru.New only fails for non-positive sizes
+	}
+	return &withdrawTreeCache{trees: trees}
+}
+
+func (c *withdrawTreeCache) get(commitmentID models.CommitmentID) *withdrawTree {
+	if c == nil {
+		return nil
+	}
+	tree, ok := c.trees.Get(commitmentID)
+	if !ok {
+		return nil
+	}
+	return tree.(*withdrawTree)
+}
+
+func (c *withdrawTreeCache) add(commitmentID models.CommitmentID, tree *withdrawTree) {
+	if c == nil {
+		return
+	}
+	c.trees.Add(commitmentID, tree)
+}
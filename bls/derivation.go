@@ -0,0 +1,141 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/kilic/bn254"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The keys are derived as specified by EIP-2333 (https://eips.ethereum.org/EIPS/eip-2333), except that the secret
+// keys are reduced modulo the order of BN254 instead of BLS12-381. The derivation paths follow EIP-2334.
+
+// DefaultDerivationPath is the path of the first key of the mnemonic used by the hubble tools
+const DefaultDerivationPath = "m/12381/60/0/0"
+
+const (
+	lamportChunks    = 255
+	lamportChunkSize = sha256.Size
+	// ceil((3 * ceil(log2(r))) / 16) for the 254 bits of the BN254 order
+	hkdfModROutputSize = 48
+	minSeedSize        = 32
+)
+
+var (
+	ErrSeedTooShort          = fmt.Errorf("seed must be at least %d bytes long", minSeedSize)
+	ErrInvalidDerivationPath = fmt.Errorf("invalid derivation path")
+
+	hkdfModRSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+)
+
+// DeriveMasterSecretKey derives the root key of the tree of keys from a seed, e.g. the one of a mnemonic
+func DeriveMasterSecretKey(seed []byte) ([32]byte, error) {
+	if len(seed) < minSeedSize {
+		return [32]byte{}, ErrSeedTooShort
+	}
+	return secretKeyBytes(hkdfModR(seed, bn254.Order)), nil
+}
+
+// DeriveChildSecretKey derives the key at the index from its parent key
+func DeriveChildSecretKey(parentSecretKey [32]byte, index uint32) [32]byte {
+	return secretKeyBytes(deriveChild(new(big.Int).SetBytes(parentSecretKey[:]), index, bn254.Order))
+}
+
+// DerivePath derives the key at a path like m/12381/60/0/0 from a seed
+func DerivePath(seed []byte, path string) ([32]byte, error) {
+	indices, err := parseDerivationPath(path)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	secretKey, err := DeriveMasterSecretKey(seed)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	for _, index := range indices {
+		secretKey = DeriveChildSecretKey(secretKey, index)
+	}
+	return secretKey, nil
+}
+
+func parseDerivationPath(path string) ([]uint32, error) {
+	nodes := strings.Split(strings.TrimSpace(path), "/")
+	if nodes[0] != "m" {
+		return nil, fmt.Errorf("%w: %s must start with m", ErrInvalidDerivationPath, path)
+	}
+
+	indices := make([]uint32, 0, len(nodes)-1)
+	for _, node := range nodes[1:] {
+		index, err := strconv.ParseUint(node, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDerivationPath, path)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+func deriveChild(parentSecretKey *big.Int, index uint32, order *big.Int) *big.Int {
+	return hkdfModR(parentSecretKeyToLamportPublicKey(parentSecretKey, index), order)
+}
+
+func hkdfModR(ikm []byte, order *big.Int) *big.Int {
+	salt := hkdfModRSalt
+	secretKey := new(big.Int)
+	for secretKey.Sign() == 0 {
+		hashedSalt := sha256.Sum256(salt)
+		salt = hashedSalt[:]
+
+		prk := hkdf.Extract(sha256.New, append(append([]byte{}, ikm...), 0), salt)
+		okm := make([]byte, hkdfModROutputSize)
+		_, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte{0, hkdfModROutputSize}), okm)
+		if err != nil {
+			panic(err) // the output size is far below the limit of HKDF
+		}
+		secretKey.Mod(secretKey.SetBytes(okm), order)
+	}
+	return secretKey
+}
+
+func parentSecretKeyToLamportPublicKey(parentSecretKey *big.Int, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+
+	ikm := make([]byte, 32)
+	parentSecretKey.FillBytes(ikm)
+	notIKM := make([]byte, 32)
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+
+	lamportPublicKey := sha256.New()
+	for _, lamportIKM := range [][]byte{ikm, notIKM} {
+		lamportSecretKey := ikmToLamportSecretKey(lamportIKM, salt)
+		for i := 0; i < lamportChunks; i++ {
+			chunkHash := sha256.Sum256(lamportSecretKey[i*lamportChunkSize : (i+1)*lamportChunkSize])
+			lamportPublicKey.Write(chunkHash[:])
+		}
+	}
+	return lamportPublicKey.Sum(nil)
+}
+
+func ikmToLamportSecretKey(ikm, salt []byte) []byte {
+	okm := make([]byte, lamportChunks*lamportChunkSize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm)
+	if err != nil {
+		panic(err) // 255 chunks of the hash size is exactly the limit of HKDF
+	}
+	return okm
+}
+
+func secretKeyBytes(secretKey *big.Int) [32]byte {
+	var bytes [32]byte
+	secretKey.FillBytes(bytes[:])
+	return bytes
+}
@@ -0,0 +1,100 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors of EIP-2333, which uses the order of BLS12-381
+var (
+	bls12381Order, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+	eip2333TestVectors = []struct {
+		seed       string
+		masterSK   string
+		childIndex uint32
+		childSK    string
+	}{
+		{
+			seed: "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630" +
+				"c7a3c4ab7c81b2f001698e7463b04",
+			masterSK:   "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			childIndex: 0,
+			childSK:    "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			seed:       "3141592653589793238462643383279502884197169399375105820974944592",
+			masterSK:   "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			childIndex: 3141592653,
+			childSK:    "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			seed:       "0099ff991111002299dd7744ee3355bbdd8844115566cc55663355668888cc00",
+			masterSK:   "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			childIndex: 4294967295,
+			childSK:    "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+	}
+)
+
+func TestDerivation_EIP2333TestVectors(t *testing.T) {
+	for _, vector := range eip2333TestVectors {
+		seed, err := hex.DecodeString(vector.seed)
+		require.NoError(t, err)
+
+		masterSK := hkdfModR(seed, bls12381Order)
+		require.Equal(t, vector.masterSK, masterSK.String())
+
+		childSK := deriveChild(masterSK, vector.childIndex, bls12381Order)
+		require.Equal(t, vector.childSK, childSK.String())
+	}
+}
+
+func TestDeriveMasterSecretKey(t *testing.T) {
+	seed, err := hex.DecodeString(eip2333TestVectors[1].seed)
+	require.NoError(t, err)
+
+	masterSK, err := DeriveMasterSecretKey(seed)
+	require.NoError(t, err)
+	require.Equal(t, "0081cf400b32ac6d04679e703b44f76dbc3b7846d57b263de3ccfa1fc95f630e", hex.EncodeToString(masterSK[:]))
+
+	childSK := DeriveChildSecretKey(masterSK, eip2333TestVectors[1].childIndex)
+	require.Equal(t, "04fa5f7a29ec93304fb60c326452f09827c77ab0ae1f2b05ed3e7b57967f61eb", hex.EncodeToString(childSK[:]))
+}
+
+func TestDeriveMasterSecretKey_SeedTooShort(t *testing.T) {
+	_, err := DeriveMasterSecretKey(make([]byte, 31))
+	require.ErrorIs(t, err, ErrSeedTooShort)
+}
+
+func TestDerivePath(t *testing.T) {
+	seed, err := hex.DecodeString(eip2333TestVectors[0].seed)
+	require.NoError(t, err)
+
+	secretKey, err := DerivePath(seed, "m/12381/60/0/0")
+	require.NoError(t, err)
+
+	expected, err := DeriveMasterSecretKey(seed)
+	require.NoError(t, err)
+	for _, index := range []uint32{12381, 60, 0, 0} {
+		expected = DeriveChildSecretKey(expected, index)
+	}
+	require.Equal(t, expected, secretKey)
+
+	masterSK, err := DerivePath(seed, "m")
+	require.NoError(t, err)
+	expected, err = DeriveMasterSecretKey(seed)
+	require.NoError(t, err)
+	require.Equal(t, expected, masterSK)
+}
+
+func TestDerivePath_InvalidPath(t *testing.T) {
+	seed := make([]byte, 32)
+	for _, path := range []string{"", "12381/60", "m/12381/-1", "m/12381/4294967296", "m//0"} {
+		_, err := DerivePath(seed, path)
+		require.ErrorIs(t, err, ErrInvalidDerivationPath, path)
+	}
+}
@@ -0,0 +1,65 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+const keyFileVersion = 3
+
+var (
+	ErrInvalidKeyPassword   = fmt.Errorf("invalid key file password")
+	ErrKeyFileVersion       = fmt.Errorf("unsupported key file version")
+	ErrKeyFilePublicKeyDiff = fmt.Errorf("public key of the key file does not match its private key")
+)
+
+// KeyFile holds a private key encrypted with a password like the Web3 Secret Storage key files of Ethereum,
+// the public key is stored in plain text to identify the key without the password
+type KeyFile struct {
+	PublicKey models.PublicKey
+	Crypto    keystore.CryptoJSON
+	Version   int
+}
+
+// EncryptKey encrypts the private key of the wallet with the scrypt parameters, e.g. keystore.StandardScryptN
+// and keystore.StandardScryptP
+func EncryptKey(wallet *Wallet, password string, scryptN, scryptP int) (*KeyFile, error) {
+	privateKey, _ := wallet.Bytes()
+	cryptoJSON, err := keystore.EncryptDataV3(privateKey, []byte(password), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyFile{
+		PublicKey: *wallet.PublicKey(),
+		Crypto:    cryptoJSON,
+		Version:   keyFileVersion,
+	}, nil
+}
+
+// DecryptKey creates the wallet of the encrypted private key
+func DecryptKey(keyFile *KeyFile, password string, domain Domain) (*Wallet, error) {
+	if keyFile.Version != keyFileVersion {
+		return nil, ErrKeyFileVersion
+	}
+
+	privateKey, err := keystore.DecryptDataV3(keyFile.Crypto, password)
+	if errors.Is(err, keystore.ErrDecrypt) {
+		return nil, ErrInvalidKeyPassword
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := NewWallet(privateKey, domain)
+	if err != nil {
+		return nil, err
+	}
+	if *wallet.PublicKey() != keyFile.PublicKey {
+		return nil, ErrKeyFilePublicKeyDiff
+	}
+	return wallet, nil
+}
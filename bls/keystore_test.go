@@ -0,0 +1,55 @@
+package bls
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptKey(t *testing.T) {
+	wallet, err := NewRandomWallet(TestDomain)
+	require.NoError(t, err)
+
+	keyFile, err := EncryptKey(wallet, "password", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	require.Equal(t, *wallet.PublicKey(), keyFile.PublicKey)
+
+	encodedKeyFile, err := json.Marshal(keyFile)
+	require.NoError(t, err)
+	var decodedKeyFile KeyFile
+	err = json.Unmarshal(encodedKeyFile, &decodedKeyFile)
+	require.NoError(t, err)
+
+	decryptedWallet, err := DecryptKey(&decodedKeyFile, "password", TestDomain)
+	require.NoError(t, err)
+	privateKey, _ := wallet.Bytes()
+	decryptedPrivateKey, _ := decryptedWallet.Bytes()
+	require.Equal(t, privateKey, decryptedPrivateKey)
+}
+
+func TestDecryptKey_InvalidPassword(t *testing.T) {
+	wallet, err := NewRandomWallet(TestDomain)
+	require.NoError(t, err)
+
+	keyFile, err := EncryptKey(wallet, "password", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	_, err = DecryptKey(keyFile, "other password", TestDomain)
+	require.ErrorIs(t, err, ErrInvalidKeyPassword)
+}
+
+func TestDecryptKey_PublicKeyDiffers(t *testing.T) {
+	wallet, err := NewRandomWallet(TestDomain)
+	require.NoError(t, err)
+	otherWallet, err := NewRandomWallet(TestDomain)
+	require.NoError(t, err)
+
+	keyFile, err := EncryptKey(wallet, "password", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	keyFile.PublicKey = *otherWallet.PublicKey()
+
+	_, err = DecryptKey(keyFile, "password", TestDomain)
+	require.ErrorIs(t, err, ErrKeyFilePublicKeyDiff)
+}
@@ -0,0 +1,57 @@
+package bls
+
+import (
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// mnemonicEntropyBits gives 24 word mnemonics
+const mnemonicEntropyBits = 256
+
+var ErrInvalidMnemonic = fmt.Errorf("invalid mnemonic")
+
+// NewMnemonic generates a random BIP-39 mnemonic of 24 english words
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// MnemonicToSeed returns the BIP-39 seed of the mnemonic, the passphrase may be empty
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMnemonic, err.Error())
+	}
+	return seed, nil
+}
+
+// NewWalletFromMnemonic creates the wallet of the key derived from the mnemonic along the path,
+// see DefaultDerivationPath
+func NewWalletFromMnemonic(mnemonic, passphrase, path string, domain Domain) (*Wallet, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := DerivePath(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewWallet(secretKey[:], domain)
+}
+
+// DeriveChild creates the wallet of the child key at the index
+func (w *Wallet) DeriveChild(index uint32) (*Wallet, error) {
+	privateKey, _ := w.Bytes()
+
+	var parentSecretKey [32]byte
+	copy(parentSecretKey[:], privateKey)
+	childSecretKey := DeriveChildSecretKey(parentSecretKey, index)
+	return NewWallet(childSecretKey[:], w.Domain())
+}
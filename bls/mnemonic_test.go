@@ -0,0 +1,68 @@
+package bls
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(mnemonic), 24)
+
+	_, err = MnemonicToSeed(mnemonic, "")
+	require.NoError(t, err)
+}
+
+func TestMnemonicToSeed(t *testing.T) {
+	// test vector of BIP-39, also used by EIP-2333
+	seed, err := MnemonicToSeed(testMnemonic, "TREZOR")
+	require.NoError(t, err)
+	require.Equal(t, eip2333TestVectors[0].seed, hex.EncodeToString(seed))
+}
+
+func TestMnemonicToSeed_InvalidMnemonic(t *testing.T) {
+	_, err := MnemonicToSeed(strings.Replace(testMnemonic, "about", "abandon", 1), "")
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+
+	_, err = MnemonicToSeed("not a mnemonic", "")
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
+
+func TestNewWalletFromMnemonic(t *testing.T) {
+	wallet, err := NewWalletFromMnemonic(testMnemonic, "TREZOR", DefaultDerivationPath, TestDomain)
+	require.NoError(t, err)
+
+	privateKey, _ := wallet.Bytes()
+	require.Equal(t, "15cba5d454809f0e8a17b9c23073f90eeda0eaf43dfb13fc935353624df8553e", hex.EncodeToString(privateKey))
+	require.Equal(
+		t,
+		"11b0bd1de4a3272d9ef78a9ca13cef9040c6bab82c0ad8f38d1b0d19d1d07fe606e6555792713c01bab3c493b9a111905b7ec9ebf73f099548f433e735880fe9"+
+			"288d520d2c506a8a28106c1e3bee5a36d7414bfe3467550d9e42712e866bae68041e8bbde5b2cc5ef0fcda6b5330de59a43a0c3809f47504cde77fa2ac09b428",
+		hex.EncodeToString(wallet.PublicKey().Bytes()),
+	)
+}
+
+func TestWallet_DeriveChild(t *testing.T) {
+	parent, err := NewWalletFromMnemonic(testMnemonic, "", "m/12381/60/0", TestDomain)
+	require.NoError(t, err)
+
+	child, err := parent.DeriveChild(0)
+	require.NoError(t, err)
+	expected, err := NewWalletFromMnemonic(testMnemonic, "", "m/12381/60/0/0", TestDomain)
+	require.NoError(t, err)
+	require.Equal(t, expected.PublicKey(), child.PublicKey())
+	require.Equal(t, TestDomain, child.Domain())
+
+	data := []byte{1, 2, 3}
+	signature, err := child.Sign(data)
+	require.NoError(t, err)
+	isValid, err := signature.Verify(data, expected.PublicKey())
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
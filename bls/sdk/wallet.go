@@ -2,7 +2,9 @@ package HubbleSDK //nolint:stylecheck
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"math"
 	"math/big"
 
 	"github.com/Worldcoin/hubble-commander/api"
@@ -72,6 +74,47 @@ func NewWalletPrivateKey() (string, error) {
 	return hex.EncodeToString(privateKey), nil
 }
 
+//export NewMnemonic
+func NewMnemonic() (string, error) {
+	return bls.NewMnemonic()
+}
+
+// NewWalletFromMnemonic returns the private key derived from the mnemonic along the path,
+// an empty path stands for bls.DefaultDerivationPath
+//export NewWalletFromMnemonic
+func NewWalletFromMnemonic(mnemonic, passphrase, path string) (string, error) {
+	if path == "" {
+		path = bls.DefaultDerivationPath
+	}
+	wallet, err := bls.NewWalletFromMnemonic(mnemonic, passphrase, path, placeholderDomain)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, _ := wallet.Bytes()
+	return hex.EncodeToString(privateKey), nil
+}
+
+// DeriveChild returns the private key of the child at the index of the given private key
+//export DeriveChild
+func DeriveChild(privateKey string, index int) (string, error) {
+	if index < 0 || int64(index) > math.MaxUint32 {
+		return "", errors.New("child index out of range")
+	}
+	wallet, err := parseWallet(privateKey, &placeholderDomain)
+	if err != nil {
+		return "", err
+	}
+
+	child, err := wallet.DeriveChild(uint32(index))
+	if err != nil {
+		return "", err
+	}
+
+	childPrivateKey, _ := child.Bytes()
+	return hex.EncodeToString(childPrivateKey), nil
+}
+
 //export GetWalletPublicKey
 func GetWalletPublicKey(privateKey string) (string, error) {
 	wallet, err := parseWallet(privateKey, &placeholderDomain)
@@ -166,6 +209,127 @@ func SignCreate2Transfer(from int, toPubKey, amount, fee, nonce, privateKey, dom
 	return hex.EncodeToString(transfer.Signature.Bytes()), nil
 }
 
+//export SignMassMigration
+func SignMassMigration(from, spokeID int, amount, fee, nonce, privateKey, domain string) (string, error) {
+	domainBls, err := parseDomain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	wallet, err := parseWallet(privateKey, domainBls)
+	if err != nil {
+		return "", err
+	}
+
+	amountUint256, err := parseUint256(amount)
+	if err != nil {
+		return "", err
+	}
+	feeUint256, err := parseUint256(fee)
+	if err != nil {
+		return "", err
+	}
+	nonceUint256, err := parseUint256(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	massMigration, err := api.SignMassMigration(wallet, dto.MassMigration{
+		FromStateID: ref.Uint32(uint32(from)),
+		SpokeID:     ref.Uint32(uint32(spokeID)),
+		Amount:      amountUint256,
+		Fee:         feeUint256,
+		Nonce:       nonceUint256,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(massMigration.Signature.Bytes()), nil
+}
+
+// SignTransactionJSON signs a transaction in the JSON format of hubble_sendTransaction,
+// any signature it already has gets replaced
+//export SignTransactionJSON
+func SignTransactionJSON(txJSON, privateKey, domain string) (string, error) {
+	domainBls, err := parseDomain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	wallet, err := parseWallet(privateKey, domainBls)
+	if err != nil {
+		return "", err
+	}
+
+	var tx dto.Transaction
+	err = json.Unmarshal([]byte(txJSON), &tx)
+	if err != nil {
+		return "", err
+	}
+
+	signedTx, err := signTransaction(wallet, tx.Parsed)
+	if err != nil {
+		return "", err
+	}
+
+	signedJSON, err := json.Marshal(signedTx)
+	if err != nil {
+		return "", err
+	}
+	return string(signedJSON), nil
+}
+
+func signTransaction(wallet *bls.Wallet, tx interface{}) (interface{}, error) {
+	switch tx := tx.(type) {
+	case dto.Transfer:
+		if tx.ToStateID == nil {
+			return nil, api.NewMissingFieldError("toStateID")
+		}
+		err := checkCommonFields(tx.FromStateID, tx.Amount, tx.Fee, tx.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		return api.SignTransfer(wallet, tx)
+	case dto.Create2Transfer:
+		if tx.ToPublicKey == nil {
+			return nil, api.NewMissingFieldError("publicKey")
+		}
+		err := checkCommonFields(tx.FromStateID, tx.Amount, tx.Fee, tx.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		return api.SignCreate2Transfer(wallet, tx)
+	case dto.MassMigration:
+		if tx.SpokeID == nil {
+			return nil, api.NewMissingFieldError("spokeID")
+		}
+		err := checkCommonFields(tx.FromStateID, tx.Amount, tx.Fee, tx.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		return api.SignMassMigration(wallet, tx)
+	default:
+		return nil, dto.ErrNotImplemented
+	}
+}
+
+func checkCommonFields(fromStateID *uint32, amount, fee, nonce *models.Uint256) error {
+	if fromStateID == nil {
+		return api.NewMissingFieldError("fromStateID")
+	}
+	if amount == nil {
+		return api.NewMissingFieldError("amount")
+	}
+	if fee == nil {
+		return api.NewMissingFieldError("fee")
+	}
+	if nonce == nil {
+		return api.NewMissingFieldError("nonce")
+	}
+	return nil
+}
+
 //export SignMessage
 func SignMessage(message, privateKey, domain string) (string, error) {
 	domainBls, err := parseDomain(domain)
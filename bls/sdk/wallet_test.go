@@ -0,0 +1,64 @@
+package HubbleSDK //nolint:stylecheck
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/api"
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/encoder"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignTransactionJSON_MassMigration(t *testing.T) {
+	privateKey, err := NewWalletPrivateKey()
+	require.NoError(t, err)
+	domain := hex.EncodeToString(bls.TestDomain.Bytes())
+
+	txJSON := `{"Type":"MASS_MIGRATION","FromStateID":1,"SpokeID":2,"Amount":"100","Fee":"10","Nonce":"3"}`
+	signedJSON, err := SignTransactionJSON(txJSON, privateKey, domain)
+	require.NoError(t, err)
+
+	var tx dto.Transaction
+	err = json.Unmarshal([]byte(signedJSON), &tx)
+	require.NoError(t, err)
+	massMigration, ok := tx.Parsed.(dto.MassMigration)
+	require.True(t, ok)
+	require.NotNil(t, massMigration.Signature)
+
+	encodedMassMigration := encoder.EncodeMassMigrationForSigning(&models.MassMigration{
+		TransactionBase: models.TransactionBase{
+			FromStateID: 1,
+			Amount:      models.MakeUint256(100),
+			Fee:         models.MakeUint256(10),
+			Nonce:       models.MakeUint256(3),
+		},
+		SpokeID: 2,
+	})
+
+	signature, err := bls.NewSignatureFromBytes(massMigration.Signature.Bytes(), bls.TestDomain)
+	require.NoError(t, err)
+
+	privateKeyBytes, err := hex.DecodeString(privateKey)
+	require.NoError(t, err)
+	wallet, err := bls.NewWallet(privateKeyBytes, bls.TestDomain)
+	require.NoError(t, err)
+
+	isValid, err := signature.Verify(encodedMassMigration, wallet.PublicKey())
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestSignTransactionJSON_MassMigrationMissingSpokeID(t *testing.T) {
+	privateKey, err := NewWalletPrivateKey()
+	require.NoError(t, err)
+	domain := hex.EncodeToString(bls.TestDomain.Bytes())
+
+	txJSON := `{"Type":"MASS_MIGRATION","FromStateID":1,"Amount":"100","Fee":"10","Nonce":"3"}`
+	_, err = SignTransactionJSON(txJSON, privateKey, domain)
+	require.ErrorIs(t, err, api.AnyMissingFieldError)
+	require.Equal(t, api.NewMissingFieldError("spokeID").Error(), err.Error())
+}
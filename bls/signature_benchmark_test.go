@@ -0,0 +1,124 @@
+package bls
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/stretchr/testify/require"
+)
+
+// The benchmarks compare the ways of checking the signatures of a commitment of 32 transactions, and the signatures
+// of a batch of 8 such commitments, run them with `go test -run ^$ -bench . ./bls/`
+
+const (
+	benchmarkTxsPerCommitment = 32
+	benchmarkCommitments      = 8
+)
+
+func BenchmarkSignature_Verify(b *testing.B) {
+	signatures, messages, publicKeys := signedMessages(b, 1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		isValid, err := signatures[0].Verify(messages[0], publicKeys[0])
+		require.NoError(b, err)
+		require.True(b, isValid)
+	}
+}
+
+func BenchmarkSignature_VerifyEach(b *testing.B) {
+	signatures, messages, publicKeys := signedMessages(b, benchmarkTxsPerCommitment)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range signatures {
+			isValid, err := signatures[j].Verify(messages[j], publicKeys[j])
+			require.NoError(b, err)
+			require.True(b, isValid)
+		}
+	}
+}
+
+func BenchmarkAggregatedSignature_Verify(b *testing.B) {
+	signatures, messages, publicKeys := signedMessages(b, benchmarkTxsPerCommitment)
+	aggregatedSignature := NewAggregatedSignature(signatures)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		isValid, err := aggregatedSignature.Verify(messages, publicKeys)
+		require.NoError(b, err)
+		require.True(b, isValid)
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	signatures, messages, publicKeys := signedMessages(b, benchmarkTxsPerCommitment)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		isValid, err := VerifyBatch(TestDomain, signatures, messages, publicKeys)
+		require.NoError(b, err)
+		require.True(b, isValid)
+	}
+}
+
+func BenchmarkAggregatedSignature_VerifyCommitments(b *testing.B) {
+	commitments := signedCommitments(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range commitments {
+			commitments[j].verify(b)
+		}
+	}
+}
+
+func BenchmarkAggregatedSignature_VerifyCommitmentsConcurrently(b *testing.B) {
+	commitments := signedCommitments(b)
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for j := range commitments {
+			workers <- struct{}{}
+			wg.Add(1)
+			go func(commitment *signedCommitment) {
+				defer func() {
+					<-workers
+					wg.Done()
+				}()
+				commitment.verify(b)
+			}(&commitments[j])
+		}
+		wg.Wait()
+	}
+}
+
+type signedCommitment struct {
+	signature  *AggregatedSignature
+	messages   [][]byte
+	publicKeys []*models.PublicKey
+}
+
+func (c *signedCommitment) verify(b *testing.B) {
+	isValid, err := c.signature.Verify(c.messages, c.publicKeys)
+	if err != nil || !isValid {
+		b.Errorf("invalid commitment signature: %v", err)
+	}
+}
+
+func signedCommitments(b *testing.B) []signedCommitment {
+	commitments := make([]signedCommitment, 0, benchmarkCommitments)
+	for i := 0; i < benchmarkCommitments; i++ {
+		signatures, messages, publicKeys := signedMessages(b, benchmarkTxsPerCommitment)
+		commitments = append(commitments, signedCommitment{
+			signature:  NewAggregatedSignature(signatures),
+			messages:   messages,
+			publicKeys: publicKeys,
+		})
+	}
+	return commitments
+}
@@ -0,0 +1,68 @@
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/kilic/bn254"
+)
+
+// batchWeightBits is the size of the random weights of VerifyBatch, an invalid batch passes with a probability of 2^-128
+const batchWeightBits = 128
+
+var (
+	ErrBatchLengthMismatch = fmt.Errorf("signatures, messages and public keys must have the same length")
+
+	maxBatchWeight = new(big.Int).Lsh(big.NewInt(1), batchWeightBits)
+)
+
+// VerifyBatch checks signatures of single messages with one multi-pairing instead of a pairing check per signature.
+// Unlike the verification of their aggregate, every signature is weighted with a random scalar, so invalid signatures
+// can't cancel each other out. It doesn't tell which of the signatures is invalid.
+func VerifyBatch(domain Domain, signatures []*Signature, messages [][]byte, publicKeys []*models.PublicKey) (bool, error) {
+	if len(signatures) != len(messages) || len(messages) != len(publicKeys) {
+		return false, ErrBatchLengthMismatch
+	}
+	if len(signatures) == 0 {
+		return true, nil
+	}
+
+	engine := bn254.NewEngine()
+	g1, g2 := engine.G1, engine.G2
+	weightedSignatures := g1.Zero()
+	for i := range signatures {
+		weight, err := randomBatchWeight()
+		if err != nil {
+			return false, err
+		}
+
+		signature, err := g1.FromBytes(signatures[i].Bytes())
+		if err != nil {
+			return false, err
+		}
+		g1.Add(weightedSignatures, weightedSignatures, g1.MulScalar(signature, signature, weight))
+
+		message, err := g1.HashToCurveFT(messages[i], domain[:])
+		if err != nil {
+			return false, err
+		}
+		publicKey, err := g2.FromBytes(toBLSPublicKey(publicKeys[i]).ToBytes())
+		if err != nil {
+			return false, err
+		}
+		engine.AddPair(g1.MulScalar(message, message, weight), publicKey)
+	}
+	engine.AddPairInv(weightedSignatures, g2.One())
+	return engine.Check(), nil
+}
+
+func randomBatchWeight() (*big.Int, error) {
+	weight, err := rand.Int(rand.Reader, maxBatchWeight)
+	if err != nil {
+		return nil, err
+	}
+	// a zero weight would drop the signature from the check
+	return weight.Add(weight, big.NewInt(1)), nil
+}
@@ -0,0 +1,79 @@
+package bls
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/kilic/bn254"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	signatures, messages, publicKeys := signedMessages(t, 3)
+
+	isValid, err := VerifyBatch(TestDomain, signatures, messages, publicKeys)
+	require.NoError(t, err)
+	require.True(t, isValid)
+}
+
+func TestVerifyBatch_InvalidSignature(t *testing.T) {
+	signatures, messages, publicKeys := signedMessages(t, 3)
+	messages[1] = []byte("0x444444")
+
+	isValid, err := VerifyBatch(TestDomain, signatures, messages, publicKeys)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestVerifyBatch_SignaturesCancellingOutInAggregate(t *testing.T) {
+	signatures, messages, publicKeys := signedMessages(t, 3)
+
+	g1 := bn254.NewG1()
+	first, err := g1.FromBytes(signatures[0].Bytes())
+	require.NoError(t, err)
+	second, err := g1.FromBytes(signatures[1].Bytes())
+	require.NoError(t, err)
+	offset, err := g1.FromBytes(signatures[2].Bytes())
+	require.NoError(t, err)
+	g1.Add(first, first, offset)
+	g1.Sub(second, second, offset)
+
+	signatures[0], err = NewSignatureFromBytes(g1.ToBytes(first), TestDomain)
+	require.NoError(t, err)
+	signatures[1], err = NewSignatureFromBytes(g1.ToBytes(second), TestDomain)
+	require.NoError(t, err)
+
+	isValid, err := NewAggregatedSignature(signatures).Verify(messages, publicKeys)
+	require.NoError(t, err)
+	require.True(t, isValid)
+
+	isValid, err = VerifyBatch(TestDomain, signatures, messages, publicKeys)
+	require.NoError(t, err)
+	require.False(t, isValid)
+}
+
+func TestVerifyBatch_LengthMismatch(t *testing.T) {
+	signatures, messages, publicKeys := signedMessages(t, 2)
+
+	_, err := VerifyBatch(TestDomain, signatures, messages[:1], publicKeys)
+	require.ErrorIs(t, err, ErrBatchLengthMismatch)
+}
+
+func signedMessages(t testing.TB, count int) (signatures []*Signature, messages [][]byte, publicKeys []*models.PublicKey) {
+	signatures = make([]*Signature, 0, count)
+	messages = make([][]byte, 0, count)
+	publicKeys = make([]*models.PublicKey, 0, count)
+	for i := 0; i < count; i++ {
+		wallet, err := NewRandomWallet(TestDomain)
+		require.NoError(t, err)
+
+		message := []byte{byte(i), 0xde, 0xad, 0xbe, 0xef}
+		signature, err := wallet.Sign(message)
+		require.NoError(t, err)
+
+		signatures = append(signatures, signature)
+		messages = append(messages, message)
+		publicKeys = append(publicKeys, wallet.PublicKey())
+	}
+	return signatures, messages, publicKeys
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NewWallet creates a wallet of the private key which signs for the network of the commander
+func (c *Client) NewWallet(privateKey []byte) (*bls.Wallet, error) {
+	networkInfo, err := c.GetNetworkInfo()
+	if err != nil {
+		return nil, err
+	}
+	return bls.NewWallet(privateKey, networkInfo.SignatureDomain)
+}
+
+// ProofOfPossession signs the public key of the wallet as required by hubble_registerPublicKey
+func ProofOfPossession(wallet *bls.Wallet) (*models.Signature, error) {
+	signature, err := wallet.Sign(wallet.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return signature.ModelsSignature(), nil
+}
+
+// RegisterWallet registers the public key of the wallet and returns its pubKeyID
+func (c *Client) RegisterWallet(wallet *bls.Wallet) (*uint32, error) {
+	proofOfPossession, err := ProofOfPossession(wallet)
+	if err != nil {
+		return nil, err
+	}
+	return c.RegisterPublicKey(wallet.PublicKey(), proofOfPossession)
+}
+
+// Account signs and sends transactions from a user state of the wallet. The nonce is read from the pending
+// state before the first transaction and then tracked locally, it is read again after a transaction fails.
+type Account struct {
+	client  *Client
+	wallet  *bls.Wallet
+	stateID uint32
+
+	mutex sync.Mutex
+	nonce *models.Uint256
+}
+
+func (c *Client) NewAccount(wallet *bls.Wallet, stateID uint32) *Account {
+	return &Account{
+		client:  c,
+		wallet:  wallet,
+		stateID: stateID,
+	}
+}
+
+func (a *Account) StateID() uint32 {
+	return a.stateID
+}
+
+func (a *Account) SendTransfer(toStateID uint32, amount, fee models.Uint256) (*common.Hash, error) {
+	return a.send(func(nonce *models.Uint256) (interface{}, error) {
+		return signTransfer(a.wallet, dto.Transfer{
+			FromStateID: &a.stateID,
+			ToStateID:   &toStateID,
+			Amount:      &amount,
+			Fee:         &fee,
+			Nonce:       nonce,
+		})
+	})
+}
+
+func (a *Account) SendCreate2Transfer(toPublicKey *models.PublicKey, amount, fee models.Uint256) (*common.Hash, error) {
+	return a.send(func(nonce *models.Uint256) (interface{}, error) {
+		return signCreate2Transfer(a.wallet, dto.Create2Transfer{
+			FromStateID: &a.stateID,
+			ToPublicKey: toPublicKey,
+			Amount:      &amount,
+			Fee:         &fee,
+			Nonce:       nonce,
+		})
+	})
+}
+
+func (a *Account) SendMassMigration(spokeID uint32, amount, fee models.Uint256) (*common.Hash, error) {
+	return a.send(func(nonce *models.Uint256) (interface{}, error) {
+		return signMassMigration(a.wallet, dto.MassMigration{
+			FromStateID: &a.stateID,
+			SpokeID:     &spokeID,
+			Amount:      &amount,
+			Fee:         &fee,
+			Nonce:       nonce,
+		})
+	})
+}
+
+// send signs the transaction with the next nonce, it is signed again with the nonce of the pending state
+// when the commander rejects it with nonce too low, e.g. because the state is also used by another client
+func (a *Account) send(sign func(nonce *models.Uint256) (interface{}, error)) (*common.Hash, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := a.nextNonce()
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := sign(nonce)
+		if err != nil {
+			return nil, err
+		}
+
+		txHash, err := a.client.SendTransaction(dto.MakeTransaction(tx))
+		if err == nil {
+			a.nonce = nonce.AddN(1)
+			return txHash, nil
+		}
+
+		// the transaction could still have been accepted if the commander was not reachable
+		a.nonce = nil
+		if attempt > 0 || ErrorCode(err) != nonceTooLowCode {
+			return nil, err
+		}
+	}
+}
+
+func (a *Account) nextNonce() (*models.Uint256, error) {
+	if a.nonce != nil {
+		return a.nonce, nil
+	}
+	userState, err := a.client.GetUserState(a.stateID)
+	if err != nil {
+		return nil, err
+	}
+	return &userState.Nonce, nil
+}
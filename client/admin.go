@@ -0,0 +1,126 @@
+package client
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (c *Client) GetPendingBatches() ([]dto.PendingBatch, error) {
+	var pendingBatches []Batch
+	err := c.query(&pendingBatches, "admin_getPendingBatches")
+	if err != nil {
+		return nil, err
+	}
+
+	batches := make([]dto.PendingBatch, 0, len(pendingBatches))
+	for i := range pendingBatches {
+		batches = append(batches, pendingBatches[i].ToDTO())
+	}
+	return batches, nil
+}
+
+func (c *Client) GetPendingTransactions() (models.GenericTransactionArray, error) {
+	pendingTxs := make([]Transaction, 0)
+	err := c.query(&pendingTxs, "admin_getPendingTransactions")
+	if err != nil {
+		return nil, err
+	}
+
+	return txsToTransactionArray(pendingTxs), nil
+}
+
+func (c *Client) GetFailedTransactions() (models.GenericTransactionArray, error) {
+	failedTxs := make([]Transaction, 0)
+	err := c.query(&failedTxs, "admin_getFailedTransactions")
+	if err != nil {
+		return nil, err
+	}
+
+	return txsToTransactionArray(failedTxs), nil
+}
+
+func (c *Client) Backup(params dto.BackupParams) (*dto.Backup, error) {
+	var backup dto.Backup
+	err := c.execute(&backup, "admin_backup", params)
+	if err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (c *Client) Configure(params dto.ConfigureParams) error {
+	return c.query(nil, "admin_configure", params)
+}
+
+func (c *Client) GetRichList(tokenID models.Uint256, limit *uint32) ([]dto.UserStateWithID, error) {
+	var states []dto.UserStateWithID
+	err := c.query(&states, "admin_getRichList", tokenID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (c *Client) GetStakes() ([]dto.Stake, error) {
+	var stakes []dto.Stake
+	err := c.query(&stakes, "admin_getStakes")
+	if err != nil {
+		return nil, err
+	}
+	return stakes, nil
+}
+
+func (c *Client) GetNodeStatus() (*dto.NodeStatus, error) {
+	var status dto.NodeStatus
+	err := c.query(&status, "admin_getNodeStatus")
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) WithdrawStake(batchID models.Uint256) (*common.Hash, error) {
+	var txHash common.Hash
+	err := c.execute(&txHash, "admin_withdrawStake", batchID)
+	if err != nil {
+		return nil, err
+	}
+	return &txHash, nil
+}
+
+func (c *Client) RecomputePendingState(stateID uint32, mutate bool) (*dto.RecomputePendingState, error) {
+	var result dto.RecomputePendingState
+	err := c.query(&result, "admin_recomputePendingState", stateID, mutate)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) GetPendingStates(startStateID, pageSize uint32) ([]dto.UserStateWithID, error) {
+	var states []dto.UserStateWithID
+	err := c.query(&states, "admin_getPendingStates", startStateID, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (c *Client) GetPendingPubkeyBalances(startPrefix []byte, pageSize uint32) ([]dto.PubkeyBalance, error) {
+	var balances []dto.PubkeyBalance
+	err := c.query(&balances, "admin_getPendingPubkeyBalances", startPrefix, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+func (c *Client) RecomputePubkeyBalances(startPrefix []byte, pageSize uint32) ([]dto.PubkeyBalance, error) {
+	var balances []dto.PubkeyBalance
+	err := c.query(&balances, "admin_recomputePubkeyBalances", startPrefix, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
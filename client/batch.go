@@ -1,6 +1,8 @@
 package client
 
 import (
+	"encoding/json"
+
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/dto"
 	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
@@ -29,3 +31,42 @@ func (b *Batch) ToDTO() dto.PendingBatch {
 		Commitments:     commitments,
 	}
 }
+
+// BatchWithCommitments is the result of hubble_getBatchByHash and hubble_getBatchByID,
+// the commitments are set in the field matching the type of the batch
+type BatchWithCommitments struct {
+	dto.Batch
+	AccountTreeRoot    *common.Hash
+	TxCommitments      []dto.BatchTxCommitment
+	MMCommitments      []dto.BatchMMCommitment
+	DepositCommitments []dto.BatchDepositCommitment
+}
+
+func (b *BatchWithCommitments) UnmarshalJSON(bytes []byte) error {
+	var rawBatch struct {
+		dto.Batch
+		AccountTreeRoot *common.Hash
+		Commitments     json.RawMessage
+	}
+	err := json.Unmarshal(bytes, &rawBatch)
+	if err != nil {
+		return err
+	}
+
+	b.Batch = rawBatch.Batch
+	b.AccountTreeRoot = rawBatch.AccountTreeRoot
+	if len(rawBatch.Commitments) == 0 {
+		return nil
+	}
+
+	switch rawBatch.Type {
+	case batchtype.Transfer, batchtype.Create2Transfer:
+		return json.Unmarshal(rawBatch.Commitments, &b.TxCommitments)
+	case batchtype.MassMigration:
+		return json.Unmarshal(rawBatch.Commitments, &b.MMCommitments)
+	case batchtype.Deposit:
+		return json.Unmarshal(rawBatch.Commitments, &b.DepositCommitments)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/utils/consts"
+	"github.com/pkg/errors"
+	"github.com/ybbus/jsonrpc/v2"
+)
+
+const (
+	DefaultMaxRetries = 5
+	DefaultRetryDelay = 100 * time.Millisecond
+)
+
+// API error codes the client reacts to, see api/Readme.md
+const (
+	nonceTooLowCode        = 10004
+	databaseConflictCode   = 40002
+	primaryUnavailableCode = 99009
+)
+
+// Client is a typed client of the hubble and admin JSON-RPC namespaces of a commander.
+// Calls which fail with a transient error are retried with an exponential backoff.
+type Client struct {
+	client     jsonrpc.RPCClient
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// NewClient creates a client of the commander API at the url, the authentication key is only needed
+// for the admin methods
+func NewClient(url, authenticationKey string) *Client {
+	client := jsonrpc.NewClientWithOpts(url, &jsonrpc.RPCClientOpts{
+		CustomHeaders: map[string]string{
+			consts.AuthKeyHeader: authenticationKey,
+		},
+	})
+
+	return &Client{
+		client:     client,
+		maxRetries: DefaultMaxRetries,
+		retryDelay: DefaultRetryDelay,
+	}
+}
+
+// SetRetryPolicy sets how many times a call is retried and the delay before the first retry,
+// which doubles with every following one
+func (c *Client) SetRetryPolicy(maxRetries int, retryDelay time.Duration) {
+	c.maxRetries = maxRetries
+	c.retryDelay = retryDelay
+}
+
+// query calls a method which can be safely repeated, so it is retried even if the request
+// might have been handled by the commander
+func (c *Client) query(out interface{}, method string, params ...interface{}) error {
+	return c.callWithRetries(isTransientError, out, method, params)
+}
+
+// execute calls a method which must not be repeated, it is only retried if the commander
+// rolled it back after a database conflict
+func (c *Client) execute(out interface{}, method string, params ...interface{}) error {
+	return c.callWithRetries(isConflictError, out, method, params)
+}
+
+func (c *Client) callWithRetries(isRetryable func(err error) bool, out interface{}, method string, params []interface{}) error {
+	var err error
+	delay := c.retryDelay
+	for attempt := 0; ; attempt++ {
+		err = c.call(out, method, params)
+		if err == nil || attempt >= c.maxRetries || !isRetryable(err) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (c *Client) call(out interface{}, method string, params []interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	// a single slice is sent as the whole params array, so params are never sent as an object
+	response, err := c.client.Call(method, params)
+	if err != nil {
+		return errors.WithStack(&TransportError{err: err})
+	}
+	if response.Error != nil {
+		return errors.WithStack(response.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return errors.WithStack(response.GetObject(out))
+}
+
+// TransportError is returned when the commander could not be reached or did not return a JSON-RPC response
+type TransportError struct {
+	err error
+}
+
+func (e *TransportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.err
+}
+
+// ErrorCode returns the code of the API error, see api/Readme.md, or 0 when err is not an API error
+func ErrorCode(err error) int {
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return 0
+}
+
+func isTransientError(err error) bool {
+	var transportErr *TransportError
+	return errors.As(err, &transportErr) || ErrorCode(err) == primaryUnavailableCode || isConflictError(err)
+}
+
+// isConflictError tells if the database transaction of the call was rolled back after a conflict with a concurrent one
+func isConflictError(err error) bool {
+	return ErrorCode(err) == databaseConflictCode
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/api"
+	"github.com/Worldcoin/hubble-commander/api/rpc"
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeHubbleAPI struct {
+	nonce          uint64
+	conflicts      int
+	userStateCalls int
+	sentNonces     []uint64
+}
+
+func (a *fakeHubbleAPI) GetUserState(id uint32) (*dto.UserStateWithID, error) {
+	a.userStateCalls++
+	return &dto.UserStateWithID{
+		StateID: id,
+		UserState: dto.UserState{
+			Nonce: models.MakeUint256(a.nonce),
+		},
+	}, nil
+}
+
+func (a *fakeHubbleAPI) SendTransaction(tx dto.Transaction) (*common.Hash, error) {
+	if a.conflicts > 0 {
+		a.conflicts--
+		return nil, api.APIErrDatabaseConflict
+	}
+
+	transfer, ok := tx.Parsed.(dto.Transfer)
+	if !ok || transfer.Signature == nil {
+		return nil, api.APIErrAnyMissingField
+	}
+	if transfer.Nonce.CmpN(a.nonce) < 0 {
+		return nil, api.APIErrNonceTooLow
+	}
+	a.sentNonces = append(a.sentNonces, transfer.Nonce.Uint64())
+	a.nonce = transfer.Nonce.Uint64() + 1
+
+	txHash := utils.RandomHash()
+	return &txHash, nil
+}
+
+func (a *fakeHubbleAPI) GetBatchByID(id models.Uint256) (*dto.BatchWithRootAndCommitments, error) {
+	batch := &dto.Batch{ID: id, Type: batchtype.MassMigration, Status: batchstatus.Mined}
+	commitments := []dto.BatchMMCommitment{{
+		ID:   dto.CommitmentID{BatchID: id},
+		Meta: dto.MassMigrationMeta{SpokeID: 3},
+	}}
+	return dto.MakeBatchWithRootAndCommitments(batch, &common.Hash{1, 2, 3}, commitments), nil
+}
+
+type fakeAdminAPI struct{}
+
+func (a *fakeAdminAPI) Backup(params dto.BackupParams) (*dto.Backup, error) {
+	return &dto.Backup{Path: params.Path, Since: params.Since, Version: params.Since + 1}, nil
+}
+
+type ClientTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	hubble  *fakeHubbleAPI
+	server  *httptest.Server
+	client  *Client
+	account *Account
+}
+
+func (s *ClientTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ClientTestSuite) SetupTest() {
+	s.hubble = &fakeHubbleAPI{}
+	server := rpc.NewServer()
+	err := server.RegisterName("hubble", s.hubble)
+	s.NoError(err)
+	err = server.RegisterName("admin", &fakeAdminAPI{})
+	s.NoError(err)
+
+	s.server = httptest.NewServer(server)
+	s.client = NewClient(s.server.URL, "")
+	s.client.SetRetryPolicy(DefaultMaxRetries, 0)
+
+	wallet, err := bls.NewRandomWallet(bls.Domain{1, 2, 3, 4})
+	s.NoError(err)
+	s.account = s.client.NewAccount(wallet, 1)
+}
+
+func (s *ClientTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ClientTestSuite) TestAccount_SendTransfer_TracksNonceLocally() {
+	s.hubble.nonce = 4
+
+	for i := 0; i < 3; i++ {
+		_, err := s.account.SendTransfer(2, models.MakeUint256(100), models.MakeUint256(10))
+		s.NoError(err)
+	}
+
+	s.Equal([]uint64{4, 5, 6}, s.hubble.sentNonces)
+	s.Equal(1, s.hubble.userStateCalls)
+}
+
+func (s *ClientTestSuite) TestAccount_SendTransfer_ReadsNonceAgainWhenTooLow() {
+	_, err := s.account.SendTransfer(2, models.MakeUint256(100), models.MakeUint256(10))
+	s.NoError(err)
+
+	// another client sent transactions from the same state
+	s.hubble.nonce = 7
+
+	_, err = s.account.SendTransfer(2, models.MakeUint256(100), models.MakeUint256(10))
+	s.NoError(err)
+
+	s.Equal([]uint64{0, 7}, s.hubble.sentNonces)
+	s.Equal(2, s.hubble.userStateCalls)
+}
+
+func (s *ClientTestSuite) TestSendTransaction_RetriesConflicts() {
+	s.hubble.conflicts = 2
+
+	_, err := s.account.SendTransfer(2, models.MakeUint256(100), models.MakeUint256(10))
+	s.NoError(err)
+	s.Equal([]uint64{0}, s.hubble.sentNonces)
+}
+
+func (s *ClientTestSuite) TestSendTransaction_ReturnsConflictAfterMaxRetries() {
+	s.hubble.conflicts = DefaultMaxRetries + 1
+
+	_, err := s.account.SendTransfer(2, models.MakeUint256(100), models.MakeUint256(10))
+	s.Error(err)
+	s.Equal(databaseConflictCode, ErrorCode(err))
+	s.Len(s.hubble.sentNonces, 0)
+}
+
+func (s *ClientTestSuite) TestSendTransaction_ReturnsAPIError() {
+	_, err := s.client.SendTransaction(dto.MakeTransaction(dto.Transfer{}))
+	s.Error(err)
+	s.Equal(10002, ErrorCode(err))
+}
+
+func (s *ClientTestSuite) TestGetBatchByID_DecodesCommitmentsOfBatchType() {
+	batch, err := s.client.GetBatchByID(models.MakeUint256(5))
+	s.NoError(err)
+
+	s.Equal(batchtype.MassMigration, batch.Type)
+	s.Equal(common.Hash{1, 2, 3}, *batch.AccountTreeRoot)
+	s.Len(batch.MMCommitments, 1)
+	s.EqualValues(3, batch.MMCommitments[0].Meta.SpokeID)
+	s.Nil(batch.TxCommitments)
+}
+
+func (s *ClientTestSuite) TestBackup() {
+	backup, err := s.client.Backup(dto.BackupParams{Path: "/tmp/backup", Since: 3})
+	s.NoError(err)
+	s.Equal(dto.Backup{Path: "/tmp/backup", Since: 3, Version: 4}, *backup)
+}
+
+func (s *ClientTestSuite) TestSign_MatchesAPISigning() {
+	wallet := s.account.wallet
+	fromStateID, toStateID, spokeID := uint32(1), uint32(2), uint32(3)
+	amount, fee, nonce := models.MakeUint256(100), models.MakeUint256(10), models.MakeUint256(4)
+
+	transfer := dto.Transfer{FromStateID: &fromStateID, ToStateID: &toStateID, Amount: &amount, Fee: &fee, Nonce: &nonce}
+	signedTransfer, err := signTransfer(wallet, transfer)
+	s.NoError(err)
+	expectedTransfer, err := api.SignTransfer(wallet, transfer)
+	s.NoError(err)
+	s.Equal(expectedTransfer, signedTransfer)
+
+	create2Transfer := dto.Create2Transfer{
+		FromStateID: &fromStateID,
+		ToPublicKey: wallet.PublicKey(),
+		Amount:      &amount,
+		Fee:         &fee,
+		Nonce:       &nonce,
+	}
+	signedCreate2Transfer, err := signCreate2Transfer(wallet, create2Transfer)
+	s.NoError(err)
+	expectedCreate2Transfer, err := api.SignCreate2Transfer(wallet, create2Transfer)
+	s.NoError(err)
+	s.Equal(expectedCreate2Transfer, signedCreate2Transfer)
+
+	massMigration := dto.MassMigration{FromStateID: &fromStateID, SpokeID: &spokeID, Amount: &amount, Fee: &fee, Nonce: &nonce}
+	signedMassMigration, err := signMassMigration(wallet, massMigration)
+	s.NoError(err)
+	expectedMassMigration, err := api.SignMassMigration(wallet, massMigration)
+	s.NoError(err)
+	s.Equal(expectedMassMigration, signedMassMigration)
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
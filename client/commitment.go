@@ -78,3 +78,60 @@ func (c *Commitment) unmarshalDepositCommitment(bytes []byte) error {
 	c.Parsed = &commitment
 	return nil
 }
+
+// CommitmentWithTransactions is the result of hubble_getCommitment, Parsed is one of *TransferCommitment,
+// *Create2TransferCommitment, *MassMigrationCommitment or *dto.DepositCommitment
+type CommitmentWithTransactions struct {
+	Parsed interface{}
+}
+
+type TransferCommitment struct {
+	dto.TxCommitment
+	Transactions []dto.TransferForCommitment
+}
+
+type Create2TransferCommitment struct {
+	dto.TxCommitment
+	Transactions []dto.Create2TransferForCommitment
+}
+
+type MassMigrationCommitment struct {
+	dto.MMCommitment
+	Transactions []dto.MassMigrationForCommitment
+}
+
+func (c *CommitmentWithTransactions) UnmarshalJSON(bytes []byte) error {
+	var rawCommitment struct {
+		Type *batchtype.BatchType
+	}
+	err := json.Unmarshal(bytes, &rawCommitment)
+	if err != nil {
+		return err
+	}
+
+	if rawCommitment.Type == nil {
+		return ErrMissingType
+	}
+
+	switch *rawCommitment.Type {
+	case batchtype.Transfer:
+		return c.unmarshal(bytes, &TransferCommitment{})
+	case batchtype.Create2Transfer:
+		return c.unmarshal(bytes, &Create2TransferCommitment{})
+	case batchtype.MassMigration:
+		return c.unmarshal(bytes, &MassMigrationCommitment{})
+	case batchtype.Deposit:
+		return c.unmarshal(bytes, &dto.DepositCommitment{})
+	default:
+		return ErrNotImplemented
+	}
+}
+
+func (c *CommitmentWithTransactions) unmarshal(bytes []byte, commitment interface{}) error {
+	err := json.Unmarshal(bytes, commitment)
+	if err != nil {
+		return err
+	}
+	c.Parsed = commitment
+	return nil
+}
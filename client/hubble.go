@@ -3,63 +3,224 @@ package client
 import (
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/dto"
-	"github.com/Worldcoin/hubble-commander/utils/consts"
-	"github.com/pkg/errors"
-	"github.com/ybbus/jsonrpc/v2"
+	"github.com/ethereum/go-ethereum/common"
 )
 
+// Hubble is the part of the Client used to migrate the data of a commander
 type Hubble interface {
 	GetPendingBatches() ([]dto.PendingBatch, error)
 	GetPendingTransactions() (models.GenericTransactionArray, error)
 	GetFailedTransactions() (models.GenericTransactionArray, error)
 }
 
-type hubble struct {
-	client jsonrpc.RPCClient
+func NewHubble(url, authenticationKey string) Hubble {
+	return NewClient(url, authenticationKey)
 }
 
-func NewHubble(url, authenticationKey string) Hubble {
-	client := jsonrpc.NewClientWithOpts(url, &jsonrpc.RPCClientOpts{
-		CustomHeaders: map[string]string{
-			consts.AuthKeyHeader: authenticationKey,
-		},
-	})
+func (c *Client) GetVersion() (string, error) {
+	var version string
+	err := c.query(&version, "hubble_getVersion")
+	return version, err
+}
+
+func (c *Client) GetStatus() (string, error) {
+	var status string
+	err := c.query(&status, "hubble_getStatus")
+	return status, err
+}
+
+func (c *Client) GetNetworkInfo() (*dto.NetworkInfo, error) {
+	var networkInfo dto.NetworkInfo
+	err := c.query(&networkInfo, "hubble_getNetworkInfo")
+	if err != nil {
+		return nil, err
+	}
+	return &networkInfo, nil
+}
+
+func (c *Client) GetGenesisAccounts() (models.GenesisAccounts, error) {
+	var accounts models.GenesisAccounts
+	err := c.query(&accounts, "hubble_getGenesisAccounts")
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (c *Client) GetUserState(stateID uint32) (*dto.UserStateWithID, error) {
+	var userState dto.UserStateWithID
+	err := c.query(&userState, "hubble_getUserState", stateID)
+	if err != nil {
+		return nil, err
+	}
+	return &userState, nil
+}
+
+func (c *Client) GetUserStates(publicKey *models.PublicKey) ([]dto.UserStateWithID, error) {
+	var userStates []dto.UserStateWithID
+	err := c.query(&userStates, "hubble_getUserStates", publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return userStates, nil
+}
+
+func (c *Client) GetStatesByToken(tokenID models.Uint256, cursor, limit *uint32) (*dto.UserStatesPage, error) {
+	var page dto.UserStatesPage
+	err := c.query(&page, "hubble_getStatesByToken", tokenID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) GetPublicKeyByPubKeyID(pubKeyID uint32) (*models.PublicKey, error) {
+	var publicKey models.PublicKey
+	err := c.query(&publicKey, "hubble_getPublicKeyByPubKeyID", pubKeyID)
+	if err != nil {
+		return nil, err
+	}
+	return &publicKey, nil
+}
+
+func (c *Client) GetPublicKeyByStateID(stateID uint32) (*models.PublicKey, error) {
+	var publicKey models.PublicKey
+	err := c.query(&publicKey, "hubble_getPublicKeyByStateID", stateID)
+	if err != nil {
+		return nil, err
+	}
+	return &publicKey, nil
+}
+
+// RegisterPublicKey waits until the public key is registered and returns its pubKeyID,
+// a key which is already registered is not registered again
+func (c *Client) RegisterPublicKey(publicKey *models.PublicKey, proofOfPossession *models.Signature) (*uint32, error) {
+	var pubKeyID uint32
+	err := c.query(&pubKeyID, "hubble_registerPublicKey", publicKey, proofOfPossession)
+	if err != nil {
+		return nil, err
+	}
+	return &pubKeyID, nil
+}
 
-	return &hubble{
-		client: client,
+func (c *Client) SendTransaction(tx dto.Transaction) (*common.Hash, error) {
+	var txHash common.Hash
+	err := c.execute(&txHash, "hubble_sendTransaction", tx.Parsed)
+	if err != nil {
+		return nil, err
 	}
+	return &txHash, nil
 }
 
-func (h *hubble) GetPendingBatches() ([]dto.PendingBatch, error) {
-	var pendingBatches []Batch
-	err := h.client.CallFor(&pendingBatches, "admin_getPendingBatches")
+func (c *Client) SendTransactions(txs []dto.Transaction) ([]dto.SendTransactionResult, error) {
+	parsedTxs := make([]interface{}, 0, len(txs))
+	for i := range txs {
+		parsedTxs = append(parsedTxs, txs[i].Parsed)
+	}
+
+	var results []dto.SendTransactionResult
+	err := c.execute(&results, "hubble_sendTransactions", parsedTxs)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
+	return results, nil
+}
+
+func (c *Client) GetTransaction(txHash common.Hash) (*dto.TransactionReceipt, error) {
+	var receipt dto.TransactionReceipt
+	err := c.query(&receipt, "hubble_getTransaction", txHash)
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
 
-	batches := make([]dto.PendingBatch, 0, len(pendingBatches))
-	for i := range pendingBatches {
-		batches = append(batches, pendingBatches[i].ToDTO())
+func (c *Client) GetBatches(from, to *models.Uint256) ([]dto.Batch, error) {
+	var batches []dto.Batch
+	err := c.query(&batches, "hubble_getBatches", from, to)
+	if err != nil {
+		return nil, err
 	}
 	return batches, nil
 }
 
-func (h *hubble) GetPendingTransactions() (models.GenericTransactionArray, error) {
-	pendingTxs := make([]Transaction, 0)
-	err := h.client.CallFor(&pendingTxs, "admin_getPendingTransactions")
+func (c *Client) GetBatchByHash(batchHash common.Hash) (*BatchWithCommitments, error) {
+	var batch BatchWithCommitments
+	err := c.query(&batch, "hubble_getBatchByHash", batchHash)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (c *Client) GetBatchByID(batchID models.Uint256) (*BatchWithCommitments, error) {
+	var batch BatchWithCommitments
+	err := c.query(&batch, "hubble_getBatchByID", batchID)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (c *Client) GetCommitment(commitmentID models.CommitmentID) (*CommitmentWithTransactions, error) {
+	var commitment CommitmentWithTransactions
+	err := c.query(&commitment, "hubble_getCommitment", commitmentID)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
+	return &commitment, nil
+}
+
+func (c *Client) GetCommitmentProof(commitmentID models.CommitmentID) (*dto.CommitmentInclusionProof, error) {
+	var proof dto.CommitmentInclusionProof
+	err := c.query(&proof, "hubble_getCommitmentProof", commitmentID)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+func (c *Client) GetMassMigrationCommitmentProof(commitmentID models.CommitmentID) (*dto.MassMigrationCommitmentProof, error) {
+	var proof dto.MassMigrationCommitmentProof
+	err := c.query(&proof, "hubble_getMassMigrationCommitmentProof", commitmentID)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
 
-	return txsToTransactionArray(pendingTxs), nil
+func (c *Client) GetPublicKeyProofByPubKeyID(pubKeyID uint32) (*dto.PublicKeyProof, error) {
+	var proof dto.PublicKeyProof
+	err := c.query(&proof, "hubble_getPublicKeyProofByPubKeyID", pubKeyID)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
 }
 
-func (h *hubble) GetFailedTransactions() (models.GenericTransactionArray, error) {
-	failedTxs := make([]Transaction, 0)
-	err := h.client.CallFor(&failedTxs, "admin_getFailedTransactions")
+func (c *Client) GetUserStateProof(stateID uint32) (*dto.StateMerkleProof, error) {
+	var proof dto.StateMerkleProof
+	err := c.query(&proof, "hubble_getUserStateProof", stateID)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
+	return &proof, nil
+}
 
-	return txsToTransactionArray(failedTxs), nil
+func (c *Client) GetWithdrawProof(commitmentID models.CommitmentID, txHash common.Hash) (*dto.WithdrawProof, error) {
+	var proof dto.WithdrawProof
+	err := c.query(&proof, "hubble_getWithdrawProof", commitmentID, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+func (c *Client) GetWithdrawProofsByPublicKey(publicKey *models.PublicKey) ([]dto.WithdrawClaim, error) {
+	var claims []dto.WithdrawClaim
+	err := c.query(&claims, "hubble_getWithdrawProofsByPublicKey", publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/encoder"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+)
+
+// The transactions are signed here rather than with the api package helpers, which would pull
+// the whole commander into the dependencies of the client.
+
+func signTransfer(wallet *bls.Wallet, transfer dto.Transfer) (*dto.Transfer, error) {
+	encodedTransfer, err := encoder.EncodeTransferForSigning(&models.Transfer{
+		TransactionBase: models.TransactionBase{
+			FromStateID: *transfer.FromStateID,
+			Amount:      *transfer.Amount,
+			Fee:         *transfer.Fee,
+			Nonce:       *transfer.Nonce,
+		},
+		ToStateID: *transfer.ToStateID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Signature, err = sign(wallet, encodedTransfer)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func signCreate2Transfer(wallet *bls.Wallet, create2Transfer dto.Create2Transfer) (*dto.Create2Transfer, error) {
+	encodedCreate2Transfer, err := encoder.EncodeCreate2TransferForSigning(&models.Create2Transfer{
+		TransactionBase: models.TransactionBase{
+			FromStateID: *create2Transfer.FromStateID,
+			Amount:      *create2Transfer.Amount,
+			Fee:         *create2Transfer.Fee,
+			Nonce:       *create2Transfer.Nonce,
+		},
+		ToPublicKey: *create2Transfer.ToPublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	create2Transfer.Signature, err = sign(wallet, encodedCreate2Transfer)
+	if err != nil {
+		return nil, err
+	}
+	return &create2Transfer, nil
+}
+
+func signMassMigration(wallet *bls.Wallet, massMigration dto.MassMigration) (*dto.MassMigration, error) {
+	encodedMassMigration := encoder.EncodeMassMigrationForSigning(&models.MassMigration{
+		TransactionBase: models.TransactionBase{
+			FromStateID: *massMigration.FromStateID,
+			Amount:      *massMigration.Amount,
+			Fee:         *massMigration.Fee,
+			Nonce:       *massMigration.Nonce,
+		},
+		SpokeID: *massMigration.SpokeID,
+	})
+
+	var err error
+	massMigration.Signature, err = sign(wallet, encodedMassMigration)
+	if err != nil {
+		return nil, err
+	}
+	return &massMigration, nil
+}
+
+func sign(wallet *bls.Wallet, message []byte) (*models.Signature, error) {
+	signature, err := wallet.Sign(message)
+	if err != nil {
+		return nil, err
+	}
+	return signature.ModelsSignature(), nil
+}
@@ -237,3 +237,45 @@ func logNewSyncedAccountsCount(newAccountsCount int) {
 		log.Printf("Found %d new account(s)", newAccountsCount)
 	}
 }
+
+// unsafeFlushRegistrations first resyncs the accounts reserved for registrations which did not end up
+// registered under the reserved pub key IDs, so that the next reservations start from the pub key IDs
+// the AccountRegistry will assign. It must be called while holding the stateMutex.
+func (c *Commander) unsafeFlushRegistrations(ctx context.Context) error {
+	pubKeyID := c.registrar.TakeResyncPubKeyID()
+	if pubKeyID != nil {
+		err := c.resyncBatchAccounts(ctx, *pubKeyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.registrar.Flush(ctx, c.storage, c.client)
+	return nil
+}
+
+// resyncBatchAccounts drops the batch accounts from the given pub key ID on and syncs the registered ones
+// again from the AccountRegistry
+func (c *Commander) resyncBatchAccounts(ctx context.Context, fromPubKeyID uint32) error {
+	removedCount, err := c.storage.AccountTree.RemoveBatchAccountsFrom(fromPubKeyID)
+	if err != nil {
+		return err
+	}
+
+	syncedBlock, err := c.storage.GetSyncedBlock()
+	if err != nil {
+		return err
+	}
+	resyncedCount, err := c.syncBatchAccounts(ctx, c.client.ChainState.AccountRegistryDeploymentBlock, *syncedBlock)
+	if err != nil {
+		return err
+	}
+
+	log.Warnf(
+		"Resynced batch accounts from pub key ID %d, dropped %d reserved account(s) and synced %d account(s) back",
+		fromPubKeyID,
+		removedCount,
+		*resyncedCount,
+	)
+	return nil
+}
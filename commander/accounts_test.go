@@ -83,6 +83,31 @@ func (s *AccountsTestSuite) TestSyncBatchAccounts() {
 	s.validateAccountsAfterSync(accounts)
 }
 
+func (s *AccountsTestSuite) TestResyncBatchAccounts_ReplacesReservedAccountsWithRegisteredOnes() {
+	reservedAccounts := make([]models.AccountLeaf, st.AccountBatchSize)
+	for i := range reservedAccounts {
+		reservedAccounts[i] = models.AccountLeaf{
+			PubKeyID:  uint32(st.AccountBatchOffset + i),
+			PublicKey: models.PublicKey{9, 9, byte(i)},
+		}
+	}
+	err := s.cmd.storage.AccountTree.SetBatch(reservedAccounts)
+	s.NoError(err)
+
+	accounts := s.registerBatchAccount()
+	latestBlockNumber, err := s.testClient.GetLatestBlockNumber()
+	s.NoError(err)
+	err = s.cmd.storage.SetSyncedBlock(*latestBlockNumber)
+	s.NoError(err)
+
+	err = s.cmd.resyncBatchAccounts(context.Background(), st.AccountBatchOffset)
+	s.NoError(err)
+
+	s.validateAccountsAfterSync(accounts)
+	_, err = s.cmd.storage.AccountTree.Leaves(&reservedAccounts[0].PublicKey)
+	s.ErrorIs(err, st.NewNotFoundError("account leaves"))
+}
+
 func (s *AccountsTestSuite) registerSingleAccount() models.AccountLeaf {
 	publicKey := models.PublicKey{2, 3, 4}
 	pubKeyID, err := s.testClient.RegisterAccountAndWait(&publicKey)
@@ -0,0 +1,123 @@
+package commander
+
+import (
+	"math"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type operatorBudget struct {
+	AffordableBatches  uint64
+	AffordableDisputes uint64
+}
+
+func (c *Commander) balanceWatcherLoop() error {
+	err := c.watchOperatorBalance()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(c.cfg.BalanceWatcher.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.workersContext.Done():
+			return nil
+		case <-ticker.C:
+			err = c.watchOperatorBalance()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *Commander) watchOperatorBalance() error {
+	balance, err := c.client.GetBalance()
+	if err != nil {
+		return err
+	}
+	gasPrice, err := c.client.SuggestGasPrice()
+	if err != nil {
+		return err
+	}
+	stakeAmount := c.client.GetStakeAmount()
+
+	budget := estimateOperatorBudget(balance, gasPrice, &stakeAmount, c.cfg.Rollup, c.cfg.BalanceWatcher.ReservedDisputes)
+	c.metrics.SaveOperatorBudget(balance, budget.AffordableBatches, budget.AffordableDisputes)
+
+	c.manageBatchCreationOnLowBalance(budget)
+	return nil
+}
+
+// manageBatchCreationOnLowBalance pauses batch creation when the operator runs low on funds so that
+// the remaining balance can be used to dispute fraudulent batches. Batch creation is resumed once
+// the balance is topped up, unless the operator disabled it in the meantime.
+func (c *Commander) manageBatchCreationOnLowBalance(budget *operatorBudget) {
+	isLow := budget.AffordableBatches < uint64(c.cfg.BalanceWatcher.MinAffordableBatches)
+	if !c.setPausedOnLowBalance(isLow) {
+		return
+	}
+
+	if isLow {
+		log.Warnf(
+			"Operator can afford only %d more batch(es) and %d dispute(s), pausing batch creation",
+			budget.AffordableBatches,
+			budget.AffordableDisputes,
+		)
+		c.stopRollupLoop()
+	} else if c.isBatchCreationEnabled() {
+		log.Info("Operator balance was topped up, resuming batch creation")
+	} else {
+		log.Info("Operator balance was topped up, batch creation stays disabled by the operator")
+	}
+}
+
+func estimateOperatorBudget(
+	balance, gasPrice, stakeAmount *models.Uint256,
+	cfg *config.RollupConfig,
+	reservedDisputes uint32,
+) *operatorBudget {
+	disputeCost := gasPrice.MulN(maxUint64(cfg.TransitionDisputeGasLimit, cfg.SignatureDisputeGasLimit))
+	batchCost := gasPrice.MulN(maxUint64(
+		cfg.TransferBatchSubmissionGasLimit,
+		cfg.C2TBatchSubmissionGasLimit,
+		cfg.MMBatchSubmissionGasLimit,
+		cfg.DepositBatchSubmissionGasLimit,
+	)).Add(stakeAmount)
+
+	budget := &operatorBudget{
+		AffordableDisputes: affordableCount(balance, disputeCost),
+	}
+
+	reserve := disputeCost.MulN(uint64(reservedDisputes))
+	if balance.Cmp(reserve) > 0 {
+		budget.AffordableBatches = affordableCount(balance.Sub(reserve), batchCost)
+	}
+	return budget
+}
+
+func affordableCount(funds, cost *models.Uint256) uint64 {
+	if cost.IsZero() {
+		return math.MaxUint64
+	}
+	count := funds.Div(cost)
+	if !count.IsUint64() {
+		return math.MaxUint64
+	}
+	return count.Uint64()
+}
+
+func maxUint64(values ...uint64) uint64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value > result {
+			result = value
+		}
+	}
+	return result
+}
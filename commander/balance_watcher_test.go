@@ -0,0 +1,101 @@
+package commander
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BalanceWatcherTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	cfg *config.Config
+	cmd *Commander
+}
+
+func (s *BalanceWatcherTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *BalanceWatcherTestSuite) SetupTest() {
+	s.cfg = config.GetTestConfig()
+	s.cfg.Rollup = &config.RollupConfig{
+		TransferBatchSubmissionGasLimit: 100,
+		C2TBatchSubmissionGasLimit:      200,
+		MMBatchSubmissionGasLimit:       150,
+		DepositBatchSubmissionGasLimit:  50,
+		TransitionDisputeGasLimit:       1000,
+		SignatureDisputeGasLimit:        2000,
+	}
+	s.cfg.BalanceWatcher.MinAffordableBatches = 2
+	s.cfg.BalanceWatcher.ReservedDisputes = 1
+
+	s.cmd = &Commander{
+		cfg:            s.cfg,
+		rollupControls: makeRollupControls(false),
+	}
+}
+
+func (s *BalanceWatcherTestSuite) TestEstimateOperatorBudget() {
+	// dispute costs 2000 * 10 = 20 000, batch costs 200 * 10 + 1000 = 3000
+	budget := estimateOperatorBudget(models.NewUint256(30_000), models.NewUint256(10), models.NewUint256(1000), s.cfg.Rollup, 1)
+	s.EqualValues(1, budget.AffordableDisputes)
+	s.EqualValues(3, budget.AffordableBatches)
+}
+
+func (s *BalanceWatcherTestSuite) TestEstimateOperatorBudget_BalanceBelowDisputeReserve() {
+	budget := estimateOperatorBudget(models.NewUint256(30_000), models.NewUint256(10), models.NewUint256(1000), s.cfg.Rollup, 2)
+	s.EqualValues(1, budget.AffordableDisputes)
+	s.EqualValues(0, budget.AffordableBatches)
+}
+
+func (s *BalanceWatcherTestSuite) TestManageBatchCreationOnLowBalance_PausesAndResumesBatchCreation() {
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 1})
+	s.False(s.cmd.isBatchCreationEnabled())
+	s.assertBatchCreationStatus(true, true)
+
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 2})
+	s.True(s.cmd.isBatchCreationEnabled())
+	s.assertBatchCreationStatus(true, false)
+}
+
+func (s *BalanceWatcherTestSuite) TestManageBatchCreationOnLowBalance_DoesNotEnableBatchCreationDisabledByOperator() {
+	s.cmd.EnableBatchCreation(false)
+
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 1})
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 2})
+	s.False(s.cmd.isBatchCreationEnabled())
+	s.assertBatchCreationStatus(false, false)
+}
+
+func (s *BalanceWatcherTestSuite) TestManageBatchCreationOnLowBalance_DoesNotEnableBatchCreationDisabledWhilePaused() {
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 1})
+	s.cmd.EnableBatchCreation(false)
+
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 2})
+	s.False(s.cmd.isBatchCreationEnabled())
+	s.assertBatchCreationStatus(false, false)
+}
+
+func (s *BalanceWatcherTestSuite) TestManageBatchCreationOnLowBalance_StaysPausedWhenEnabledWhilePaused() {
+	s.cmd.EnableBatchCreation(false)
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 1})
+	s.cmd.EnableBatchCreation(true)
+	s.False(s.cmd.isBatchCreationEnabled())
+
+	s.cmd.manageBatchCreationOnLowBalance(&operatorBudget{AffordableBatches: 2})
+	s.True(s.cmd.isBatchCreationEnabled())
+}
+
+func (s *BalanceWatcherTestSuite) assertBatchCreationStatus(expectedEnabled, expectedPaused bool) {
+	enabled, paused := s.cmd.batchCreationStatus()
+	s.Equal(expectedEnabled, enabled)
+	s.Equal(expectedPaused, paused)
+}
+
+func TestBalanceWatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(BalanceWatcherTestSuite))
+}
@@ -164,6 +164,12 @@ func (c *Commander) disputeFraudulentBatch(
 	remoteBatch *eth.DecodedTxBatch,
 	disputableErr *syncer.DisputableError,
 ) (err error) {
+	if !c.elector.IsLeader() {
+		log.WithFields(log.Fields{"batchID": remoteBatch.ID.String()}).
+			Info("Leaving the dispute of the fraudulent batch to the leader")
+		return ErrRollbackInProgress
+	}
+
 	disputeCtx := disputer.NewContext(c.storage, c.client)
 
 	switch disputableErr.Type {
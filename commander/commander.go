@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Worldcoin/hubble-commander/api"
+	"github.com/Worldcoin/hubble-commander/commander/leader"
+	"github.com/Worldcoin/hubble-commander/commander/registrar"
 	"github.com/Worldcoin/hubble-commander/commander/tracker"
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/contracts/accountregistry"
@@ -58,7 +61,9 @@ type Commander struct {
 	invalidBatchID *models.Uint256
 
 	txsTrackingChannels *eth.TxsTrackingChannels
-	txsTracker          *tracker.Tracker
+	txsTracker          atomic.Value // *tracker.Tracker, stored by the elector goroutine
+	registrar           *registrar.Registrar
+	elector             *leader.Elector
 }
 
 func NewCommander(cfg *config.Config, blockchain chain.Connection) *Commander {
@@ -73,6 +78,7 @@ func NewCommander(cfg *config.Config, blockchain chain.Connection) *Commander {
 			Requests: make(chan *eth.TxSendingRequest, 1024),
 			SentTxs:  make(chan *types.Transaction, 1024),
 		},
+		registrar: registrar.NewRegistrar(cfg.Registration),
 	}
 }
 
@@ -91,44 +97,91 @@ func (c *Commander) Start() (err error) {
 	if c.isActive() {
 		return nil
 	}
+	if c.cfg.Replica != nil {
+		return c.startReplica()
+	}
 
 	c.storage, err = st.NewStorage(c.cfg)
 	if err != nil {
 		return err
 	}
 
-	err = c.storage.MigratePubKeyPendingState()
+	err = c.storage.MigrateSchema()
+	if err != nil {
+		return err
+	}
+
+	if c.cfg.Bootstrap.SnapshotPath != nil {
+		c.client, err = c.getClientFromSnapshot(*c.cfg.Bootstrap.SnapshotPath)
+	} else {
+		c.client, err = getClient(c.blockchain, c.storage, c.cfg, c.metrics, c.txsTrackingChannels)
+	}
 	if err != nil {
 		return err
 	}
 
-	c.client, err = getClient(c.blockchain, c.storage, c.cfg, c.metrics, c.txsTrackingChannels)
+	err = c.addGenesisBatch()
 	if err != nil {
 		return err
 	}
 
-	c.txsTracker, err = tracker.NewTrackerWithCounter(
+	c.metricsServer = c.metrics.NewServer(c.cfg.Metrics)
+
+	// the elector is created before the API server, a standby rejects the transactions sent to it
+	if c.cfg.LeaderElection.Enabled && !c.cfg.SafeMode {
+		c.elector, err = leader.NewElector(c.cfg.LeaderElection)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.apiServer, err = api.NewServer(
+		c.cfg,
+		c.storage,
 		c.client,
-		c.txsTrackingChannels.SentTxs,
-		c.txsTrackingChannels.Requests,
-		c.metrics.BlockchainGasSpend,
+		c.metrics,
+		c.EnableBatchCreation,
+		c.isMigrating,
+		c.rollupStatus,
+		c.registrar,
+		c.elector,
 	)
 	if err != nil {
 		return err
 	}
 
-	err = c.addGenesisBatch()
+	err = c.startServers()
 	if err != nil {
 		return err
 	}
 
-	c.metricsServer = c.metrics.NewServer(c.cfg.Metrics)
+	if c.cfg.SafeMode {
+		log.Warn("Commander running in safe mode, most functions are disabled")
+	} else {
+		err = c.startTrackerOnceElected()
+		if err != nil {
+			return err
+		}
+		c.startWorker("New Block Loop", func() error { return c.newBlockLoop() })
+		if c.cfg.BalanceWatcher.Enabled {
+			c.startWorker("Balance Watcher", func() error { return c.balanceWatcherLoop() })
+		}
+	}
 
-	c.apiServer, err = api.NewServer(c.cfg, c.storage, c.client, c.metrics, c.EnableBatchCreation, c.isMigrating)
-	if err != nil {
-		return err
+	c.startWorker("Mempool Metrics", func() error { return c.mempoolMetricsLoop() })
+	c.startWorker("Badger Garbage Colection", func() error { return c.badgerGCLoop() })
+	if c.cfg.Pruning.Enabled {
+		c.startWorker("Pruning", func() error { return c.pruningLoop() })
 	}
 
+	go c.handleWorkerError()
+
+	log.Printf("Commander started and listening on port %s", c.cfg.API.Port)
+	c.setActive(true)
+	return nil
+}
+
+func (c *Commander) startServers() error {
 	c.startWorker("API Server", func() error {
 		err := c.apiServer.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
@@ -154,22 +207,6 @@ func (c *Commander) Start() (err error) {
 			shutdownTracer()
 		}()
 	}
-
-	if c.cfg.SafeMode {
-		log.Warn("Commander running in safe mode, most functions are disabled")
-	} else {
-		c.startWorker("Tracking Sent Txs", func() error { return c.txsTracker.TrackSentTxs(c.workersContext) })
-		c.startWorker("Sending Requested Txs", func() error { return c.txsTracker.SendRequestedTxs(c.workersContext) })
-		c.startWorker("New Block Loop", func() error { return c.newBlockLoop() })
-	}
-
-	c.startWorker("Mempool Metrics", func() error { return c.mempoolMetricsLoop() })
-	c.startWorker("Badger Garbage Colection", func() error { return c.badgerGCLoop() })
-
-	go c.handleWorkerError()
-
-	log.Printf("Commander started and listening on port %s", c.cfg.API.Port)
-	c.setActive(true)
 	return nil
 }
 
@@ -188,13 +225,63 @@ func (c *Commander) Stop() (err error) {
 	return err
 }
 
+// startTrackerOnceElected starts sending transactions right away unless leader election is enabled,
+// in which case a standby commander only starts the tracker after taking over the leader lease
+func (c *Commander) startTrackerOnceElected() error {
+	if c.elector == nil {
+		return c.startTracker()
+	}
+	c.startWorker("Leader Election", func() error { return c.elector.Run(c.workersContext, c.startTracker) })
+	return nil
+}
+
+func (c *Commander) startTracker() error {
+	// the tracker is created here so that a new leader starts with the latest nonce
+	txsTracker, err := tracker.NewTrackerWithCounter(
+		c.client,
+		c.txsTrackingChannels.SentTxs,
+		c.txsTrackingChannels.Requests,
+		c.metrics.BlockchainGasSpend,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.txsTracker.Store(txsTracker)
+
+	c.startWorker("Tracking Sent Txs", func() error { return txsTracker.TrackSentTxs(c.workersContext) })
+	c.startWorker("Sending Requested Txs", func() error { return txsTracker.SendRequestedTxs(c.workersContext) })
+	return nil
+}
+
+// getTxsTracker returns nil until the tracker is started
+func (c *Commander) getTxsTracker() *tracker.Tracker {
+	txsTracker, _ := c.txsTracker.Load().(*tracker.Tracker)
+	return txsTracker
+}
+
 func (c *Commander) EnableBatchCreation(enable bool) {
-	c.batchCreationEnabled = enable
+	c.setBatchCreationEnabled(enable)
 	if !enable {
 		c.stopRollupLoop()
 	}
 }
 
+func (c *Commander) rollupStatus() dto.RollupStatus {
+	enabled, paused := c.batchCreationStatus()
+	status := dto.RollupStatus{
+		LoopActive:           c.isRollupLoopActive(),
+		BatchCreationEnabled: enabled,
+		PausedOnLowBalance:   paused,
+		Migrating:            c.isMigrating(),
+	}
+	// the tracker is only started once the commander is elected
+	if txsTracker := c.getTxsTracker(); txsTracker != nil {
+		status.PendingL1Txs = txsTracker.PendingTxsCount()
+	}
+	return status
+}
+
 func (c *Commander) mempoolMetricsLoop() error {
 	ticker := time.NewTicker(time.Second * 10)
 	defer ticker.Stop()
@@ -221,19 +308,23 @@ func (c *Commander) badgerGCLoop() error {
 		case <-c.workersContext.Done():
 			return nil
 		case <-ticker.C:
-			log.Debug("Running GC in background")
-		again:
-			innerErr := c.storage.TriggerGC()
-			if innerErr == nil {
-				goto again
-			}
-			// this looks weird but we're ignoring the error because innerErr!=nil
-			// _if we successfully did nothing_
-			log.Debug("Finished Running GC: ", innerErr)
+			c.runBadgerGC()
 		}
 	}
 }
 
+func (c *Commander) runBadgerGC() {
+	log.Debug("Running GC in background")
+again:
+	innerErr := c.storage.TriggerGC()
+	if innerErr == nil {
+		goto again
+	}
+	// this looks weird but we're ignoring the error because innerErr!=nil
+	// _if we successfully did nothing_
+	log.Debug("Finished Running GC: ", innerErr)
+}
+
 func (c *Commander) handleWorkerError() {
 	<-c.workersContext.Done()
 	c.closeOnce.Do(func() {
@@ -254,6 +345,7 @@ func (c *Commander) stop() error {
 		return err
 	}
 	c.stopWorkersAndWait()
+	c.registrar.Cancel()
 	c.setActive(false)
 	return c.storage.Close()
 }
@@ -425,6 +517,37 @@ func createClientFromChainState(
 	cfg *config.Config,
 	commanderMetrics *metrics.CommanderMetrics,
 	txsChannels *eth.TxsTrackingChannels,
+) (*eth.Client, error) {
+	return newEthClient(blockchain, chainState, commanderMetrics, txsChannels, eth.ClientConfig{
+		TransferBatchSubmissionGasLimit:  ref.Uint64(cfg.Rollup.TransferBatchSubmissionGasLimit),
+		C2TBatchSubmissionGasLimit:       ref.Uint64(cfg.Rollup.C2TBatchSubmissionGasLimit),
+		MMBatchSubmissionGasLimit:        ref.Uint64(cfg.Rollup.MMBatchSubmissionGasLimit),
+		DepositBatchSubmissionGasLimit:   ref.Uint64(cfg.Rollup.DepositBatchSubmissionGasLimit),
+		TransitionDisputeGasLimit:        ref.Uint64(cfg.Rollup.TransitionDisputeGasLimit),
+		SignatureDisputeGasLimit:         ref.Uint64(cfg.Rollup.SignatureDisputeGasLimit),
+		BatchAccountRegistrationGasLimit: ref.Uint64(cfg.Rollup.BatchAccountRegistrationGasLimit),
+		TxMineTimeout:                    ref.Duration(cfg.Ethereum.MineTimeout),
+	})
+}
+
+// NewClientFromChainSpec creates an ethereum client for the contracts of the chain spec which sends
+// transactions from the account of the connection directly, i.e. not through the transactions tracker
+func NewClientFromChainSpec(
+	blockchain chain.Connection,
+	chainSpec *models.ChainSpec,
+	cfg *config.EthereumConfig,
+) (*eth.Client, error) {
+	return newEthClient(blockchain, newChainStateFromChainSpec(chainSpec), nil, nil, eth.ClientConfig{
+		TxMineTimeout: ref.Duration(cfg.MineTimeout),
+	})
+}
+
+func newEthClient(
+	blockchain chain.Connection,
+	chainState *models.ChainState,
+	commanderMetrics *metrics.CommanderMetrics,
+	txsChannels *eth.TxsTrackingChannels,
+	clientConfig eth.ClientConfig,
 ) (*eth.Client, error) {
 	err := logChainState(chainState)
 	if err != nil {
@@ -466,16 +589,7 @@ func createClientFromChainState(
 		SpokeRegistry:   spokeRegistry,
 		DepositManager:  depositManager,
 		TxsChannels:     txsChannels,
-		ClientConfig: eth.ClientConfig{
-			TransferBatchSubmissionGasLimit:  ref.Uint64(cfg.Rollup.TransferBatchSubmissionGasLimit),
-			C2TBatchSubmissionGasLimit:       ref.Uint64(cfg.Rollup.C2TBatchSubmissionGasLimit),
-			MMBatchSubmissionGasLimit:        ref.Uint64(cfg.Rollup.MMBatchSubmissionGasLimit),
-			DepositBatchSubmissionGasLimit:   ref.Uint64(cfg.Rollup.DepositBatchSubmissionGasLimit),
-			TransitionDisputeGasLimit:        ref.Uint64(cfg.Rollup.TransitionDisputeGasLimit),
-			SignatureDisputeGasLimit:         ref.Uint64(cfg.Rollup.SignatureDisputeGasLimit),
-			BatchAccountRegistrationGasLimit: ref.Uint64(cfg.Rollup.BatchAccountRegistrationGasLimit),
-			TxMineTimeout:                    ref.Duration(cfg.Ethereum.MineTimeout),
-		},
+		ClientConfig:    clientConfig,
 	})
 	if err != nil {
 		return nil, err
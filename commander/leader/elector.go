@@ -0,0 +1,122 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrLeadershipLost = fmt.Errorf("leader lease was lost")
+
+// Elector keeps renewing the leader lease. Only the leader is allowed to send transactions,
+// other commanders stay on standby: they keep syncing and serving the API.
+type Elector struct {
+	lock           Lock
+	nodeID         string
+	leaseDuration  time.Duration
+	renewInterval  time.Duration
+	isLeader       uint32
+	leaseExpiresAt time.Time
+}
+
+func NewElector(cfg *config.LeaderElectionConfig) (*Elector, error) {
+	lock, err := NewLock(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewElectorWithLock(lock, cfg.NodeID, cfg.LeaseDuration, cfg.RenewInterval), nil
+}
+
+func NewElectorWithLock(lock Lock, nodeID string, leaseDuration, renewInterval time.Duration) *Elector {
+	return &Elector{
+		lock:          lock,
+		nodeID:        nodeID,
+		leaseDuration: leaseDuration,
+		renewInterval: renewInterval,
+	}
+}
+
+// IsLeader returns true when leader election is disabled
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+	return atomic.LoadUint32(&e.isLeader) != 0
+}
+
+// Run tries to acquire the lease until the context is cancelled and calls onElected once it succeeds.
+// Failed renewals are retried while the lease is still valid. A leader whose lease is taken over or
+// would expire before the next renewal must stop sending transactions straight away, in that case
+// Run returns ErrLeadershipLost so that the commander can be stopped and restarted as a standby.
+func (e *Elector) Run(ctx context.Context, onElected func() error) error {
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		err := e.renew(ctx, onElected)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return e.release()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) renew(ctx context.Context, onElected func() error) error {
+	// the lease is extended from the moment the request is sent at the latest
+	requestedAt := time.Now()
+	acquired, err := e.lock.TryAcquire(ctx)
+	if e.IsLeader() {
+		return e.handleRenewal(acquired, err, requestedAt)
+	}
+
+	if err != nil {
+		log.Warnf("Failed to acquire leader lease: %v", err)
+		return nil
+	}
+	if !acquired {
+		return nil
+	}
+
+	log.Infof("Commander %s was elected as the leader", e.nodeID)
+	e.leaseExpiresAt = requestedAt.Add(e.leaseDuration)
+	atomic.StoreUint32(&e.isLeader, 1)
+	return onElected()
+}
+
+func (e *Elector) handleRenewal(acquired bool, err error, requestedAt time.Time) error {
+	if err == nil && acquired {
+		e.leaseExpiresAt = requestedAt.Add(e.leaseDuration)
+		return nil
+	}
+	if err == nil {
+		atomic.StoreUint32(&e.isLeader, 0)
+		return errors.WithMessage(ErrLeadershipLost, "lease is held by another node")
+	}
+	if time.Now().Add(e.renewInterval).Before(e.leaseExpiresAt) {
+		log.Warnf("Failed to renew leader lease, retrying until it expires at %s: %v", e.leaseExpiresAt.Format(time.RFC3339), err)
+		return nil
+	}
+	atomic.StoreUint32(&e.isLeader, 0)
+	return errors.WithMessage(ErrLeadershipLost, err.Error())
+}
+
+func (e *Elector) release() error {
+	if !e.IsLeader() {
+		return nil
+	}
+	atomic.StoreUint32(&e.isLeader, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpLockTimeout)
+	defer cancel()
+	return e.lock.Release(ctx)
+}
@@ -0,0 +1,147 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ElectorTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	lock *testLock
+}
+
+func (s *ElectorTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ElectorTestSuite) SetupTest() {
+	s.lock = &testLock{}
+}
+
+func (s *ElectorTestSuite) TestIsLeader_NilElector() {
+	var elector *Elector
+	s.True(elector.IsLeader())
+}
+
+func (s *ElectorTestSuite) TestRun_CallsOnElectedOnceLeaseIsAcquired() {
+	s.lock.setHeldByOther(true)
+	elector := NewElectorWithLock(s.lock, "node", time.Second, time.Millisecond)
+
+	elected := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- elector.Run(ctx, func() error {
+			close(elected)
+			return nil
+		})
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	s.False(elector.IsLeader())
+
+	s.lock.setHeldByOther(false)
+	<-elected
+	s.True(elector.IsLeader())
+
+	cancel()
+	s.NoError(<-done)
+	s.False(elector.IsLeader())
+	s.True(s.lock.released)
+}
+
+func (s *ElectorTestSuite) TestRun_ReturnsErrorWhenLeaseIsLost() {
+	elector := NewElectorWithLock(s.lock, "node", time.Second, time.Millisecond)
+
+	err := elector.Run(context.Background(), func() error {
+		s.lock.setHeldByOther(true)
+		return nil
+	})
+	s.ErrorIs(err, ErrLeadershipLost)
+	s.False(elector.IsLeader())
+}
+
+func (s *ElectorTestSuite) TestRun_RetriesRenewalWhileLeaseIsValid() {
+	elector := NewElectorWithLock(s.lock, "node", time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- elector.Run(ctx, func() error {
+			s.lock.setFailures(3)
+			return nil
+		})
+	}()
+
+	s.Eventually(func() bool { return s.lock.getFailures() == 0 }, time.Second, time.Millisecond)
+	s.True(elector.IsLeader())
+
+	cancel()
+	s.NoError(<-done)
+}
+
+func (s *ElectorTestSuite) TestRun_ReturnsErrorWhenLeaseCannotBeRenewedBeforeExpiry() {
+	elector := NewElectorWithLock(s.lock, "node", 20*time.Millisecond, time.Millisecond)
+
+	err := elector.Run(context.Background(), func() error {
+		s.lock.setFailures(1000)
+		return nil
+	})
+	s.ErrorIs(err, ErrLeadershipLost)
+	s.False(elector.IsLeader())
+}
+
+type testLock struct {
+	mutex       sync.Mutex
+	heldByOther bool
+	failures    int
+	released    bool
+}
+
+func (l *testLock) setFailures(failures int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.failures = failures
+}
+
+func (l *testLock) getFailures() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.failures
+}
+
+func (l *testLock) setHeldByOther(heldByOther bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.heldByOther = heldByOther
+}
+
+func (l *testLock) TryAcquire(_ context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.failures > 0 {
+		l.failures--
+		return false, errTestLockUnavailable
+	}
+	return !l.heldByOther, nil
+}
+
+func (l *testLock) Release(_ context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.released = true
+	return nil
+}
+
+var errTestLockUnavailable = fmt.Errorf("lock server unavailable")
+
+func TestElectorTestSuite(t *testing.T) {
+	suite.Run(t, new(ElectorTestSuite))
+}
@@ -0,0 +1,105 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileLock keeps the lease in a file which must be accessible by all commanders, e.g. on a shared volume.
+// Concurrent updates of the lease are serialised with flock(2).
+type FileLock struct {
+	path          string
+	nodeID        string
+	leaseDuration time.Duration
+}
+
+func NewFileLock(path, nodeID string, leaseDuration time.Duration) *FileLock {
+	return &FileLock{
+		path:          path,
+		nodeID:        nodeID,
+		leaseDuration: leaseDuration,
+	}
+}
+
+func (l *FileLock) TryAcquire(_ context.Context) (acquired bool, err error) {
+	err = l.withLockedFile(func(file *os.File, current *lease) error {
+		now := time.Now()
+		if current.isHeldByOther(l.nodeID, now) {
+			return nil
+		}
+		acquired = true
+		return writeLease(file, &lease{
+			Holder:    l.nodeID,
+			ExpiresAt: now.Add(l.leaseDuration),
+		})
+	})
+	return acquired, err
+}
+
+func (l *FileLock) Release(_ context.Context) error {
+	return l.withLockedFile(func(file *os.File, current *lease) error {
+		if current.Holder != l.nodeID {
+			return nil
+		}
+		return writeLease(file, &lease{})
+	})
+}
+
+func (l *FileLock) withLockedFile(fn func(file *os.File, current *lease) error) error {
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN) }()
+
+	current, err := readLease(file)
+	if err != nil {
+		return err
+	}
+	return fn(file, current)
+}
+
+func readLease(file *os.File) (*lease, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var current lease
+	if len(data) == 0 {
+		return &current, nil
+	}
+	err = json.Unmarshal(data, &current)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &current, nil
+}
+
+func writeLease(file *os.File, newLease *lease) error {
+	data, err := json.Marshal(newLease)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = file.Truncate(0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = file.WriteAt(data, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(file.Sync())
+}
@@ -0,0 +1,94 @@
+package leader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileLockTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	dir string
+}
+
+func (s *FileLockTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *FileLockTestSuite) SetupTest() {
+	var err error
+	s.dir, err = os.MkdirTemp("", "hubble-leader-lock")
+	s.NoError(err)
+}
+
+func (s *FileLockTestSuite) TearDownTest() {
+	err := os.RemoveAll(s.dir)
+	s.NoError(err)
+}
+
+func (s *FileLockTestSuite) TestTryAcquire_LeaseHeldByAnotherNode() {
+	first := s.newFileLock("first", time.Minute)
+	second := s.newFileLock("second", time.Minute)
+
+	acquired, err := first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.False(acquired)
+
+	acquired, err = first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+}
+
+func (s *FileLockTestSuite) TestTryAcquire_TakesOverExpiredLease() {
+	first := s.newFileLock("first", time.Millisecond)
+	second := s.newFileLock("second", time.Minute)
+
+	acquired, err := first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+
+	time.Sleep(5 * time.Millisecond)
+
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+}
+
+func (s *FileLockTestSuite) TestRelease() {
+	first := s.newFileLock("first", time.Minute)
+	second := s.newFileLock("second", time.Minute)
+
+	acquired, err := first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+
+	err = second.Release(context.Background())
+	s.NoError(err)
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.False(acquired)
+
+	err = first.Release(context.Background())
+	s.NoError(err)
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+}
+
+func (s *FileLockTestSuite) newFileLock(nodeID string, leaseDuration time.Duration) *FileLock {
+	return NewFileLock(filepath.Join(s.dir, "leader.lock"), nodeID, leaseDuration)
+}
+
+func TestFileLockTestSuite(t *testing.T) {
+	suite.Run(t, new(FileLockTestSuite))
+}
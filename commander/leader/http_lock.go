@@ -0,0 +1,96 @@
+package leader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const httpLockTimeout = 5 * time.Second
+
+var ErrUnexpectedLeaseResponse = fmt.Errorf("unexpected lease server response")
+
+// HTTPLock keeps the lease in an external lease server, e.g. a thin service in front of
+// Postgres advisory locks or etcd leases. The server is expected to handle:
+//
+//	PUT <url>    {"Holder": "<node id>", "TTL": <seconds>}  200 when granted, 409 when held by another node
+//	DELETE <url> {"Holder": "<node id>"}                    200 or 404 when released
+type HTTPLock struct {
+	url           string
+	nodeID        string
+	leaseDuration time.Duration
+	client        *http.Client
+}
+
+type leaseRequest struct {
+	Holder string
+	TTL    uint64 `json:",omitempty"`
+}
+
+func NewHTTPLock(url, nodeID string, leaseDuration time.Duration) *HTTPLock {
+	return &HTTPLock{
+		url:           url,
+		nodeID:        nodeID,
+		leaseDuration: leaseDuration,
+		client:        &http.Client{Timeout: httpLockTimeout},
+	}
+}
+
+func (l *HTTPLock) TryAcquire(ctx context.Context) (bool, error) {
+	statusCode, err := l.request(ctx, http.MethodPut, &leaseRequest{
+		Holder: l.nodeID,
+		TTL:    uint64(l.leaseDuration.Seconds()),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	switch statusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, errors.WithMessagef(ErrUnexpectedLeaseResponse, "status %d", statusCode)
+	}
+}
+
+func (l *HTTPLock) Release(ctx context.Context) error {
+	statusCode, err := l.request(ctx, http.MethodDelete, &leaseRequest{Holder: l.nodeID})
+	if err != nil {
+		return err
+	}
+
+	switch statusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return errors.WithMessagef(ErrUnexpectedLeaseResponse, "status %d", statusCode)
+	}
+}
+
+func (l *HTTPLock) request(ctx context.Context, method string, body *leaseRequest) (int, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, l.url, bytes.NewReader(data))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := l.client.Do(request)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,104 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPLockTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	server *httptest.Server
+
+	mutex  sync.Mutex
+	holder string
+}
+
+func (s *HTTPLockTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *HTTPLockTestSuite) SetupTest() {
+	s.holder = ""
+	s.server = httptest.NewServer(http.HandlerFunc(s.handleLeaseRequest))
+}
+
+func (s *HTTPLockTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *HTTPLockTestSuite) TestTryAcquire() {
+	first := NewHTTPLock(s.server.URL, "first", time.Minute)
+	second := NewHTTPLock(s.server.URL, "second", time.Minute)
+
+	acquired, err := first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.False(acquired)
+}
+
+func (s *HTTPLockTestSuite) TestRelease() {
+	first := NewHTTPLock(s.server.URL, "first", time.Minute)
+	second := NewHTTPLock(s.server.URL, "second", time.Minute)
+
+	acquired, err := first.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+
+	err = first.Release(context.Background())
+	s.NoError(err)
+
+	acquired, err = second.TryAcquire(context.Background())
+	s.NoError(err)
+	s.True(acquired)
+}
+
+func (s *HTTPLockTestSuite) TestTryAcquire_UnexpectedResponse() {
+	lock := NewHTTPLock(s.server.URL+"/missing", "first", time.Minute)
+
+	_, err := lock.TryAcquire(context.Background())
+	s.ErrorIs(err, ErrUnexpectedLeaseResponse)
+}
+
+func (s *HTTPLockTestSuite) handleLeaseRequest(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var request leaseRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	s.NoError(err)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		if s.holder != "" && s.holder != request.Holder {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.holder = request.Holder
+	case http.MethodDelete:
+		if s.holder == request.Holder {
+			s.holder = ""
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestHTTPLockTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPLockTestSuite))
+}
@@ -0,0 +1,46 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	FileBackend = "file"
+	HTTPBackend = "http"
+)
+
+var ErrUnknownBackend = fmt.Errorf("unknown leader election backend")
+
+// Lock is a lease which can be held by a single commander at a time. The lease expires
+// unless its holder renews it within the lease duration.
+type Lock interface {
+	// TryAcquire acquires or renews the lease, it returns false when the lease is held by another node
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release gives up the lease if it is held by this node
+	Release(ctx context.Context) error
+}
+
+func NewLock(cfg *config.LeaderElectionConfig) (Lock, error) {
+	switch cfg.Backend {
+	case FileBackend:
+		return NewFileLock(cfg.FilePath, cfg.NodeID, cfg.LeaseDuration), nil
+	case HTTPBackend:
+		return NewHTTPLock(cfg.URL, cfg.NodeID, cfg.LeaseDuration), nil
+	default:
+		return nil, errors.WithMessagef(ErrUnknownBackend, "%q", cfg.Backend)
+	}
+}
+
+type lease struct {
+	Holder    string
+	ExpiresAt time.Time
+}
+
+func (l *lease) isHeldByOther(nodeID string, now time.Time) bool {
+	return l.Holder != "" && l.Holder != nodeID && now.Before(l.ExpiresAt)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/Worldcoin/hubble-commander/eth/chain"
 	"github.com/Worldcoin/hubble-commander/metrics"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -77,6 +79,12 @@ func (c *Commander) newBlockLoopIteration(currentBlock *types.Header) error {
 		return err
 	}
 
+	if !c.elector.IsLeader() {
+		// standby commanders only keep syncing, the leader sends all transactions
+		c.manageRollupLoop(false)
+		return nil
+	}
+
 	err = c.withdrawRemainingStakes(currentBlock.Number.Uint64())
 	if err != nil {
 		return errors.WithStack(err)
@@ -91,6 +99,13 @@ func (c *Commander) newBlockLoopIteration(currentBlock *types.Header) error {
 		return errors.WithStack(err)
 	}
 
+	if isProposer {
+		err = c.checkOperatorBalance()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
 	c.manageRollupLoop(isProposer)
 	return nil
 }
@@ -110,6 +125,9 @@ func (c *Commander) keepRollingBackIfNecessary() (err error) {
 		return err
 	}
 	if c.invalidBatchID != nil {
+		if !c.elector.IsLeader() {
+			return ErrRollbackInProgress
+		}
 		err = c.client.KeepRollingBack()
 		if err != nil {
 			return err
@@ -248,18 +266,39 @@ func (c *Commander) withdrawRemainingStakes(currentBlock uint64) error {
 		return err
 	}
 	for i := range stakes {
-		_, err = c.client.WithdrawStake(&stakes[i].BatchID)
-		if err != nil {
-			return err
-		}
-		err = c.storage.RemovePendingStakeWithdrawal(stakes[i].BatchID)
+		err = c.withdrawStake(&stakes[i].BatchID)
 		if err != nil {
 			return err
 		}
 	}
+	return c.updateLockedStakeMetric()
+}
+
+func (c *Commander) withdrawStake(batchID *models.Uint256) error {
+	stake, err := c.storage.TakePendingStakeWithdrawal(*batchID)
+	if st.IsNotFoundError(err) {
+		// already withdrawn with admin_withdrawStake
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.WithdrawStake(batchID)
+	if err != nil {
+		return restorePendingStakeWithdrawal(c.storage, stake, err)
+	}
 	return nil
 }
 
+func restorePendingStakeWithdrawal(storage *st.Storage, stake *models.PendingStakeWithdrawal, withdrawErr error) error {
+	err := storage.AddPendingStakeWithdrawal(stake)
+	if err != nil {
+		log.Errorf("Failed to restore pending stake withdrawal of batch #%s: %+v", stake.BatchID.String(), err)
+	}
+	return withdrawErr
+}
+
 func min(x, y uint64) uint64 {
 	if x < y {
 		return x
@@ -0,0 +1,45 @@
+package commander
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+	log "github.com/sirupsen/logrus"
+)
+
+func (c *Commander) pruningLoop() error {
+	ticker := time.NewTicker(c.cfg.Pruning.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.workersContext.Done():
+			return nil
+		case <-ticker.C:
+			c.pruneStorage()
+		}
+	}
+}
+
+// pruneStorage only logs failures, pruning is retried on the next tick and must never stop the commander.
+// The state mutex is held while each chunk is deleted so that pruning never interleaves with a revert.
+func (c *Commander) pruneStorage() {
+	result, err := c.storage.PruneStateUpdates(c.cfg.Pruning.SafetyMargin, &c.stateMutex)
+	if err != nil {
+		log.Warnf("Failed to prune state updates: %+v", err)
+		return
+	}
+	if result.BatchID == nil || result.PrunedStateUpdates == 0 {
+		return
+	}
+
+	c.metrics.SavePruningResult(result.BatchID, result.PrunedStateUpdates, result.ReclaimedBytes)
+	log.Infof(
+		"Pruned %d state update(s) up to batch #%d, reclaimed %s",
+		result.PrunedStateUpdates,
+		result.BatchID.Uint64(),
+		humanize.IBytes(result.ReclaimedBytes),
+	)
+
+	c.runBadgerGC()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Worldcoin/hubble-commander/contracts/spokeregistry"
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/eth"
 	"github.com/Worldcoin/hubble-commander/metrics"
 	"github.com/Worldcoin/hubble-commander/models"
@@ -13,7 +14,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (c *Commander) syncSpokes(ctx context.Context, startBlock, endBlock uint64) error {
@@ -83,7 +83,7 @@ func (c *Commander) getSpokeRegisteredIterator(start, end uint64) (*spokeregistr
 
 func saveSyncedSpoke(storage *st.RegisteredSpokeStorage, spoke *models.RegisteredSpoke) (isNewSpoke *bool, err error) {
 	err = storage.AddRegisteredSpoke(spoke)
-	if errors.Is(err, bh.ErrKeyExists) {
+	if errors.Is(err, db.ErrKeyExists) {
 		return ref.Bool(false), nil
 	}
 	if err != nil {
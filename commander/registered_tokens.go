@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/Worldcoin/hubble-commander/contracts/tokenregistry"
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/eth"
 	"github.com/Worldcoin/hubble-commander/metrics"
 	"github.com/Worldcoin/hubble-commander/models"
@@ -13,7 +14,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (c *Commander) syncTokens(ctx context.Context, startBlock, endBlock uint64) error {
@@ -88,7 +88,7 @@ func (c *Commander) getRegisteredTokenIterator(start, end uint64) (*tokenregistr
 
 func saveSyncedToken(storage *st.RegisteredTokenStorage, token *models.RegisteredToken) (isNewToken *bool, err error) {
 	err = storage.AddRegisteredToken(token)
-	if errors.Is(err, bh.ErrKeyExists) {
+	if errors.Is(err, db.ErrKeyExists) {
 		return ref.Bool(false), nil
 	}
 	if err != nil {
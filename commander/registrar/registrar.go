@@ -0,0 +1,299 @@
+package registrar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrRegistrationDisabled  = fmt.Errorf("public key registration is disabled")
+	ErrRateLimitExceeded     = fmt.Errorf("public key registration rate limit exceeded")
+	ErrPublicKeyNotAllowed   = fmt.Errorf("public key is not on the registration allowlist")
+	ErrRegistrationCancelled = fmt.Errorf("public key registration was cancelled")
+	ErrNotFlushing           = fmt.Errorf("public key registrations are only processed by the active proposer")
+	ErrPubKeyIDMismatch      = fmt.Errorf("registered pub key IDs do not match the reserved ones")
+)
+
+// Registrar queues public key registrations and submits them to the AccountRegistry
+// in batches of st.AccountBatchSize public keys. Pending registrations are flushed
+// from the rollup loop so that their pub key IDs are reserved in the same way as
+// the ones of accounts created by Create2Transfers.
+type Registrar struct {
+	cfg       *config.RegistrationConfig
+	limiter   *rate.Limiter
+	allowlist map[models.PublicKey]bool
+
+	mutex    sync.Mutex
+	pending  []*Registration
+	flushing bool
+
+	// resyncFromPubKeyID is the first pub key ID of the earliest reservation which did not end up registered
+	// under the reserved pub key IDs, the accounts from it on have to be synced again from the chain
+	resyncFromPubKeyID *uint32
+	awaiting           sync.WaitGroup
+}
+
+func NewRegistrar(cfg *config.RegistrationConfig) *Registrar {
+	limit := rate.Inf
+	if cfg.RateLimit > 0 {
+		limit = rate.Limit(cfg.RateLimit)
+	}
+
+	allowlist := make(map[models.PublicKey]bool, len(cfg.Allowlist))
+	for i := range cfg.Allowlist {
+		allowlist[cfg.Allowlist[i]] = true
+	}
+
+	return &Registrar{
+		cfg:       cfg,
+		limiter:   rate.NewLimiter(limit, cfg.RateLimitBurst),
+		allowlist: allowlist,
+		pending:   make([]*Registration, 0, st.AccountBatchSize),
+	}
+}
+
+// Register queues a registration of the given public key. Registrations of the same
+// public key which are still pending are merged into one.
+func (r *Registrar) Register(publicKey *models.PublicKey) (*Registration, error) {
+	if !r.cfg.Enabled {
+		return nil, errors.WithStack(ErrRegistrationDisabled)
+	}
+	if len(r.allowlist) > 0 && !r.allowlist[*publicKey] {
+		return nil, errors.WithStack(ErrPublicKeyNotAllowed)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.flushing {
+		return nil, errors.WithStack(ErrNotFlushing)
+	}
+
+	for i := range r.pending {
+		if r.pending[i].PublicKey == *publicKey {
+			return r.pending[i], nil
+		}
+	}
+
+	if !r.limiter.Allow() {
+		return nil, errors.WithStack(ErrRateLimitExceeded)
+	}
+
+	registration := newRegistration(publicKey)
+	r.pending = append(r.pending, registration)
+	return registration, nil
+}
+
+// SetFlushing is called when the rollup loop starts and stops. Registrations are rejected while
+// the rollup loop is not running, and the pending ones are failed once it stops.
+func (r *Registrar) SetFlushing(flushing bool) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.flushing = flushing
+	if !flushing {
+		failRegistrations(r.pending, ErrNotFlushing)
+		r.pending = make([]*Registration, 0, st.AccountBatchSize)
+	}
+}
+
+func (r *Registrar) PendingCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.pending)
+}
+
+// Flush submits a registerBatch transaction once there are enough pending registrations
+// to fill a batch or the oldest one waited longer than RegistrationConfig.MaxDelay.
+// It must be called while holding the same lock as batch creation, otherwise reserved
+// pub key IDs may collide with the ones assigned to new Create2Transfer receivers.
+// The pub key IDs are reserved in the same database transaction in which the registration
+// transaction is sent, if sending fails the registrations fail and the reservation is rolled back.
+func (r *Registrar) Flush(ctx context.Context, storage *st.Storage, client *eth.Client) {
+	if r == nil {
+		return
+	}
+
+	registrations := r.takeRegistrationsToFlush()
+	if len(registrations) == 0 {
+		return
+	}
+
+	var accounts []models.AccountLeaf
+	var tx *types.Transaction
+	err := storage.ExecuteInTransaction(st.TxOptions{}, func(txStorage *st.Storage) (err error) {
+		accounts, err = reservePubKeyIDs(txStorage, registrations)
+		if err != nil {
+			return err
+		}
+
+		publicKeys := make([]models.PublicKey, 0, len(accounts))
+		for i := range accounts {
+			publicKeys = append(publicKeys, accounts[i].PublicKey)
+		}
+		tx, err = client.RegisterBatchAccount(ctx, publicKeys)
+		return err
+	})
+	if err != nil {
+		log.Errorf("Failed to submit a batch account registration transaction with %d queued public key(s): %+v", len(registrations), err)
+		failRegistrations(registrations, err)
+		return
+	}
+	log.Debugf(
+		"Submitted a batch account registration transaction with %d queued public key(s). Transaction nonce: %d, hash: %v",
+		len(registrations),
+		tx.Nonce(),
+		tx.Hash(),
+	)
+
+	r.awaiting.Add(1)
+	go func() {
+		defer r.awaiting.Done()
+		r.awaitRegistrations(client, tx, registrations, accounts)
+	}()
+}
+
+// awaitRegistrations completes the registrations once the transaction is mined. When the transaction fails
+// or the AccountRegistry assigned other pub key IDs than the reserved ones the registrations fail, and the
+// reserved accounts are resynced by the next TakeResyncPubKeyID caller.
+func (r *Registrar) awaitRegistrations(
+	client *eth.Client,
+	tx *types.Transaction,
+	registrations []*Registration,
+	accounts []models.AccountLeaf,
+) {
+	pubKeyIDs, err := waitForRegisteredPubKeyIDs(client, tx)
+	if err == nil && pubKeyIDs[0] != accounts[0].PubKeyID {
+		err = errors.WithMessagef(
+			ErrPubKeyIDMismatch,
+			"registered pub key IDs start at %d instead of the reserved %d",
+			pubKeyIDs[0],
+			accounts[0].PubKeyID,
+		)
+	}
+	if err != nil {
+		log.Errorf("Batch account registration transaction %v failed, resyncing accounts: %+v", tx.Hash(), err)
+		failRegistrations(registrations, err)
+		r.requireResync(accounts[0].PubKeyID)
+		return
+	}
+
+	for i := range registrations {
+		registrations[i].complete(&pubKeyIDs[i], nil)
+	}
+}
+
+func waitForRegisteredPubKeyIDs(client *eth.Client, tx *types.Transaction) ([]uint32, error) {
+	receipt, err := client.WaitToBeMined(tx)
+	if err != nil {
+		return nil, err
+	}
+	return client.RetrieveRegisteredPubKeyIDs(receipt)
+}
+
+func (r *Registrar) requireResync(pubKeyID uint32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.resyncFromPubKeyID == nil || pubKeyID < *r.resyncFromPubKeyID {
+		r.resyncFromPubKeyID = &pubKeyID
+	}
+}
+
+// TakeResyncPubKeyID returns the pub key ID from which the accounts have to be synced again from the chain
+// because some of the reserved ones were not registered, or nil when they are all consistent. It must be
+// called while holding the same lock as Flush.
+func (r *Registrar) TakeResyncPubKeyID() *uint32 {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pubKeyID := r.resyncFromPubKeyID
+	r.resyncFromPubKeyID = nil
+	return pubKeyID
+}
+
+func (r *Registrar) takeRegistrationsToFlush() []*Registration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+	if len(r.pending) < st.AccountBatchSize && time.Since(r.pending[0].queuedAt) < r.cfg.MaxDelay {
+		return nil
+	}
+
+	count := st.AccountBatchSize
+	if len(r.pending) < count {
+		count = len(r.pending)
+	}
+	registrations := r.pending[:count]
+	r.pending = append(make([]*Registration, 0, st.AccountBatchSize), r.pending[count:]...)
+	return registrations
+}
+
+// Cancel fails all pending registrations and waits for the submitted ones, it is called when the commander stops
+func (r *Registrar) Cancel() {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	r.flushing = false
+	failRegistrations(r.pending, ErrRegistrationCancelled)
+	r.pending = make([]*Registration, 0, st.AccountBatchSize)
+	r.mutex.Unlock()
+
+	r.awaiting.Wait()
+}
+
+func reservePubKeyIDs(storage *st.Storage, registrations []*Registration) ([]models.AccountLeaf, error) {
+	nextPubKeyID, err := storage.AccountTree.NextBatchAccountPubKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]models.AccountLeaf, 0, st.AccountBatchSize)
+	for i := 0; i < st.AccountBatchSize; i++ {
+		publicKey := models.ZeroPublicKey
+		if i < len(registrations) {
+			publicKey = registrations[i].PublicKey
+		}
+		accounts = append(accounts, models.AccountLeaf{
+			PubKeyID:  *nextPubKeyID + uint32(i),
+			PublicKey: publicKey,
+		})
+	}
+
+	err = storage.AccountTree.SetInBatch(accounts...)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func failRegistrations(registrations []*Registration, err error) {
+	for i := range registrations {
+		registrations[i].complete(nil, err)
+	}
+}
@@ -0,0 +1,182 @@
+package registrar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistrarTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	cfg       *config.RegistrationConfig
+	registrar *Registrar
+	storage   *st.TestStorage
+	client    *eth.TestClient
+}
+
+func (s *RegistrarTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *RegistrarTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	s.client, err = eth.NewTestClient()
+	s.NoError(err)
+
+	s.cfg = &config.RegistrationConfig{
+		Enabled:        true,
+		MaxDelay:       time.Hour,
+		RateLimitBurst: 1,
+	}
+	s.registrar = NewRegistrar(s.cfg)
+	s.registrar.SetFlushing(true)
+}
+
+func (s *RegistrarTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *RegistrarTestSuite) TestRegister_Disabled() {
+	s.cfg.Enabled = false
+
+	_, err := s.registrar.Register(&models.PublicKey{1, 2, 3})
+	s.ErrorIs(err, ErrRegistrationDisabled)
+}
+
+func (s *RegistrarTestSuite) TestRegister_PublicKeyNotOnAllowlist() {
+	s.cfg.Allowlist = []models.PublicKey{{1, 2, 3}}
+	s.registrar = NewRegistrar(s.cfg)
+	s.registrar.SetFlushing(true)
+
+	_, err := s.registrar.Register(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+
+	_, err = s.registrar.Register(&models.PublicKey{2, 3, 4})
+	s.ErrorIs(err, ErrPublicKeyNotAllowed)
+}
+
+func (s *RegistrarTestSuite) TestRegister_RateLimitExceeded() {
+	s.cfg.RateLimit = 0.001
+	s.registrar = NewRegistrar(s.cfg)
+	s.registrar.SetFlushing(true)
+
+	_, err := s.registrar.Register(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+
+	_, err = s.registrar.Register(&models.PublicKey{2, 3, 4})
+	s.ErrorIs(err, ErrRateLimitExceeded)
+}
+
+func (s *RegistrarTestSuite) TestRegister_MergesPendingRegistrationsOfTheSamePublicKey() {
+	first, err := s.registrar.Register(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+	second, err := s.registrar.Register(&models.PublicKey{1, 2, 3})
+	s.NoError(err)
+
+	s.Equal(first, second)
+	s.Equal(1, s.registrar.PendingCount())
+}
+
+func (s *RegistrarTestSuite) TestFlush_DoesNothingUntilBatchIsFull() {
+	s.registerPublicKeys(st.AccountBatchSize - 1)
+
+	s.registrar.Flush(context.Background(), s.storage.Storage, s.client.Client)
+	s.Equal(st.AccountBatchSize-1, s.registrar.PendingCount())
+}
+
+func (s *RegistrarTestSuite) TestFlush_RegistersFullBatch() {
+	registrations := s.registerPublicKeys(st.AccountBatchSize + 1)
+
+	s.registrar.Flush(context.Background(), s.storage.Storage, s.client.Client)
+	s.Equal(1, s.registrar.PendingCount())
+
+	for i := 0; i < st.AccountBatchSize; i++ {
+		pubKeyID, err := registrations[i].Wait(context.Background())
+		s.NoError(err)
+		s.EqualValues(st.AccountBatchOffset+i, *pubKeyID)
+
+		leaf, err := s.storage.AccountTree.Leaf(*pubKeyID)
+		s.NoError(err)
+		s.Equal(registrations[i].PublicKey, leaf.PublicKey)
+	}
+}
+
+func (s *RegistrarTestSuite) TestFlush_PadsPartialBatchAfterMaxDelay() {
+	s.cfg.MaxDelay = 0
+	registrations := s.registerPublicKeys(2)
+
+	s.registrar.Flush(context.Background(), s.storage.Storage, s.client.Client)
+	s.Equal(0, s.registrar.PendingCount())
+
+	pubKeyID, err := registrations[1].Wait(context.Background())
+	s.NoError(err)
+	s.EqualValues(st.AccountBatchOffset+1, *pubKeyID)
+
+	nextPubKeyID, err := s.storage.AccountTree.NextBatchAccountPubKeyID()
+	s.NoError(err)
+	s.EqualValues(st.AccountBatchOffset+st.AccountBatchSize, *nextPubKeyID)
+}
+
+func (s *RegistrarTestSuite) TestRegister_NotFlushing() {
+	registrations := s.registerPublicKeys(1)
+
+	s.registrar.SetFlushing(false)
+
+	_, err := registrations[0].Wait(context.Background())
+	s.ErrorIs(err, ErrNotFlushing)
+
+	_, err = s.registrar.Register(&models.PublicKey{2, 3, 4})
+	s.ErrorIs(err, ErrNotFlushing)
+}
+
+func (s *RegistrarTestSuite) TestFlush_FailsRegistrationsWhenPubKeyIDsDoNotMatch() {
+	s.cfg.MaxDelay = 0
+	_, err := s.client.RegisterBatchAccountAndWait(make([]models.PublicKey, st.AccountBatchSize))
+	s.NoError(err)
+
+	registrations := s.registerPublicKeys(1)
+	s.registrar.Flush(context.Background(), s.storage.Storage, s.client.Client)
+
+	_, err = registrations[0].Wait(context.Background())
+	s.ErrorIs(err, ErrPubKeyIDMismatch)
+
+	resyncPubKeyID := s.registrar.TakeResyncPubKeyID()
+	s.NotNil(resyncPubKeyID)
+	s.EqualValues(st.AccountBatchOffset, *resyncPubKeyID)
+	s.Nil(s.registrar.TakeResyncPubKeyID())
+}
+
+func (s *RegistrarTestSuite) TestCancel_FailsPendingRegistrations() {
+	registrations := s.registerPublicKeys(1)
+
+	s.registrar.Cancel()
+
+	_, err := registrations[0].Wait(context.Background())
+	s.ErrorIs(err, ErrRegistrationCancelled)
+}
+
+func (s *RegistrarTestSuite) registerPublicKeys(count int) []*Registration {
+	registrations := make([]*Registration, 0, count)
+	for i := 0; i < count; i++ {
+		registration, err := s.registrar.Register(&models.PublicKey{1, 2, byte(i)})
+		s.NoError(err)
+		registrations = append(registrations, registration)
+	}
+	return registrations
+}
+
+func TestRegistrarTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistrarTestSuite))
+}
@@ -0,0 +1,45 @@
+package registrar
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/models"
+)
+
+type Registration struct {
+	PublicKey models.PublicKey
+	queuedAt  time.Time
+
+	once     sync.Once
+	done     chan struct{}
+	pubKeyID *uint32
+	err      error
+}
+
+func newRegistration(publicKey *models.PublicKey) *Registration {
+	return &Registration{
+		PublicKey: *publicKey,
+		queuedAt:  time.Now(),
+		done:      make(chan struct{}),
+	}
+}
+
+func (r *Registration) complete(pubKeyID *uint32, err error) {
+	r.once.Do(func() {
+		r.pubKeyID = pubKeyID
+		r.err = err
+		close(r.done)
+	})
+}
+
+// Wait blocks until the registration transaction is mined and returns the assigned pub key ID
+func (r *Registration) Wait(ctx context.Context) (*uint32, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.done:
+		return r.pubKeyID, r.err
+	}
+}
@@ -0,0 +1,85 @@
+package commander
+
+import (
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/api"
+	"github.com/Worldcoin/hubble-commander/api/replication"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const replicaResyncDelay = 5 * time.Second
+
+// startReplica serves the hubble API from a copy of the database of the primary commander.
+// It neither syncs with the chain nor creates batches, the schema is migrated by the primary.
+func (c *Commander) startReplica() (err error) {
+	c.storage, err = st.NewStorage(c.cfg)
+	if err != nil {
+		return err
+	}
+
+	primaryURL := c.cfg.Replica.PrimaryURL
+	follower := replication.NewFollower(primaryURL, c.cfg.API.AuthenticationKey, c.storage)
+	err = follower.Sync(c.workersContext)
+	if err != nil {
+		return err
+	}
+
+	c.client, err = getClient(c.blockchain, c.storage, c.cfg, c.metrics, c.txsTrackingChannels)
+	if err != nil {
+		return err
+	}
+
+	c.metricsServer = c.metrics.NewServer(c.cfg.Metrics)
+
+	c.apiServer, err = api.NewReplicaServer(c.cfg, c.storage, c.client, c.metrics, primaryURL)
+	if err != nil {
+		return err
+	}
+
+	err = c.startServers()
+	if err != nil {
+		return err
+	}
+
+	c.startWorker("Replication", func() error { return c.replicationLoop(follower) })
+	c.startWorker("Badger Garbage Colection", func() error { return c.badgerGCLoop() })
+
+	go c.handleWorkerError()
+
+	log.Printf("Replica of %s started and listening on port %s", primaryURL, c.cfg.API.Port)
+	c.setActive(true)
+	return nil
+}
+
+// replicationLoop resyncs with the primary whenever the replication stream breaks, the API keeps serving
+// the data replicated so far in the meantime
+func (c *Commander) replicationLoop(follower *replication.Follower) error {
+	defer follower.Close()
+
+	for {
+		err := follower.Follow()
+		if c.workersContext.Err() != nil {
+			return nil
+		}
+		log.WithError(err).Warn("Lost the replication stream of the primary commander, resyncing")
+
+		for {
+			select {
+			case <-c.workersContext.Done():
+				return nil
+			case <-time.After(replicaResyncDelay):
+			}
+
+			err = follower.Sync(c.workersContext)
+			if err == nil {
+				break
+			}
+			if c.workersContext.Err() != nil {
+				return nil
+			}
+			log.WithError(err).Warn("Failed to resync with the primary commander")
+		}
+	}
+}
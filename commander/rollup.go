@@ -17,7 +17,7 @@ import (
 
 func (c *Commander) manageRollupLoop(isProposer bool) {
 	rollupLoopRunning := c.isRollupLoopActive()
-	if isProposer && !rollupLoopRunning && c.batchCreationEnabled {
+	if isProposer && !rollupLoopRunning && c.isBatchCreationEnabled() {
 		log.Debugf("Commander is an active proposer, starting rollupLoop")
 		c.startRollupLoop()
 	} else if !isProposer && rollupLoopRunning {
@@ -35,6 +35,7 @@ func (c *Commander) startRollupLoop() {
 	c.startWorker("Rollup Loop", func() error { return c.rollupLoop(ctx) })
 	c.cancelRollupLoop = cancel
 	c.setRollupLoopActive(true)
+	c.registrar.SetFlushing(true)
 }
 
 func (c *Commander) stopRollupLoop() {
@@ -45,6 +46,7 @@ func (c *Commander) stopRollupLoop() {
 		c.cancelRollupLoop()
 	}
 	c.setRollupLoopActive(false)
+	c.registrar.SetFlushing(false)
 }
 
 func (c *Commander) rollupLoop(ctx context.Context) (err error) {
@@ -70,6 +72,11 @@ func (c *Commander) rollupLoopIteration(ctx context.Context, currentBatchType *b
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	err = c.unsafeFlushRegistrations(ctx)
+	if err != nil {
+		return err
+	}
+
 	err = c.unsafeRollupLoopIteration(ctx, currentBatchType)
 	if errors.Is(err, executor.ErrNotEnoughDeposits) {
 		return c.unsafeRollupLoopIteration(ctx, currentBatchType)
@@ -2,24 +2,59 @@ package commander
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 )
 
 // nolint:structcheck
 type rollupControls struct {
+	// batchCreationEnabled is the choice of the operator, pausedOnLowBalance is set by the balance watcher.
+	// Batches are created only when enabled and not paused.
+	batchCreationMutex   sync.Mutex
 	batchCreationEnabled bool
-	migrate              uint32
+	pausedOnLowBalance   bool
+
+	migrate uint32
 
 	rollupLoopActive uint32
 	cancelRollupLoop context.CancelFunc
 }
 
 func makeRollupControls(migrate bool) rollupControls {
-	controls := rollupControls{
+	return rollupControls{
 		batchCreationEnabled: true,
+		migrate:              atomicFlag(migrate),
 	}
-	controls.setMigrate(migrate)
-	return controls
+}
+
+func (c *rollupControls) isBatchCreationEnabled() bool {
+	c.batchCreationMutex.Lock()
+	defer c.batchCreationMutex.Unlock()
+	return c.batchCreationEnabled && !c.pausedOnLowBalance
+}
+
+// batchCreationStatus returns the choice of the operator and whether batch creation is paused on low balance
+func (c *rollupControls) batchCreationStatus() (enabled, paused bool) {
+	c.batchCreationMutex.Lock()
+	defer c.batchCreationMutex.Unlock()
+	return c.batchCreationEnabled, c.pausedOnLowBalance
+}
+
+func (c *rollupControls) setBatchCreationEnabled(enabled bool) {
+	c.batchCreationMutex.Lock()
+	defer c.batchCreationMutex.Unlock()
+	c.batchCreationEnabled = enabled
+}
+
+// setPausedOnLowBalance returns false when batch creation was already in the requested state
+func (c *rollupControls) setPausedOnLowBalance(paused bool) bool {
+	c.batchCreationMutex.Lock()
+	defer c.batchCreationMutex.Unlock()
+	if c.pausedOnLowBalance == paused {
+		return false
+	}
+	c.pausedOnLowBalance = paused
+	return true
 }
 
 func (c *rollupControls) isRollupLoopActive() bool {
@@ -27,11 +62,7 @@ func (c *rollupControls) isRollupLoopActive() bool {
 }
 
 func (c *rollupControls) setRollupLoopActive(active bool) {
-	activeFlag := uint32(0)
-	if active {
-		activeFlag = 1
-	}
-	atomic.StoreUint32(&c.rollupLoopActive, activeFlag)
+	atomic.StoreUint32(&c.rollupLoopActive, atomicFlag(active))
 }
 
 func (c *rollupControls) isMigrating() bool {
@@ -39,9 +70,12 @@ func (c *rollupControls) isMigrating() bool {
 }
 
 func (c *rollupControls) setMigrate(migrate bool) {
-	migrateFlag := uint32(0)
-	if migrate {
-		migrateFlag = 1
+	atomic.StoreUint32(&c.migrate, atomicFlag(migrate))
+}
+
+func atomicFlag(value bool) uint32 {
+	if value {
+		return 1
 	}
-	atomic.StoreUint32(&c.migrate, migrateFlag)
+	return 0
 }
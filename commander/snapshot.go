@@ -0,0 +1,244 @@
+package commander
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// log queries are cheap compared to syncing batches, so deposits are synced in larger ranges
+const snapshotDepositsSyncSize = 10_000
+
+var (
+	errInconsistentSnapshotChainID = NewInconsistentChainIDError("snapshot")
+
+	ErrSnapshotChainStateMismatch    = fmt.Errorf("chain state of the snapshot doesn't match the chain spec file or bootstrap node")
+	ErrSnapshotBatchNotFinalised     = fmt.Errorf("snapshot batch is not finalised on chain")
+	ErrInvalidSnapshotCommitmentRoot = fmt.Errorf("commitments of the snapshot don't match the batch submitted on chain")
+	ErrInvalidSnapshotStateRoot      = fmt.Errorf("state root of the snapshot doesn't match the snapshot batch")
+)
+
+// getClientFromSnapshot imports the snapshot into an empty database. The contracts are taken from the chain spec
+// file or bootstrap node, the snapshot has to match them. Once imported the commander continues like it would
+// from its own database, so the snapshot is ignored on later restarts.
+func (c *Commander) getClientFromSnapshot(snapshotPath string) (*eth.Client, error) {
+	dbChainState, err := c.storage.GetChainState()
+	if err != nil && !st.IsNotFoundError(err) {
+		return nil, err
+	}
+	if dbChainState != nil {
+		log.Warn("Database is already initialised, ignoring the snapshot")
+		return getClient(c.blockchain, c.storage, c.cfg, c.metrics, c.txsTrackingChannels)
+	}
+
+	chainState, err := getBootstrapChainState(c.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(snapshotPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	err = c.bootstrapFromSnapshot(chainState, st.NewSnapshotReader(bufio.NewReader(file)))
+	if err != nil {
+		return nil, err
+	}
+	return c.client, nil
+}
+
+// getBootstrapChainState returns the chain state of the configured chain spec file or bootstrap node
+func getBootstrapChainState(cfg *config.Config) (*models.ChainState, error) {
+	if cfg.Bootstrap.ChainSpecPath != nil {
+		chainSpec, err := ReadChainSpecFile(*cfg.Bootstrap.ChainSpecPath)
+		if err != nil {
+			return nil, err
+		}
+		return newChainStateFromChainSpec(chainSpec), nil
+	}
+	if cfg.Bootstrap.BootstrapNodeURL != nil {
+		return fetchChainStateFromRemoteNode(*cfg.Bootstrap.BootstrapNodeURL)
+	}
+	return nil, errors.WithStack(errMissingBootstrapSource)
+}
+
+// bootstrapFromSnapshot imports the snapshot and verifies it against the chain, all imported data is
+// removed when either of them fails
+func (c *Commander) bootstrapFromSnapshot(chainState *models.ChainState, snapshot *st.SnapshotReader) error {
+	err := c.importSnapshot(chainState, snapshot)
+	if err != nil {
+		dropErr := c.storage.DropImportedSnapshot()
+		if dropErr != nil {
+			log.Errorf("Failed to remove the imported snapshot: %+v", dropErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *Commander) importSnapshot(chainState *models.ChainState, snapshot *st.SnapshotReader) error {
+	header, err := snapshot.ReadHeader()
+	if err != nil {
+		return err
+	}
+
+	if !chainState.ChainID.EqN(c.cfg.Ethereum.ChainID) || c.blockchain.GetChainID() != chainState.ChainID {
+		return errors.WithStack(errInconsistentSnapshotChainID)
+	}
+	if !isSameChain(&header.ChainState, chainState) {
+		return errors.WithStack(ErrSnapshotChainStateMismatch)
+	}
+
+	c.client, err = createClientFromChainState(c.blockchain, chainState, c.cfg, c.metrics, c.txsTrackingChannels)
+	if err != nil {
+		return err
+	}
+
+	batch := &header.Batch
+	log.Printf("Importing snapshot at batch #%d", batch.ID.Uint64())
+	err = c.storage.ImportSnapshot(header, snapshot)
+	if err != nil {
+		return err
+	}
+
+	snapshotBlock, err := verifySnapshotBatch(c.storage, c.client, &batch.ID)
+	if err != nil {
+		return err
+	}
+
+	// the block of the snapshot batch is synced again by the new block loop
+	// in case other batches were submitted in the same block
+	syncedBlock := snapshotBlock - 1
+	err = c.syncSnapshotDeposits(getInitialSyncedBlock(chainState.AccountRegistryDeploymentBlock)+1, syncedBlock)
+	if err != nil {
+		return err
+	}
+	err = removeSubmittedDepositSubtrees(c.storage, header.LastDepositSubtreeID)
+	if err != nil {
+		return err
+	}
+
+	err = c.storage.SetSyncedBlock(syncedBlock)
+	if err != nil {
+		return err
+	}
+	err = c.storage.SetChainState(chainState)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported snapshot at batch #%d, syncing from block %d", batch.ID.Uint64(), snapshotBlock)
+	return nil
+}
+
+// isSameChain compares the chain ID and contracts of the chain states, genesis accounts
+// are always taken from the chain spec file or bootstrap node
+func isSameChain(a, b *models.ChainState) bool {
+	return a.ChainID == b.ChainID &&
+		a.AccountRegistry == b.AccountRegistry &&
+		a.AccountRegistryDeploymentBlock == b.AccountRegistryDeploymentBlock &&
+		a.TokenRegistry == b.TokenRegistry &&
+		a.SpokeRegistry == b.SpokeRegistry &&
+		a.DepositManager == b.DepositManager &&
+		a.WithdrawManager == b.WithdrawManager &&
+		a.Rollup == b.Rollup
+}
+
+// verifySnapshotBatch checks the imported state against the batch submitted on chain
+// and returns the number of the block in which the batch was submitted
+func verifySnapshotBatch(storage *st.Storage, client *eth.Client, batchID *models.Uint256) (uint64, error) {
+	contractBatch, err := client.GetContractBatch(batchID)
+	if err != nil {
+		return 0, err
+	}
+	latestBlockNumber, err := client.Blockchain.GetLatestBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(contractBatch.FinaliseOn) > *latestBlockNumber {
+		return 0, errors.WithStack(ErrSnapshotBatchNotFinalised)
+	}
+
+	commitments, err := storage.GetCommitmentsByBatchID(*batchID)
+	if err != nil {
+		return 0, err
+	}
+	if len(commitments) == 0 {
+		return 0, errors.WithStack(ErrInvalidSnapshotCommitmentRoot)
+	}
+
+	leafHashes := make([]common.Hash, 0, len(commitments))
+	for i := range commitments {
+		leafHashes = append(leafHashes, commitments[i].LeafHash())
+	}
+	tree, err := merkletree.NewMerkleTree(leafHashes)
+	if err != nil {
+		return 0, err
+	}
+	if tree.Root() != contractBatch.Hash {
+		return 0, errors.WithStack(ErrInvalidSnapshotCommitmentRoot)
+	}
+
+	stateRoot, err := storage.StateTree.Root()
+	if err != nil {
+		return 0, err
+	}
+	if commitments[len(commitments)-1].GetPostStateRoot() != *stateRoot {
+		return 0, errors.WithStack(ErrInvalidSnapshotStateRoot)
+	}
+
+	blocksToFinalise, err := client.GetBlocksToFinalise()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(contractBatch.FinaliseOn) - uint64(*blocksToFinalise), nil
+}
+
+// syncSnapshotDeposits replays deposit events, the snapshot doesn't contain deposits which were
+// queued but not yet submitted at the snapshot batch
+func (c *Commander) syncSnapshotDeposits(startBlock, endBlock uint64) error {
+	for start := startBlock; start <= endBlock; start += snapshotDepositsSyncSize {
+		end := min(endBlock, start+snapshotDepositsSyncSize-1)
+		err := c.syncDeposits(context.Background(), start, end)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeSubmittedDepositSubtrees(storage *st.Storage, lastSubtreeID *models.Uint256) error {
+	if lastSubtreeID == nil {
+		return nil
+	}
+
+	for {
+		subtree, err := storage.GetFirstPendingDepositSubtree()
+		if st.IsNotFoundError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if subtree.ID.Cmp(lastSubtreeID) > 0 {
+			return nil
+		}
+
+		err = storage.RemovePendingDepositSubtrees(subtree.ID)
+		if err != nil {
+			return err
+		}
+	}
+}
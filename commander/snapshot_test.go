@@ -0,0 +1,240 @@
+package commander
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/commander/executor"
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/testutils"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SnapshotTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	cmd      *Commander
+	storage  *st.TestStorage
+	client   *eth.TestClient
+	cfg      *config.Config
+	wallets  []bls.Wallet
+	snapshot []byte
+}
+
+func (s *SnapshotTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+	s.cfg = config.GetTestConfig()
+	s.cfg.Rollup.MinCommitmentsPerBatch = 1
+	s.cfg.Rollup.MaxCommitmentsPerBatch = 32
+	s.cfg.Rollup.MinTxsPerCommitment = 1
+	s.cfg.Rollup.MaxTxsPerCommitment = 1
+	s.cfg.Rollup.DisableSignatures = false
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+	setStateLeaves(s.T(), s.storage.Storage)
+	s.client = newClientWithGenesisStateAndFastBlockFinalization(s.T(), s.storage)
+
+	s.cmd = NewCommander(s.cfg, s.client.Blockchain)
+	s.cmd.client = s.client.Client
+	s.cmd.storage = s.storage.Storage
+	err = s.cmd.addGenesisBatch()
+	s.NoError(err)
+
+	domain, err := s.client.GetDomain()
+	s.NoError(err)
+	s.wallets = testutils.GenerateWallets(s.Assertions, domain, 2)
+	setChainState(s.T(), s.storage)
+	setAccountLeaves(s.T(), s.storage.Storage, s.wallets)
+
+	s.snapshot = s.exportSnapshotAfterTransferBatch()
+}
+
+func (s *SnapshotTestSuite) TearDownTest() {
+	s.client.Close()
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *SnapshotTestSuite) TestBootstrapFromSnapshot() {
+	importCmd, storage := s.newImportingCommander()
+	defer func() {
+		s.NoError(storage.Teardown())
+	}()
+
+	err := importCmd.bootstrapFromSnapshot(s.chainState(), s.readSnapshot(nil))
+	s.NoError(err)
+
+	expectedStateRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+	stateRoot, err := storage.StateTree.Root()
+	s.NoError(err)
+	s.Equal(expectedStateRoot, stateRoot)
+
+	batches, err := storage.GetBatchesInRange(nil, nil)
+	s.NoError(err)
+	s.Len(batches, 2)
+
+	chainState, err := storage.GetChainState()
+	s.NoError(err)
+	s.Equal(s.client.ChainState.Rollup, chainState.Rollup)
+
+	contractBatch, err := s.client.GetContractBatch(models.NewUint256(1))
+	s.NoError(err)
+	syncedBlock, err := storage.GetSyncedBlock()
+	s.NoError(err)
+	s.EqualValues(contractBatch.FinaliseOn-2, *syncedBlock)
+}
+
+func (s *SnapshotTestSuite) TestBootstrapFromSnapshot_InvalidStateLeaves() {
+	importCmd, storage := s.newImportingCommander()
+	defer func() {
+		s.NoError(storage.Teardown())
+	}()
+
+	modifiedLeaf := false
+	snapshot := s.readSnapshot(func(_ *models.SnapshotHeader, record *models.SnapshotRecord) {
+		if record != nil && record.StateLeaf != nil && !modifiedLeaf {
+			record.StateLeaf.Balance = models.MakeUint256(1)
+			modifiedLeaf = true
+		}
+	})
+
+	err := importCmd.bootstrapFromSnapshot(s.chainState(), snapshot)
+	s.ErrorIs(err, ErrInvalidSnapshotStateRoot)
+
+	_, err = storage.GetChainState()
+	s.True(st.IsNotFoundError(err))
+	s.requireNoImportedBatches(storage)
+}
+
+func (s *SnapshotTestSuite) TestBootstrapFromSnapshot_ChainIDMismatch() {
+	importCmd, storage := s.newImportingCommander()
+	defer func() {
+		s.NoError(storage.Teardown())
+	}()
+
+	chainState := s.chainState()
+	chainState.ChainID = models.MakeUint256(1)
+
+	err := importCmd.bootstrapFromSnapshot(chainState, s.readSnapshot(nil))
+	s.ErrorIs(err, errInconsistentSnapshotChainID)
+}
+
+func (s *SnapshotTestSuite) TestBootstrapFromSnapshot_ChainStateMismatch() {
+	importCmd, storage := s.newImportingCommander()
+	defer func() {
+		s.NoError(storage.Teardown())
+	}()
+
+	snapshot := s.readSnapshot(func(header *models.SnapshotHeader, _ *models.SnapshotRecord) {
+		if header != nil {
+			header.ChainState.Rollup = utils.RandomAddress()
+		}
+	})
+
+	err := importCmd.bootstrapFromSnapshot(s.chainState(), snapshot)
+	s.ErrorIs(err, ErrSnapshotChainStateMismatch)
+	s.requireNoImportedBatches(storage)
+}
+
+func (s *SnapshotTestSuite) requireNoImportedBatches(storage *st.TestStorage) {
+	batches, err := storage.GetBatchesInRange(nil, nil)
+	s.NoError(err)
+	s.Len(batches, 0)
+}
+
+func (s *SnapshotTestSuite) chainState() *models.ChainState {
+	chainState := s.client.ChainState
+	return &chainState
+}
+
+func (s *SnapshotTestSuite) exportSnapshotAfterTransferBatch() []byte {
+	transfer := testutils.MakeTransfer(0, 1, 0, 400)
+	signTransfer(s.T(), &s.wallets[transfer.FromStateID], &transfer)
+	s.submitTransferBatch(&transfer)
+
+	// mine a block so that the batch gets finalised
+	s.client.GetBackend().Commit()
+	err := s.cmd.syncToLatestBlock()
+	s.NoError(err)
+
+	var snapshot bytes.Buffer
+	header, err := s.storage.ExportSnapshot(&snapshot)
+	s.NoError(err)
+	s.EqualValues(1, header.Batch.ID.Uint64())
+	return snapshot.Bytes()
+}
+
+// readSnapshot re-encodes the exported snapshot with the chain state of the test client,
+// modify is called with the header and then with each record
+func (s *SnapshotTestSuite) readSnapshot(
+	modify func(header *models.SnapshotHeader, record *models.SnapshotRecord),
+) *st.SnapshotReader {
+	decoder := json.NewDecoder(bytes.NewReader(s.snapshot))
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+
+	var header models.SnapshotHeader
+	s.NoError(decoder.Decode(&header))
+	header.ChainState = s.client.ChainState
+	if modify != nil {
+		modify(&header, nil)
+	}
+	s.NoError(encoder.Encode(header))
+
+	for decoder.More() {
+		var record models.SnapshotRecord
+		s.NoError(decoder.Decode(&record))
+		if modify != nil {
+			modify(nil, &record)
+		}
+		s.NoError(encoder.Encode(record))
+	}
+	return st.NewSnapshotReader(&encoded)
+}
+
+func (s *SnapshotTestSuite) submitTransferBatch(tx *models.Transfer) {
+	txController, txStorage := s.storage.BeginTransaction(st.TxOptions{})
+	defer txController.Rollback(nil)
+
+	executionCtx := executor.NewTestExecutionContext(txStorage, s.client.Client, s.cfg.Rollup)
+	txsCtx, err := executor.NewTestTxsContext(executionCtx, batchtype.Transfer)
+	s.NoError(err)
+
+	err = txStorage.AddMempoolTx(tx)
+	s.NoError(err)
+
+	batchData, err := txsCtx.CreateCommitments(context.Background())
+	s.NoError(err)
+	batch, err := txsCtx.NewPendingBatch(batchtype.Transfer)
+	s.NoError(err)
+	err = txsCtx.SubmitBatch(context.Background(), batch, batchData)
+	s.NoError(err)
+	s.client.GetBackend().Commit()
+}
+
+func (s *SnapshotTestSuite) newImportingCommander() (*Commander, *st.TestStorage) {
+	storage, err := st.NewTestStorage()
+	s.NoError(err)
+
+	cmd := NewCommander(s.cfg, s.client.Blockchain)
+	cmd.storage = storage.Storage
+	return cmd, storage
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Worldcoin/hubble-commander/storage"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
 )
 
 func (c *Commander) syncStakeWithdrawals(ctx context.Context, startBlock, endBlock uint64) error {
@@ -42,6 +43,9 @@ func (c *Commander) unmeasuredSyncStakeWithdrawals(startBlock, endBlock uint64)
 			continue
 		}
 
+		stakeAmount := c.client.GetStakeAmount()
+		c.metrics.AddStakeWithdrawn(&stakeAmount)
+
 		// TODO: why are we ignoring the cases where it is not found? Should we
 		//       at least log?
 		err = c.storage.RemovePendingStakeWithdrawal(models.MakeUint256FromBig(*it.Event.BatchID))
@@ -65,3 +69,30 @@ func (c *Commander) getStakeWithdrawIterator(start, end uint64) (*rollup.StakeWi
 
 	return it, nil
 }
+
+func (c *Commander) updateLockedStakeMetric() error {
+	stakes, err := c.storage.GetPendingStakeWithdrawals()
+	if err != nil {
+		return err
+	}
+	stakeAmount := c.client.GetStakeAmount()
+	c.metrics.SaveStakeLocked(&stakeAmount, len(stakes))
+	return nil
+}
+
+// checkOperatorBalance warns when the commander won't be able to stake the next batch it submits
+func (c *Commander) checkOperatorBalance() error {
+	balance, err := c.client.GetBalance()
+	if err != nil {
+		return err
+	}
+	stakeAmount := c.client.GetStakeAmount()
+	if balance.Cmp(&stakeAmount) < 0 {
+		log.Warnf(
+			"Operator balance of %s wei is not enough to cover the stake of %s wei for the next batch",
+			balance.String(),
+			stakeAmount.String(),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package syncer
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// signatureVerifier checks the signatures of the commitments of a batch on a bounded pool of goroutines while the
+// following commitments are being synced. Pairing checks dominate the sync of big batches.
+type signatureVerifier struct {
+	verify  func(job *signatureJob) (reason string, err error)
+	workers chan struct{}
+	wg      sync.WaitGroup
+
+	mutex   sync.Mutex
+	failure *signatureFailure
+}
+
+type signatureFailure struct {
+	job    *signatureJob
+	reason string
+	err    error
+}
+
+func newSignatureVerifier(verify func(job *signatureJob) (reason string, err error)) *signatureVerifier {
+	return &signatureVerifier{
+		verify:  verify,
+		workers: make(chan struct{}, runtime.GOMAXPROCS(0)),
+	}
+}
+
+// submit blocks until one of the workers is free
+func (v *signatureVerifier) submit(job *signatureJob) {
+	if v.failedBefore(job.index) {
+		return
+	}
+
+	v.workers <- struct{}{}
+	v.wg.Add(1)
+	go func() {
+		defer func() {
+			<-v.workers
+			v.wg.Done()
+		}()
+
+		reason, err := v.safeVerify(job)
+		if reason != "" || err != nil {
+			v.fail(&signatureFailure{job: job, reason: reason, err: err})
+		}
+	}()
+}
+
+func (v *signatureVerifier) safeVerify(job *signatureJob) (reason string, err error) {
+	defer func() {
+		if recoverErr := recover(); recoverErr != nil {
+			log.Errorf("stacktrace from signature verification panic: %s", debug.Stack())
+			err = fmt.Errorf("signature verification of commitment #%d failed: %+v", job.index, recoverErr)
+		}
+	}()
+	return v.verify(job)
+}
+
+func (v *signatureVerifier) failedBefore(index int) bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.failure != nil && v.failure.job.index < index
+}
+
+func (v *signatureVerifier) fail(failure *signatureFailure) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.failure == nil || failure.job.index < v.failure.job.index {
+		v.failure = failure
+	}
+}
+
+// wait blocks until all submitted signatures are checked and returns the failure of the first commitment, if any
+func (v *signatureVerifier) wait() *signatureFailure {
+	v.wg.Wait()
+	return v.failure
+}
@@ -21,52 +21,56 @@ const (
 	nonexistentReceiverMessage   = "nonexistent receiver"
 )
 
-func (c *TxsContext) syncTxCommitment(commitment encoder.Commitment) error {
+// syncTxCommitment applies the transactions of the commitment and returns the check of its signature, which is left
+// to the caller. The returned job is nil when signatures are disabled.
+func (c *TxsContext) syncTxCommitment(commitment encoder.Commitment) (*signatureJob, error) {
 	decodedCommitment := commitment.ToDecodedCommitment()
 	if len(decodedCommitment.Transactions)%c.Syncer.TxLength() != 0 {
-		return ErrInvalidDataLength
+		return nil, ErrInvalidDataLength
 	}
 
 	syncedTxs, err := c.Syncer.DeserializeTxs(decodedCommitment.Transactions)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if uint32(syncedTxs.Txs().Len()) > c.cfg.MaxTxsPerCommitment {
-		return ErrTooManyTxs
+		return nil, ErrTooManyTxs
 	}
 
 	appliedTxs, stateProofs, err := c.SyncTxs(syncedTxs, decodedCommitment.FeeReceiver)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	syncedTxs.SetTxs(appliedTxs)
 
 	err = c.verifyStateRoot(decodedCommitment.StateRoot, stateProofs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = c.Syncer.VerifyAmountAndWithdrawRoots(commitment, appliedTxs, stateProofs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = c.Syncer.SetMissingTxsData(commitment, syncedTxs)
 	if st.IsNotFoundError(err) {
-		return c.createDisputableSignatureError(nonexistentReceiverMessage, syncedTxs.Txs())
+		return nil, c.createDisputableSignatureError(nonexistentReceiverMessage, syncedTxs.Txs())
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	var job *signatureJob
 	if !c.cfg.DisableSignatures {
-		err = c.verifyTxSignature(decodedCommitment, syncedTxs.Txs())
+		job, err = c.newSignatureJob(decodedCommitment, syncedTxs.Txs())
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return c.addTxs(syncedTxs.Txs(), &decodedCommitment.ID)
+	return job, c.addTxs(syncedTxs.Txs(), &decodedCommitment.ID)
 }
 
 func (c *TxsContext) verifyStateRoot(commitmentPostState common.Hash, proofs []models.StateMerkleProof) error {
@@ -3,6 +3,7 @@ package syncer
 import (
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
 	st "github.com/Worldcoin/hubble-commander/storage"
 )
@@ -13,6 +14,8 @@ type TxsContext struct {
 	client  *eth.Client
 	Syncer  TransactionSyncer
 	TxType  txtype.TransactionType
+
+	publicKeys map[uint32]*models.PublicKey
 }
 
 func NewTestTxsContext(
@@ -36,5 +39,7 @@ func newTxsContext(
 		client:  client,
 		Syncer:  NewTransactionSyncer(storage, txType),
 		TxType:  txType,
+
+		publicKeys: make(map[uint32]*models.PublicKey),
 	}
 }
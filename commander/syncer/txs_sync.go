@@ -12,20 +12,47 @@ import (
 
 func (c *TxsContext) SyncCommitments(remoteBatch eth.DecodedBatch) error {
 	batch := remoteBatch.ToDecodedTxBatch()
+	verifier := newSignatureVerifier(c.verifySignature)
 	for i := range batch.Commitments {
 		log.WithFields(log.Fields{"batchID": batch.ID.String()}).Debugf("Syncing commitment #%d", i+1)
 
-		err := c.syncCommitment(batch, batch.Commitments[i])
-
-		var disputableErr *DisputableError
-		if errors.As(err, &disputableErr) {
-			return disputableErr.WithCommitmentIndex(i)
-		}
+		job, err := c.syncCommitment(batch, batch.Commitments[i])
 		if err != nil {
-			return err
+			return c.firstCommitmentError(verifier, withCommitmentIndex(err, i))
+		}
+		if job != nil {
+			job.index = i
+			verifier.submit(job)
 		}
 	}
-	return nil
+	return c.firstCommitmentError(verifier, nil)
+}
+
+// firstCommitmentError waits for the signature checks of the synced commitments. An invalid signature takes precedence
+// over syncErr, as it belongs to an earlier commitment. The state tree is reverted to the post state of the commitment
+// with the invalid signature to prove the states of its senders.
+func (c *TxsContext) firstCommitmentError(verifier *signatureVerifier, syncErr error) error {
+	failure := verifier.wait()
+	if failure == nil {
+		return syncErr
+	}
+	if failure.err != nil {
+		return failure.err
+	}
+
+	err := c.storage.StateTree.RevertTo(failure.job.commitment.StateRoot)
+	if err != nil {
+		return err
+	}
+	return withCommitmentIndex(c.createDisputableSignatureError(failure.reason, failure.job.txs), failure.job.index)
+}
+
+func withCommitmentIndex(err error, index int) error {
+	var disputableErr *DisputableError
+	if errors.As(err, &disputableErr) {
+		return disputableErr.WithCommitmentIndex(index)
+	}
+	return err
 }
 
 func (c *TxsContext) UpdateExistingBatch(batch eth.DecodedBatch, prevStateRoot common.Hash) error {
@@ -36,13 +63,13 @@ func (c *TxsContext) UpdateExistingBatch(batch eth.DecodedBatch, prevStateRoot c
 	return c.setCommitmentsBodyHash(batch.ToDecodedTxBatch())
 }
 
-func (c *TxsContext) syncCommitment(batch *eth.DecodedTxBatch, commitment encoder.Commitment) error {
-	err := c.syncTxCommitment(commitment)
+func (c *TxsContext) syncCommitment(batch *eth.DecodedTxBatch, commitment encoder.Commitment) (*signatureJob, error) {
+	job, err := c.syncTxCommitment(commitment)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return c.addCommitment(batch, commitment)
+	return job, c.addCommitment(batch, commitment)
 }
 
 func (c *TxsContext) addCommitment(batch *eth.DecodedTxBatch, encodedCommitment encoder.Commitment) (err error) {
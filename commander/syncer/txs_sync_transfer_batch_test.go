@@ -212,6 +212,46 @@ func (s *SyncTransferBatchTestSuite) TestSyncBatch_InvalidTxSignature() {
 	s.Equal(0, disputableErr.CommitmentIndex)
 }
 
+func (s *SyncTransferBatchTestSuite) TestSyncBatch_InvalidTxSignatureInEarlierCommitment() {
+	txs := []*models.Transfer{
+		testutils.NewTransfer(0, 1, 0, 400),
+		testutils.NewTransfer(0, 1, 1, 100),
+	}
+	signTransfer(s.T(), &s.wallets[1], txs[0])
+	s.setTxHash(txs[0])
+	s.setTxHashAndSign(txs[1])
+	for i := range txs {
+		s.addTx(txs[i])
+	}
+
+	pendingBatch, err := s.txsCtx.NewPendingBatch(s.txsCtx.BatchType)
+	s.NoError(err)
+	commitments, err := s.txsCtx.CreateCommitments(context.Background())
+	s.NoError(err)
+	s.Len(commitments, 2)
+	err = s.txsCtx.SubmitBatch(context.Background(), pendingBatch, commitments)
+	s.NoError(err)
+	s.client.GetBackend().Commit()
+
+	s.recreateDatabase()
+
+	remoteBatches, err := s.client.GetAllBatches()
+	s.NoError(err)
+	s.Len(remoteBatches, 1)
+
+	var disputableErr *DisputableError
+	err = s.syncCtx.SyncBatch(remoteBatches[0])
+	s.ErrorAs(err, &disputableErr)
+	s.Equal(Signature, disputableErr.Type)
+	s.Equal(InvalidSignatureMessage, disputableErr.Reason)
+	s.Equal(0, disputableErr.CommitmentIndex)
+
+	// the sender state is proven as of the post state of the first commitment
+	s.Len(disputableErr.Proofs, 1)
+	s.Equal(models.MakeUint256(1), disputableErr.Proofs[0].UserState.Nonce)
+	s.Equal(models.MakeUint256(1000-400), disputableErr.Proofs[0].UserState.Balance)
+}
+
 func (s *SyncTransferBatchTestSuite) TestSyncBatch_NotValidBLSSignature() {
 	tx := testutils.MakeTransfer(0, 1, 0, 400)
 	s.setTxHash(&tx)
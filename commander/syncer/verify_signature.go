@@ -11,63 +11,107 @@ const (
 	InvalidSignatureMessage = "invalid commitment signature"
 )
 
+// signatureJob holds everything needed to check the signature of a commitment without touching the storage
+type signatureJob struct {
+	index      int
+	commitment *encoder.DecodedCommitment
+	domain     *bls.Domain
+	messages   [][]byte
+	publicKeys []*models.PublicKey
+	txs        models.GenericTransactionArray
+}
+
 func (c *TxsContext) verifyTxSignature(commitment *encoder.DecodedCommitment, txs models.GenericTransactionArray) error {
-	domain, err := c.client.GetDomain()
+	job, err := c.newSignatureJob(commitment, txs)
+	if err != nil {
+		return err
+	}
+	reason, err := c.verifySignature(job)
 	if err != nil {
 		return err
 	}
+	if reason != "" {
+		return c.createDisputableSignatureError(reason, txs)
+	}
+	return nil
+}
+
+func (c *TxsContext) newSignatureJob(
+	commitment *encoder.DecodedCommitment,
+	txs models.GenericTransactionArray,
+) (*signatureJob, error) {
+	domain, err := c.client.GetDomain()
+	if err != nil {
+		return nil, err
+	}
 
 	messages := make([][]byte, txs.Len())
 	publicKeys := make([]*models.PublicKey, txs.Len())
 	for i := 0; i < txs.Len(); i++ {
-		publicKeys[i], err = c.storage.GetPublicKeyByStateID(txs.At(i).GetFromStateID())
+		publicKeys[i], err = c.senderPublicKey(txs.At(i).GetFromStateID())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		messages[i], err = c.Syncer.EncodeTxForSigning(txs.At(i))
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return c.verifyCommitmentSignature(commitment, &commitment.CombinedSignature, domain, messages, publicKeys, txs)
+	return &signatureJob{
+		commitment: commitment,
+		domain:     domain,
+		messages:   messages,
+		publicKeys: publicKeys,
+		txs:        txs,
+	}, nil
 }
 
-func (c *TxsContext) verifyCommitmentSignature(
-	commitment *encoder.DecodedCommitment,
-	signature *models.Signature,
-	domain *bls.Domain,
-	messages [][]byte,
-	publicKeys []*models.PublicKey,
-	txs models.GenericTransactionArray,
-) error {
-	if len(messages) == 0 {
-		return nil
+// senderPublicKey caches the public keys for the duration of the sync, the public key of a state never changes
+func (c *TxsContext) senderPublicKey(stateID uint32) (*models.PublicKey, error) {
+	if publicKey, ok := c.publicKeys[stateID]; ok {
+		return publicKey, nil
+	}
+	publicKey, err := c.storage.GetPublicKeyByStateID(stateID)
+	if err != nil {
+		return nil, err
 	}
-	sig, err := bls.NewSignatureFromBytes(signature.Bytes(), *domain)
+	c.publicKeys[stateID] = publicKey
+	return publicKey, nil
+}
+
+// verifySignature returns the reason the commitment is disputable for, or an empty string if its signature is valid.
+// It doesn't access the storage, so it can run concurrently with the sync of the following commitments.
+func (c *TxsContext) verifySignature(job *signatureJob) (reason string, err error) {
+	if len(job.messages) == 0 {
+		return "", nil
+	}
+	sig, err := bls.NewSignatureFromBytes(job.commitment.CombinedSignature.Bytes(), *job.domain)
 	if err != nil {
-		return c.createDisputableSignatureError(err.Error(), txs)
+		return err.Error(), nil
 	}
 	aggregatedSignature := bls.AggregatedSignature{Signature: sig}
-	isValid, err := aggregatedSignature.Verify(messages, publicKeys)
+	isValid, err := aggregatedSignature.Verify(job.messages, job.publicKeys)
 	if err != nil {
-		return err
+		return "", err
 	}
-	if !isValid {
-		shouldIgnoreFailure :=
-			c.cfg.HackSkipKnownBadSignatures &&
-				(commitment.ID.BatchID.CmpN(65) <= 0 || commitment.ID.BatchID.CmpN(2022) == 0 || commitment.ID.BatchID.CmpN(2024) == 0)
-		if shouldIgnoreFailure {
-			// HACK: Signatures are screwed on the first 65 blocks and block 2022. We just ignore these and continue processing.
-			log.WithFields(log.Fields{
-				"batchId": commitment.ID.BatchID,
-				"index":   commitment.ID.IndexInBatch,
-			}).Error("Invalid aggregate signature, pretending it is valid")
-		} else {
-			return c.createDisputableSignatureError(InvalidSignatureMessage, txs)
-		}
+	if isValid {
+		return "", nil
 	}
-	return nil
+
+	commitment := job.commitment
+	shouldIgnoreFailure :=
+		c.cfg.HackSkipKnownBadSignatures &&
+			(commitment.ID.BatchID.CmpN(65) <= 0 || commitment.ID.BatchID.CmpN(2022) == 0 || commitment.ID.BatchID.CmpN(2024) == 0)
+	if shouldIgnoreFailure {
+		// HACK: Signatures are screwed on the first 65 blocks and block 2022. We just ignore these and continue processing.
+		log.WithFields(log.Fields{
+			"batchId": commitment.ID.BatchID,
+			"index":   commitment.ID.IndexInBatch,
+		}).Error("Invalid aggregate signature, pretending it is valid")
+		return "", nil
+	}
+	return InvalidSignatureMessage, nil
 }
 
 func (c *TxsContext) createDisputableSignatureError(reason string, txs models.GenericTransactionArray) error {
@@ -83,3 +83,11 @@ func (t *Tracker) isEmptyTxsQueue() bool {
 
 	return len(t.txs) == 0
 }
+
+// PendingTxsCount returns how many sent transactions are waiting to be mined
+func (t *Tracker) PendingTxsCount() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return len(t.txs)
+}
@@ -63,8 +63,9 @@ func (s *TxsTrackingTestSuite) setupTestWithClientConfig(cfg *eth.ClientConfig)
 	s.cmd.client = s.client.Client
 	s.cmd.blockchain = s.client.Blockchain
 	s.cmd.storage = s.storage.Storage
-	s.cmd.txsTracker, err = tracker.NewTracker(s.client.Client, clientCfg.TxsChannels.SentTxs, clientCfg.TxsChannels.Requests)
+	txsTracker, err := tracker.NewTracker(s.client.Client, clientCfg.TxsChannels.SentTxs, clientCfg.TxsChannels.Requests)
 	s.NoError(err)
+	s.cmd.txsTracker.Store(txsTracker)
 
 	err = s.cmd.addGenesisBatch()
 	s.NoError(err)
@@ -175,12 +176,12 @@ func (s *TxsTrackingTestSuite) submitBatch(tx models.GenericTransaction, batchTy
 
 func (s *TxsTrackingTestSuite) startWorkers() {
 	s.cmd.startWorker("Test Sending Requested Txs", func() error {
-		err := s.cmd.txsTracker.SendRequestedTxs(s.cmd.workersContext)
+		err := s.cmd.getTxsTracker().SendRequestedTxs(s.cmd.workersContext)
 		s.NoError(err)
 		return err
 	})
 	s.cmd.startWorker("Test Tracking Sent Txs", func() error {
-		err := s.cmd.txsTracker.TrackSentTxs(s.cmd.workersContext)
+		err := s.cmd.getTxsTracker().TrackSentTxs(s.cmd.workersContext)
 		s.Error(err)
 		return err
 	})
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"strings"
 	"time"
 
@@ -26,48 +27,61 @@ const (
 
 func GetConfig() *Config {
 	setupViper("commander-config")
+	return newConfigReader(nil).readConfig()
+}
 
+func (r *configReader) readConfig() *Config {
 	return &Config{
-		Log:     getLogConfig(),
-		Metrics: getMetricsConfig(),
-		Tracing: getTracingConfig(),
+		Log:     r.getLogConfig(),
+		Metrics: r.getMetricsConfig(),
+		Tracing: r.getTracingConfig(),
 		Bootstrap: &CommanderBootstrapConfig{
-			Prune:            getBool("bootstrap.prune", false),
-			Migrate:          getBool("bootstrap.migrate", false),
-			BootstrapNodeURL: getStringOrNil("bootstrap.node_url"),
-			ChainSpecPath:    getStringOrNil("bootstrap.chain_spec_path"),
+			Prune:            r.getBool("bootstrap.prune", false),
+			Migrate:          r.getBool("bootstrap.migrate", false),
+			BootstrapNodeURL: r.getStringOrNil("bootstrap.node_url"),
+			ChainSpecPath:    r.getStringOrNil("bootstrap.chain_spec_path"),
+			SnapshotPath:     r.getStringOrNil("bootstrap.snapshot_path"),
 		},
 		Rollup: &RollupConfig{
-			SyncSize:                         getUint32("rollup.sync_size", 50),
-			FeeReceiverPubKeyID:              getUint32("rollup.fee_receiver_pub_key_id", 0),
-			MinTxsPerCommitment:              getUint32("rollup.min_txs_per_commitment", 1),
-			MaxTxsPerCommitment:              getUint32("rollup.max_txs_per_commitment", 32),
-			MinCommitmentsPerBatch:           getUint32("rollup.min_commitments_per_batch", 1),
-			MaxCommitmentsPerBatch:           getUint32("rollup.max_commitments_per_batch", 32),
-			TransferBatchSubmissionGasLimit:  getUint64("rollup.transfer_batch_submission_gas_limit", DefaultTransferBatchSubmissionGasLimit),
-			C2TBatchSubmissionGasLimit:       getUint64("rollup.c2t_batch_submission_gas_limit", DefaultC2TBatchSubmissionGasLimit),
-			MMBatchSubmissionGasLimit:        getUint64("rollup.mm_batch_submission_gas_limit", DefaultMMBatchSubmissionGasLimit),
-			DepositBatchSubmissionGasLimit:   getUint64("rollup.deposit_batch_submission_gas_limit", DefaultDepositBatchSubmissionGasLimit),
-			TransitionDisputeGasLimit:        getUint64("rollup.transition_dispute_gas_limit", DefaultTransitionDisputeGasLimit),
-			SignatureDisputeGasLimit:         getUint64("rollup.signature_dispute_gas_limit", DefaultSignatureDisputeGasLimit),
-			BatchAccountRegistrationGasLimit: getUint64("rollup.batch_account_registration_gas_limit", DefaultBatchAccountRegistrationGasLimit),
-			StakeWithdrawalGasLimit:          getUint64("rollup.stake_withdrawal_gas_limit", DefaultStakeWithdrawalGasLimit),
-			BatchLoopInterval:                getDuration("rollup.batch_loop_interval", 500*time.Millisecond),
-			DisableSignatures:                getBool("rollup.disable_signatures", false),
-			HackSkipKnownBadSignatures:       getBool("hack.skip_known_bad_signatures", false),
-			MaxTxnDelay:                      getDuration("rollup.max_txn_delay", 30*time.Minute),
+			SyncSize:                         r.getUint32("rollup.sync_size", 50),
+			FeeReceiverPubKeyID:              r.getUint32("rollup.fee_receiver_pub_key_id", 0),
+			MinTxsPerCommitment:              r.getUint32("rollup.min_txs_per_commitment", 1),
+			MaxTxsPerCommitment:              r.getUint32("rollup.max_txs_per_commitment", 32),
+			MinCommitmentsPerBatch:           r.getUint32("rollup.min_commitments_per_batch", 1),
+			MaxCommitmentsPerBatch:           r.getUint32("rollup.max_commitments_per_batch", 32),
+			TransferBatchSubmissionGasLimit:  r.getUint64("rollup.transfer_batch_submission_gas_limit", DefaultTransferBatchSubmissionGasLimit),
+			C2TBatchSubmissionGasLimit:       r.getUint64("rollup.c2t_batch_submission_gas_limit", DefaultC2TBatchSubmissionGasLimit),
+			MMBatchSubmissionGasLimit:        r.getUint64("rollup.mm_batch_submission_gas_limit", DefaultMMBatchSubmissionGasLimit),
+			DepositBatchSubmissionGasLimit:   r.getUint64("rollup.deposit_batch_submission_gas_limit", DefaultDepositBatchSubmissionGasLimit),
+			TransitionDisputeGasLimit:        r.getUint64("rollup.transition_dispute_gas_limit", DefaultTransitionDisputeGasLimit),
+			SignatureDisputeGasLimit:         r.getUint64("rollup.signature_dispute_gas_limit", DefaultSignatureDisputeGasLimit),
+			BatchAccountRegistrationGasLimit: r.getUint64("rollup.batch_account_registration_gas_limit", DefaultBatchAccountRegistrationGasLimit),
+			StakeWithdrawalGasLimit:          r.getUint64("rollup.stake_withdrawal_gas_limit", DefaultStakeWithdrawalGasLimit),
+			BatchLoopInterval:                r.getDuration("rollup.batch_loop_interval", 500*time.Millisecond),
+			DisableSignatures:                r.getBool("rollup.disable_signatures", false),
+			HackSkipKnownBadSignatures:       r.getBool("hack.skip_known_bad_signatures", false),
+			MaxTxnDelay:                      r.getDuration("rollup.max_txn_delay", 30*time.Minute),
 		},
 		API: &APIConfig{
-			Version:            "0.5.0-rc2",
-			Port:               getString("api.port", "8080"),
-			EnableProofMethods: getBool("api.enable_proof_methods", false),
-			AuthenticationKey:  getStringOrPanic("api.authentication_key"),
+			Version:                    "0.5.0-rc2",
+			Port:                       r.getString("api.port", "8080"),
+			EnableProofMethods:         r.getBool("api.enable_proof_methods", false),
+			AuthenticationKey:          r.getStringOrPanic("api.authentication_key"),
+			BatchSignatureVerification: r.getBool("api.batch_signature_verification", false),
+		},
+		Registration:   r.getRegistrationConfig(),
+		BalanceWatcher: r.getBalanceWatcherConfig(),
+		LeaderElection: r.getLeaderElectionConfig(),
+		Pruning:        r.getPruningConfig(),
+		Backup: &BackupConfig{
+			Dir: r.getString("backup.dir", ""),
 		},
 		Badger: &BadgerConfig{
-			Path: getString("badger.path", "./db/data/hubble"),
+			Path:    r.getString("badger.path", "./db/data/hubble"),
+			Backend: r.getString("badger.backend", "badger"),
 		},
-		Ethereum: getEthereumConfig(),
-		SafeMode: getBool("safe_mode", false),
+		Ethereum: r.getEthereumConfig(),
+		SafeMode: r.getBool("safe_mode", false),
 	}
 }
 
@@ -115,8 +129,33 @@ func GetTestConfig() *Config {
 			EnableProofMethods: true,
 			AuthenticationKey:  "secret_authentication_key",
 		},
+		Registration: &RegistrationConfig{
+			Enabled:        false,
+			MaxDelay:       time.Minute,
+			RateLimit:      0,
+			RateLimitBurst: 1,
+		},
+		BalanceWatcher: &BalanceWatcherConfig{
+			Enabled:              false,
+			Interval:             time.Minute,
+			MinAffordableBatches: 10,
+			ReservedDisputes:     1,
+		},
+		LeaderElection: &LeaderElectionConfig{
+			Enabled:       false,
+			Backend:       "file",
+			LeaseDuration: 10 * time.Second,
+			RenewInterval: 2 * time.Second,
+		},
+		Pruning: &PruningConfig{
+			Enabled:      false,
+			Interval:     10 * time.Minute,
+			SafetyMargin: 16,
+		},
+		Backup: &BackupConfig{},
 		Badger: &BadgerConfig{
-			Path: "../db/data/hubble_test",
+			Path:    "../db/data/hubble_test",
+			Backend: "badger",
 		},
 		Ethereum: &EthereumConfig{
 			RPCURL:      "simulator",
@@ -152,16 +191,16 @@ func setupViper(configName string) {
 	}
 }
 
-func getLogConfig() *LogConfig {
-	level, err := log.ParseLevel(getString("log.level", "info"))
+func (r *configReader) getLogConfig() *LogConfig {
+	level, err := log.ParseLevel(r.getString("log.level", "info"))
 	if err != nil {
-		log.Panicf("invalid log level: %e", err)
+		r.invalidConfig("invalid log level: %v", err)
 	}
 
-	format := getString("log.format", LogFormatText)
+	format := r.getString("log.format", LogFormatText)
 
 	if format != LogFormatText && format != LogFormatJSON {
-		log.Panicf("invalid log format: %s", format)
+		r.invalidConfig("invalid log format: %s", format)
 	}
 
 	return &LogConfig{
@@ -170,36 +209,80 @@ func getLogConfig() *LogConfig {
 	}
 }
 
-func getMetricsConfig() *MetricsConfig {
+func (r *configReader) getMetricsConfig() *MetricsConfig {
 	return &MetricsConfig{
-		Port:     getString("metrics.port", DefaultMetricsPort),
-		Endpoint: getString("metrics.endpoint", DefaultMetricsEndpoint),
+		Port:     r.getString("metrics.port", DefaultMetricsPort),
+		Endpoint: r.getString("metrics.endpoint", DefaultMetricsEndpoint),
 	}
 }
 
-func getTracingConfig() *TracingConfig {
+func (r *configReader) getTracingConfig() *TracingConfig {
 	return &TracingConfig{
-		Enabled:     getBool("tracing.enabled", false),
-		ServiceName: getString("tracing.service", "hubble-commander"),
-		Version:     getString("tracing.version", "0.0.0"),
-		Env:         getString("tracing.env", "prod"),
-		Endpoint:    getString("tracing.endpoint", "localhost:4317"),
+		Enabled:     r.getBool("tracing.enabled", false),
+		ServiceName: r.getString("tracing.service", "hubble-commander"),
+		Version:     r.getString("tracing.version", "0.0.0"),
+		Env:         r.getString("tracing.env", "prod"),
+		Endpoint:    r.getString("tracing.endpoint", "localhost:4317"),
+	}
+}
+
+func (r *configReader) getRegistrationConfig() *RegistrationConfig {
+	return &RegistrationConfig{
+		Enabled:        r.getBool("registration.enabled", false),
+		MaxDelay:       r.getDuration("registration.max_delay", time.Minute),
+		RateLimit:      r.getFloat64("registration.rate_limit", 0),
+		RateLimitBurst: r.getInt("registration.rate_limit_burst", 1),
+		Allowlist:      r.getPublicKeys("registration.allowlist"),
+	}
+}
+
+func (r *configReader) getBalanceWatcherConfig() *BalanceWatcherConfig {
+	return &BalanceWatcherConfig{
+		Enabled:              r.getBool("balance_watcher.enabled", false),
+		Interval:             r.getDuration("balance_watcher.interval", time.Minute),
+		MinAffordableBatches: r.getUint32("balance_watcher.min_affordable_batches", 10),
+		ReservedDisputes:     r.getUint32("balance_watcher.reserved_disputes", 1),
+	}
+}
+
+func (r *configReader) getPruningConfig() *PruningConfig {
+	return &PruningConfig{
+		Enabled:      r.getBool("pruning.enabled", false),
+		Interval:     r.getDuration("pruning.interval", 10*time.Minute),
+		SafetyMargin: r.getUint32("pruning.safety_margin", 16),
+	}
+}
+
+func (r *configReader) getLeaderElectionConfig() *LeaderElectionConfig {
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Panicf("failed to get hostname: %s", err)
+	}
+
+	return &LeaderElectionConfig{
+		Enabled:       r.getBool("leader_election.enabled", false),
+		Backend:       r.getString("leader_election.backend", "file"),
+		NodeID:        r.getString("leader_election.node_id", hostname),
+		LeaseDuration: r.getDuration("leader_election.lease_duration", 10*time.Second),
+		RenewInterval: r.getDuration("leader_election.renew_interval", 2*time.Second),
+		FilePath:      r.getString("leader_election.file_path", "./db/leader.lock"),
+		URL:           r.getString("leader_election.url", ""),
 	}
 }
 
-func getEthereumConfig() *EthereumConfig {
-	rpcURL := getStringOrNil("ethereum.rpc_url")
+func (r *configReader) getEthereumConfig() *EthereumConfig {
+	rpcURL := r.getStringOrNil("ethereum.rpc_url")
 	if rpcURL == nil {
 		return &EthereumConfig{
 			RPCURL:     "simulator",
 			ChainID:    SimulatorChainID,
-			PrivateKey: getString("ethereum.private_key", "ee79b5f6e221356af78cf4c36f4f7885a11b67dfcc81c34d80249947330c0f82"),
+			PrivateKey: r.getString("ethereum.private_key", "ee79b5f6e221356af78cf4c36f4f7885a11b67dfcc81c34d80249947330c0f82"),
 		}
 	}
 	return &EthereumConfig{
 		RPCURL:      *rpcURL,
-		ChainID:     getUint64OrPanic("ethereum.chain_id"),
-		PrivateKey:  getStringOrPanic("ethereum.private_key"),
-		MineTimeout: getDuration("ethereum.mine_timeout", DefaultEthereumMineTimeout),
+		ChainID:     r.getUint64OrPanic("ethereum.chain_id"),
+		PrivateKey:  r.getStringOrPanic("ethereum.private_key"),
+		MineTimeout: r.getDuration("ethereum.mine_timeout", DefaultEthereumMineTimeout),
 	}
 }
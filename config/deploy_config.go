@@ -10,16 +10,19 @@ import (
 
 const DefaultBlocksToFinalise = uint32(1 * 24 * 60 * 4) // there are 4 eth blocks / min
 
+const DefaultGenesisPath = "genesis.yaml"
+
 func GetDeployerConfig() *DeployerConfig {
 	setupViper("deployer-config")
 
+	r := newConfigReader(nil)
 	return &DeployerConfig{
 		Bootstrap: &DeployerBootstrapConfig{
-			BlocksToFinalise: getUint32("bootstrap.blocks_to_finalise", DefaultBlocksToFinalise), // nolint:misspell
-			GenesisAccounts:  getGenesisAccounts(),
-			Chooser:          getAddressOrNil("chooser_address"),
+			BlocksToFinalise: r.getUint32("bootstrap.blocks_to_finalise", DefaultBlocksToFinalise), // nolint:misspell
+			GenesisAccounts:  readGenesisAccounts(r.getString("bootstrap.genesis_path", DefaultGenesisPath)),
+			Chooser:          r.getAddressOrNil("chooser_address"),
 		},
-		Ethereum: getEthereumConfig(),
+		Ethereum: r.getEthereumConfig(),
 	}
 }
 
@@ -32,13 +35,8 @@ func GetDeployerTestConfig() *DeployerConfig {
 	return GetDeployerConfig()
 }
 
-func getGenesisAccounts() []models.GenesisAccount {
-	filename := getStringOrPanic("bootstrap.genesis_path")
-	log.Printf("Reading genesis config from %s", filename)
-	return readGenesisAccounts(filename)
-}
-
 func readGenesisAccounts(filename string) []models.GenesisAccount {
+	log.Printf("Reading genesis config from %s", filename)
 	genesisAccounts, err := readGenesisFile(filename)
 	if err != nil {
 		log.Panicf("error reading genesis file: %s", err.Error())
@@ -3,18 +3,21 @@ package config
 import (
 	"time"
 
+	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/ethereum/go-ethereum/common"
-	log "github.com/sirupsen/logrus"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
-func getString(key, fallback string) string {
+func (r *configReader) getString(key, fallback string) string {
 	viper.SetDefault(key, fallback)
+	r.checkString(key)
 	return viper.GetString(key)
 }
 
-func getStringOrNil(key string) *string {
+func (r *configReader) getStringOrNil(key string) *string {
+	r.checkString(key)
 	value := viper.GetString(key)
 	if value == "" {
 		return nil
@@ -22,16 +25,22 @@ func getStringOrNil(key string) *string {
 	return &value
 }
 
-func getStringOrPanic(key string) string {
+func (r *configReader) getStringOrPanic(key string) string {
+	r.checkString(key)
 	value := viper.GetString(key)
 	if value == "" {
-		log.Panicf("%s config not specified", key)
+		r.invalidConfig("%s config not specified", key)
 	}
 	return value
 }
 
-func getAddressOrNil(key string) *common.Address {
-	strAddress := getStringOrNil(key)
+func (r *configReader) checkString(key string) {
+	_, err := cast.ToStringE(viper.Get(key))
+	r.checkKey(key, err)
+}
+
+func (r *configReader) getAddressOrNil(key string) *common.Address {
+	strAddress := r.getStringOrNil(key)
 	if strAddress == nil {
 		return nil
 	}
@@ -39,31 +48,79 @@ func getAddressOrNil(key string) *common.Address {
 	return &address
 }
 
-func getUint32(key string, fallback uint32) uint32 {
+func (r *configReader) getUint32(key string, fallback uint32) uint32 {
 	viper.SetDefault(key, fallback)
+	_, err := cast.ToUint32E(viper.Get(key))
+	r.checkKey(key, err)
 	return viper.GetUint32(key)
 }
 
-func getUint64(key string, fallback uint64) uint64 {
+func (r *configReader) getUint64(key string, fallback uint64) uint64 {
 	viper.SetDefault(key, fallback)
+	_, err := cast.ToUint64E(viper.Get(key))
+	r.checkKey(key, err)
 	return viper.GetUint64(key)
 }
 
-func getUint64OrPanic(key string) uint64 {
+func (r *configReader) getUint64OrPanic(key string) uint64 {
 	value, err := cast.ToUint64E(viper.Get(key))
+	r.checkKey(key, nil)
 	if err != nil {
-		log.Panicf("failed to read %s config: %v", key, err)
+		r.invalidConfig("failed to read %s config: %v", key, err)
 	}
 	return value
 }
 
+func (r *configReader) getInt(key string, fallback int) int {
+	viper.SetDefault(key, fallback)
+	_, err := cast.ToIntE(viper.Get(key))
+	r.checkKey(key, err)
+	return viper.GetInt(key)
+}
+
+func (r *configReader) getFloat64(key string, fallback float64) float64 {
+	viper.SetDefault(key, fallback)
+	_, err := cast.ToFloat64E(viper.Get(key))
+	r.checkKey(key, err)
+	return viper.GetFloat64(key)
+}
+
+func (r *configReader) getPublicKeys(key string) []models.PublicKey {
+	var err error
+	if viper.IsSet(key) {
+		_, err = cast.ToStringSliceE(viper.Get(key))
+	}
+	r.checkKey(key, err)
+	values := viper.GetStringSlice(key)
+	publicKeys := make([]models.PublicKey, 0, len(values))
+	for _, value := range values {
+		decoded, err := hexutil.Decode(value)
+		if err != nil {
+			r.invalidConfig("failed to read %s config: %v", key, err)
+			continue
+		}
+		var publicKey models.PublicKey
+		err = publicKey.SetBytes(decoded)
+		if err != nil {
+			r.invalidConfig("failed to read %s config: %v", key, err)
+			continue
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys
+}
+
 // nolint: unparam
-func getBool(key string, fallback bool) bool {
+func (r *configReader) getBool(key string, fallback bool) bool {
 	viper.SetDefault(key, fallback)
+	_, err := cast.ToBoolE(viper.Get(key))
+	r.checkKey(key, err)
 	return viper.GetBool(key)
 }
 
-func getDuration(key string, fallback time.Duration) time.Duration {
+func (r *configReader) getDuration(key string, fallback time.Duration) time.Duration {
 	viper.SetDefault(key, fallback)
+	_, err := cast.ToDurationE(viper.Get(key))
+	r.checkKey(key, err)
 	return viper.GetDuration(key)
 }
@@ -14,14 +14,23 @@ const (
 )
 
 type Config struct {
-	Log       *LogConfig
-	Metrics   *MetricsConfig
-	Tracing   *TracingConfig
-	Bootstrap *CommanderBootstrapConfig
-	Rollup    *RollupConfig
-	API       *APIConfig
-	Badger    *BadgerConfig
-	Ethereum  *EthereumConfig
+	Log            *LogConfig
+	Metrics        *MetricsConfig
+	Tracing        *TracingConfig
+	Bootstrap      *CommanderBootstrapConfig
+	Rollup         *RollupConfig
+	API            *APIConfig
+	Registration   *RegistrationConfig
+	BalanceWatcher *BalanceWatcherConfig
+	LeaderElection *LeaderElectionConfig
+	Pruning        *PruningConfig
+	Backup         *BackupConfig
+	Badger         *BadgerConfig
+	Ethereum       *EthereumConfig
+
+	// set by `start --api-only --replica-of <url>`, the commander then only serves
+	// the hubble API from a database replicated from the primary commander
+	Replica *ReplicaConfig
 
 	// Hubble is not yet stable but a lot of services rely on the commander being available
 	// at all times. When SafeMode=true Hubble only serves API requests, it does not attempt
@@ -63,8 +72,9 @@ type DeployerBootstrapConfig struct {
 type CommanderBootstrapConfig struct {
 	Prune            bool
 	Migrate          bool
-	BootstrapNodeURL *string
+	BootstrapNodeURL *string `redact:"url"`
 	ChainSpecPath    *string
+	SnapshotPath     *string
 }
 
 type RollupConfig struct {
@@ -100,10 +110,76 @@ type APIConfig struct {
 	Port               string
 	EnableProofMethods bool
 	AuthenticationKey  string `json:"-"`
+
+	// checks the signatures of hubble_sendTransactions with a single multi-pairing, falling back
+	// to checking them one by one when any of them is invalid
+	BatchSignatureVerification bool
+}
+
+type RegistrationConfig struct {
+	Enabled bool
+
+	// registrations are submitted in batches of 16 public keys, a partially filled batch
+	// is padded with zero public keys once its oldest registration waited this long
+	MaxDelay time.Duration
+
+	// number of registrations accepted per second, 0 disables rate limiting
+	RateLimit      float64
+	RateLimitBurst int
+
+	// when not empty only these public keys can be registered
+	Allowlist []models.PublicKey
+}
+
+type BalanceWatcherConfig struct {
+	Enabled  bool
+	Interval time.Duration
+
+	// batch creation is paused once the operator can afford fewer batches than this,
+	// funds for ReservedDisputes disputes are set aside before counting batches
+	MinAffordableBatches uint32
+	ReservedDisputes     uint32
+}
+
+type LeaderElectionConfig struct {
+	Enabled bool
+
+	// "file" or "http"
+	Backend string
+
+	// must be unique for every commander sharing the lease
+	NodeID string
+
+	// a standby takes over at most LeaseDuration + RenewInterval after the leader is lost
+	LeaseDuration time.Duration
+	RenewInterval time.Duration
+
+	FilePath string
+	URL      string `redact:"url"`
+}
+
+type PruningConfig struct {
+	Enabled  bool
+	Interval time.Duration
+
+	// state updates of this many batches before the latest finalised one are kept
+	SafetyMargin uint32
+}
+
+type BackupConfig struct {
+	// admin_backup only writes backup files into this directory, backups are disabled when it is empty
+	Dir string
+}
+
+type ReplicaConfig struct {
+	// URL of the API of the primary commander, the replica authenticates with its own API.AuthenticationKey
+	PrimaryURL string `redact:"url"`
 }
 
 type BadgerConfig struct {
 	Path string
+	// key-value engine the database is stored in, "badger" or "leveldb"
+	Backend string
 }
 
 type EthereumConfig struct {
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const RedactedValue = "<redacted>"
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Redact converts the config to maps which can be printed. The fields tagged with `json:"-"` hold secrets,
+// they are replaced with RedactedValue unless they are empty. The URLs tagged with `redact:"url"` may carry
+// credentials, their user info and query are replaced with RedactedValue.
+func Redact(cfg interface{}) interface{} {
+	return redactValue(reflect.ValueOf(cfg))
+}
+
+func redactValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		return redactValue(value.Elem())
+	}
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	if value.Kind() != reflect.Struct || value.Type().Implements(textMarshalerType) {
+		return value.Interface()
+	}
+
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Tag.Get("json") == "-" {
+			fields[field.Name] = ""
+			if !value.Field(i).IsZero() {
+				fields[field.Name] = RedactedValue
+			}
+			continue
+		}
+		if field.Tag.Get("redact") == "url" {
+			fields[field.Name] = redactURLValue(value.Field(i))
+			continue
+		}
+		fields[field.Name] = redactValue(value.Field(i))
+	}
+	return fields
+}
+
+func redactURLValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	return redactURL(value.String())
+}
+
+// redactURL keeps the scheme, host and path, URLs which can't be parsed are redacted as a whole
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return RedactedValue
+	}
+	redacted := *parsedURL
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.Fragment = ""
+
+	result := redacted.String()
+	if parsedURL.User != nil {
+		result = strings.Replace(result, "//", "//"+RedactedValue+"@", 1)
+	}
+	if parsedURL.RawQuery != "" {
+		result += "?" + RedactedValue
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// The gas used by the smallest operation of each kind, measured on the simulated chain with a single commitment
+// or transaction. Lower gas limits make every such transaction run out of gas, larger batches need more.
+const (
+	minTransferBatchSubmissionGas  = 90_000
+	minC2TBatchSubmissionGas       = 90_000
+	minMMBatchSubmissionGas        = 95_000
+	minDepositBatchSubmissionGas   = 165_000
+	minTransitionDisputeGas        = 80_000
+	minSignatureDisputeGas         = 195_000
+	minBatchAccountRegistrationGas = 160_000
+	minStakeWithdrawalGas          = 55_000
+)
+
+// configReader reads the config values from viper. Without validation the getters panic on missing required
+// values and fall back to the zero value on values of the wrong type, like the commander always did.
+type configReader struct {
+	validation *configValidation
+}
+
+// configValidation collects the problems found by ValidateConfig
+type configValidation struct {
+	readKeys map[string]bool
+	problems []error
+}
+
+func newConfigReader(validation *configValidation) *configReader {
+	return &configReader{validation: validation}
+}
+
+// ValidateConfig reads the commander config like GetConfig and returns the keys which are not read,
+// the values of the wrong type and the values which are inconsistent with each other
+func ValidateConfig() []error {
+	setupViper("commander-config")
+
+	validation := &configValidation{readKeys: make(map[string]bool)}
+	cfg := newConfigReader(validation).readConfig()
+
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !validation.readKeys[key] {
+			validation.problems = append(validation.problems, fmt.Errorf("%s is not read by the commander", key))
+		}
+	}
+
+	return append(validation.problems, validateValues(cfg)...)
+}
+
+func (r *configReader) checkKey(key string, err error) {
+	if r.validation == nil {
+		return
+	}
+	r.validation.readKeys[key] = true
+	if err != nil {
+		r.validation.problems = append(r.validation.problems, fmt.Errorf("%s has the wrong type: %v", key, err))
+	}
+}
+
+func (r *configReader) invalidConfig(format string, args ...interface{}) {
+	if r.validation == nil {
+		log.Panicf(format, args...)
+	}
+	r.validation.problems = append(r.validation.problems, fmt.Errorf(format, args...))
+}
+
+func validateValues(cfg *Config) []error {
+	problems := make([]error, 0)
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	rollup := cfg.Rollup
+	if rollup.SyncSize == 0 {
+		addProblem("rollup.sync_size must be greater than 0")
+	}
+	if rollup.MaxTxsPerCommitment == 0 {
+		addProblem("rollup.max_txs_per_commitment must be greater than 0")
+	}
+	if rollup.MinTxsPerCommitment > rollup.MaxTxsPerCommitment {
+		addProblem(
+			"rollup.min_txs_per_commitment (%d) is greater than rollup.max_txs_per_commitment (%d)",
+			rollup.MinTxsPerCommitment,
+			rollup.MaxTxsPerCommitment,
+		)
+	}
+	if rollup.MaxCommitmentsPerBatch == 0 {
+		addProblem("rollup.max_commitments_per_batch must be greater than 0")
+	}
+	if rollup.MinCommitmentsPerBatch > rollup.MaxCommitmentsPerBatch {
+		addProblem(
+			"rollup.min_commitments_per_batch (%d) is greater than rollup.max_commitments_per_batch (%d)",
+			rollup.MinCommitmentsPerBatch,
+			rollup.MaxCommitmentsPerBatch,
+		)
+	}
+
+	gasLimits := []struct {
+		key      string
+		value    uint64
+		minimum  uint64
+		scenario string
+	}{
+		{"rollup.transfer_batch_submission_gas_limit", rollup.TransferBatchSubmissionGasLimit, minTransferBatchSubmissionGas,
+			"transfer batch"},
+		{"rollup.c2t_batch_submission_gas_limit", rollup.C2TBatchSubmissionGasLimit, minC2TBatchSubmissionGas,
+			"create2Transfer batch"},
+		{"rollup.mm_batch_submission_gas_limit", rollup.MMBatchSubmissionGasLimit, minMMBatchSubmissionGas,
+			"mass migration batch"},
+		{"rollup.deposit_batch_submission_gas_limit", rollup.DepositBatchSubmissionGasLimit, minDepositBatchSubmissionGas,
+			"deposit batch"},
+		{"rollup.transition_dispute_gas_limit", rollup.TransitionDisputeGasLimit, minTransitionDisputeGas,
+			"transition dispute"},
+		{"rollup.signature_dispute_gas_limit", rollup.SignatureDisputeGasLimit, minSignatureDisputeGas,
+			"signature dispute"},
+		{"rollup.batch_account_registration_gas_limit", rollup.BatchAccountRegistrationGasLimit, minBatchAccountRegistrationGas,
+			"batch account registration"},
+		{"rollup.stake_withdrawal_gas_limit", rollup.StakeWithdrawalGasLimit, minStakeWithdrawalGas,
+			"stake withdrawal"},
+	}
+	for i := range gasLimits {
+		if gasLimits[i].value < gasLimits[i].minimum {
+			addProblem(
+				"%s (%d) is below the gas used by the smallest %s (%d)",
+				gasLimits[i].key,
+				gasLimits[i].value,
+				gasLimits[i].scenario,
+				gasLimits[i].minimum,
+			)
+		}
+	}
+
+	if cfg.Registration.RateLimit < 0 {
+		addProblem("registration.rate_limit must not be negative")
+	}
+	if cfg.Registration.RateLimit > 0 && cfg.Registration.RateLimitBurst < 1 {
+		addProblem("registration.rate_limit_burst must be at least 1 when registrations are rate limited")
+	}
+
+	leaderElection := cfg.LeaderElection
+	if leaderElection.Enabled {
+		if leaderElection.Backend != "file" && leaderElection.Backend != "http" {
+			addProblem("leader_election.backend must be either file or http, got %q", leaderElection.Backend)
+		}
+		if leaderElection.Backend == "http" && leaderElection.URL == "" {
+			addProblem("leader_election.url must be set with the http backend")
+		}
+		if leaderElection.RenewInterval >= leaderElection.LeaseDuration {
+			addProblem(
+				"leader_election.renew_interval (%s) must be shorter than leader_election.lease_duration (%s)",
+				leaderElection.RenewInterval,
+				leaderElection.LeaseDuration,
+			)
+		}
+	}
+
+	if cfg.Badger.Backend != "badger" && cfg.Badger.Backend != "leveldb" {
+		addProblem("badger.backend must be either badger or leveldb, got %q", cfg.Badger.Backend)
+	}
+
+	return problems
+}
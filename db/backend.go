@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	BadgerBackend  = "badger"
+	LevelDBBackend = "leveldb"
+
+	// TestBackendEnv selects the backend used by NewTestDB, Badger is used when it is empty
+	TestBackendEnv = "HUBBLE_TEST_DB_BACKEND"
+)
+
+var ErrUnknownBackend = errors.New("unknown database backend")
+
+// backend is the key-value engine Database stores its data in
+type backend interface {
+	newTransaction(update bool) backendTxn
+	close() error
+	runGC() error
+	dropAll() error
+	backup(w io.Writer, since uint64) (uint64, error)
+	load(r io.Reader, maxPendingWrites int) error
+
+	// subscribe calls cb with the changes of every commit, in commit order, until ctx is cancelled or cb fails.
+	// Changes carry the timestamp of their commit, which grows by one with every commit. The subscription
+	// calls ready once it is registered, or as close to that as the backend allows.
+	subscribe(ctx context.Context, ready func(), cb func(changes []Change) error) error
+}
+
+// backendTxn exposes the raw key-value operations together with the typed operations of BadgerHold
+type backendTxn interface {
+	Txn
+	commit() error
+	discard()
+	// readVersion is the commit timestamp of the last commit the transaction sees
+	readVersion() uint64
+
+	count(dataType interface{}, query *Query) (uint64, error)
+	find(result interface{}, query *Query) error
+	findOne(result interface{}, query *Query) error
+	get(key, result interface{}) error
+	insert(key, data interface{}) error
+	upsert(key, data interface{}) error
+	update(key, data interface{}) error
+	delete(key, dataType interface{}) error
+}
+
+func openBackend(name, path string, inMemory bool) (backend, error) {
+	switch strings.ToLower(name) {
+	case "", BadgerBackend:
+		return openBadgerBackend(path, inMemory)
+	case LevelDBBackend:
+		return openLevelDBBackend(path, inMemory)
+	default:
+		return nil, errors.WithMessagef(ErrUnknownBackend, "%q", name)
+	}
+}
+
+func testBackend() string {
+	return os.Getenv(TestBackendEnv)
+}
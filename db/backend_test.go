@@ -0,0 +1,163 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type indexedStruct struct {
+	ID    uint64 `badgerhold:"key"`
+	Group uint32 `badgerhold:"index"`
+	Name  string
+}
+
+type BackendTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	backendName string
+	db          *Database
+}
+
+func (s *BackendTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *BackendTestSuite) SetupTest() {
+	var err error
+	s.db, err = NewInMemoryDatabaseWithBackend(s.backendName)
+	s.NoError(err)
+
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		err = s.db.Insert(NextSequence(), indexedStruct{Group: uint32(i % 2), Name: name})
+		s.NoError(err)
+	}
+}
+
+func (s *BackendTestSuite) TearDownTest() {
+	err := s.db.Close()
+	s.NoError(err)
+}
+
+func (s *BackendTestSuite) TestInsert_SetsKeyFieldOfValuePassedByReference() {
+	value := indexedStruct{Name: "f"}
+	err := s.db.Insert(NextSequence(), &value)
+	s.NoError(err)
+	s.EqualValues(5, value.ID)
+
+	err = s.db.Insert(value.ID, value)
+	s.ErrorIs(err, ErrKeyExists)
+}
+
+func (s *BackendTestSuite) TestGet_SetsKeyField() {
+	var value indexedStruct
+	err := s.db.Get(uint64(2), &value)
+	s.NoError(err)
+	s.Equal(indexedStruct{ID: 2, Group: 0, Name: "c"}, value)
+
+	err = s.db.Get(uint64(10), &value)
+	s.ErrorIs(err, ErrNotFound)
+}
+
+func (s *BackendTestSuite) TestFind_UsingIndex() {
+	var values []indexedStruct
+	err := s.db.Find(&values, Where("Group").Eq(uint32(1)).Index("Group"))
+	s.NoError(err)
+	s.Equal([]string{"b", "d"}, names(values))
+
+	values = nil
+	err = s.db.Find(&values, Where("Group").In(uint32(0), uint32(1)).Index("Group").And("Name").Ge("c"))
+	s.NoError(err)
+	s.ElementsMatch([]string{"c", "d", "e"}, names(values))
+}
+
+func (s *BackendTestSuite) TestFind_KeyRangeSortedAndLimited() {
+	var values []indexedStruct
+	err := s.db.Find(&values, Where(Key).Ge(uint64(1)).And(Key).Le(uint64(3)))
+	s.NoError(err)
+	s.Equal([]string{"b", "c", "d"}, names(values))
+
+	values = nil
+	err = s.db.Find(&values, Where("Group").Eq(uint32(0)).SortBy("Name").Limit(2))
+	s.NoError(err)
+	s.Equal([]string{"a", "c"}, names(values))
+}
+
+func (s *BackendTestSuite) TestUpdate_MovesValueBetweenIndexEntries() {
+	err := s.db.Update(uint64(0), indexedStruct{Group: 1, Name: "a"})
+	s.NoError(err)
+	err = s.db.Delete(uint64(1), indexedStruct{})
+	s.NoError(err)
+
+	count, err := s.db.Count(&indexedStruct{}, Where("Group").Eq(uint32(1)).Index("Group"))
+	s.NoError(err)
+	s.EqualValues(2, count)
+
+	err = s.db.Update(uint64(1), indexedStruct{})
+	s.ErrorIs(err, ErrNotFound)
+}
+
+func (s *BackendTestSuite) TestIterator_SeesPendingWritesOfTransaction() {
+	prefix := []byte("prefix")
+	err := s.db.RawUpdate(func(txn Txn) error {
+		s.NoError(txn.Set([]byte("prefix1"), []byte{1}))
+		return txn.Set([]byte("prefix3"), []byte{3})
+	})
+	s.NoError(err)
+
+	tx, txDB := s.db.BeginTransaction(true)
+	defer tx.Rollback(&err)
+
+	err = txDB.RawUpdate(func(txn Txn) error {
+		s.NoError(txn.Set([]byte("prefix2"), []byte{2}))
+		return txn.Delete([]byte("prefix3"))
+	})
+	s.NoError(err)
+
+	for _, opts := range []IteratorOptions{PrefetchIteratorOpts, ReversePrefetchIteratorOpts} {
+		keys := make([]string, 0, 2)
+		err = txDB.Iterator(prefix, opts, func(item Item) (bool, error) {
+			keys = append(keys, string(item.Key()))
+			return false, nil
+		})
+		s.ErrorIs(err, ErrIteratorFinished)
+		if opts.Reverse {
+			s.Equal([]string{"prefix2", "prefix1"}, keys)
+		} else {
+			s.Equal([]string{"prefix1", "prefix2"}, keys)
+		}
+	}
+}
+
+func (s *BackendTestSuite) TestCommit_ConflictsWithKeysReadBeforeConcurrentCommit() {
+	tx, txDB := s.db.BeginTransaction(true)
+
+	var value indexedStruct
+	err := txDB.Get(uint64(0), &value)
+	s.NoError(err)
+
+	err = s.db.Upsert(uint64(0), indexedStruct{Name: "z"})
+	s.NoError(err)
+
+	err = txDB.Upsert(uint64(0), indexedStruct{Name: "y"})
+	s.NoError(err)
+	err = tx.Commit()
+	s.ErrorIs(err, ErrConflict)
+}
+
+func names(values []indexedStruct) []string {
+	result := make([]string, 0, len(values))
+	for i := range values {
+		result = append(result, values[i].Name)
+	}
+	return result
+}
+
+func TestBackendTestSuite(t *testing.T) {
+	for _, backendName := range []string{BadgerBackend, LevelDBBackend} {
+		t.Run(backendName, func(t *testing.T) {
+			suite.Run(t, &BackendTestSuite{backendName: backendName})
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const maxPendingLoadWrites = 256
+
+// Backup streams all key versions newer than `since` to the writer while the database stays open.
+// The returned version can be used as `since` of the next, incremental, backup.
+func (d *Database) Backup(w io.Writer, since uint64) (uint64, error) {
+	version, err := d.backend.backup(w, since)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return version, nil
+}
+
+// Load restores a backup created with Backup. Incremental backups must be loaded in the order they were created.
+func (d *Database) Load(r io.Reader) error {
+	err := d.backend.load(r, maxPendingLoadWrites)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
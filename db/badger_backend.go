@@ -0,0 +1,193 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/pb"
+	bh "github.com/timshannon/badgerhold/v4"
+)
+
+var badgerInternalKeyPrefix = []byte("!badger!")
+
+type badgerBackend struct {
+	store *bh.Store
+}
+
+func openBadgerBackend(path string, inMemory bool) (*badgerBackend, error) {
+	options := badger.DefaultOptions(path).
+		WithLoggingLevel(badger.WARNING)
+	if inMemory {
+		options = options.WithInMemory(true)
+	} else {
+		options = options.WithMemTableSize(64 << 22)
+	}
+
+	bhOptions := bh.DefaultOptions
+	bhOptions.Encoder = Encode
+	bhOptions.Decoder = Decode
+	bhOptions.Options = options
+
+	store, err := bh.Open(bhOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &badgerBackend{store: store}, nil
+}
+
+func (b *badgerBackend) newTransaction(update bool) backendTxn {
+	return &badgerTxn{
+		store: b.store,
+		txn:   b.store.Badger().NewTransaction(update),
+	}
+}
+
+func (b *badgerBackend) close() error {
+	return b.store.Close()
+}
+
+func (b *badgerBackend) runGC() error {
+	// We're garbage collecting the value log files, which are a combination WAL and
+	// mechamism for keeping large values out of the LSM levels which are frequently
+	// copied between files as levels are compacted. Each value log file is a
+	// collection of values, some number of which refer to inaccessible versions which
+	// should be garbage collected. The garbage collector works at the granularity of
+	// individual files, in order to garbage collect a file it reads all the records
+	// and creates a new file without all the discardable ones. The float we're
+	// passing in is the proportion of the records in a value log file which must be
+	// discardable for that value log file to be rewritten. e.g. if we pass 1.0 then
+	// value log files will be rewritten if they contain even a single discardable
+	// record. 0.5 is the recommended value and it seems fine, worth revisiting this
+	// number if we end up using too much disk space or if we're doing too much disk
+	// I/O.
+	return b.store.Badger().RunValueLogGC(0.5)
+}
+
+func (b *badgerBackend) dropAll() error {
+	return b.store.Badger().DropAll()
+}
+
+func (b *badgerBackend) backup(w io.Writer, since uint64) (uint64, error) {
+	return b.store.Badger().Backup(w, since)
+}
+
+func (b *badgerBackend) load(r io.Reader, maxPendingWrites int) error {
+	return b.store.Badger().Load(r, maxPendingWrites)
+}
+
+// subscribe skips the internal keys of Badger. Badger doesn't publish the meta byte of entries, so deletions
+// are told apart by their empty values, which is fine as the stores never write empty values.
+// Badger registers the subscriber at the start of Subscribe without telling when, so ready is called just before it.
+func (b *badgerBackend) subscribe(ctx context.Context, ready func(), cb func(changes []Change) error) error {
+	ready()
+	return b.store.Badger().Subscribe(ctx, func(kvs *badger.KVList) error {
+		changes := make([]Change, 0, len(kvs.Kv))
+		for _, kv := range kvs.Kv {
+			if bytes.HasPrefix(kv.Key, badgerInternalKeyPrefix) {
+				continue
+			}
+			changes = append(changes, Change{
+				Key:     kv.Key,
+				Value:   kv.Value,
+				Deleted: len(kv.Value) == 0,
+				version: kv.Version,
+			})
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return cb(changes)
+	}, []pb.Match{{Prefix: nil}})
+}
+
+type badgerTxn struct {
+	store *bh.Store
+	txn   *badger.Txn
+}
+
+func (t *badgerTxn) Get(key []byte) (Item, error) {
+	item, err := t.txn.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (t *badgerTxn) Set(key, value []byte) error {
+	return t.txn.Set(key, value)
+}
+
+func (t *badgerTxn) Delete(key []byte) error {
+	return t.txn.Delete(key)
+}
+
+func (t *badgerTxn) NewIterator(opts IteratorOptions) KVIterator {
+	return &badgerIterator{
+		Iterator: t.txn.NewIterator(badger.IteratorOptions{
+			PrefetchValues: opts.PrefetchValues,
+			PrefetchSize:   opts.PrefetchSize,
+			Reverse:        opts.Reverse,
+		}),
+	}
+}
+
+func (t *badgerTxn) commit() error {
+	return t.txn.Commit()
+}
+
+func (t *badgerTxn) discard() {
+	t.txn.Discard()
+}
+
+func (t *badgerTxn) readVersion() uint64 {
+	return t.txn.ReadTs()
+}
+
+func (t *badgerTxn) count(dataType interface{}, query *Query) (uint64, error) {
+	return t.store.TxCount(t.txn, dataType, query.toBadgerhold())
+}
+
+func (t *badgerTxn) find(result interface{}, query *Query) error {
+	return t.store.TxFind(t.txn, result, query.toBadgerhold())
+}
+
+func (t *badgerTxn) findOne(result interface{}, query *Query) error {
+	return t.store.TxFindOne(t.txn, result, query.toBadgerhold())
+}
+
+func (t *badgerTxn) get(key, result interface{}) error {
+	return t.store.TxGet(t.txn, key, result)
+}
+
+func (t *badgerTxn) insert(key, data interface{}) error {
+	if _, ok := key.(sequence); ok {
+		key = bh.NextSequence()
+	}
+	return t.store.TxInsert(t.txn, key, data)
+}
+
+func (t *badgerTxn) upsert(key, data interface{}) error {
+	return t.store.TxUpsert(t.txn, key, data)
+}
+
+func (t *badgerTxn) update(key, data interface{}) error {
+	return t.store.TxUpdate(t.txn, key, data)
+}
+
+func (t *badgerTxn) delete(key, dataType interface{}) error {
+	return t.store.TxDelete(t.txn, key, dataType)
+}
+
+type badgerIterator struct {
+	*badger.Iterator
+}
+
+func (it *badgerIterator) Item() Item {
+	item := it.Iterator.Item()
+	if item == nil {
+		return nil
+	}
+	return item
+}
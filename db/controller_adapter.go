@@ -1,12 +1,14 @@
 package db
 
-import "github.com/dgraph-io/badger/v3"
-
 type ControllerAdapter struct {
-	*badger.Txn
+	txn backendTxn
+}
+
+func (a *ControllerAdapter) Commit() error {
+	return a.txn.commit()
 }
 
 func (a *ControllerAdapter) Rollback() error {
-	a.Txn.Discard()
+	a.txn.discard()
 	return nil
 }
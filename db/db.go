@@ -4,47 +4,37 @@ import (
 	"reflect"
 
 	"github.com/Worldcoin/hubble-commander/config"
-	"github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type Database struct {
-	store             *bh.Store
-	txn               *badger.Txn
+	backend           backend
+	txn               backendTxn
 	updateTransaction bool
 }
 
 func NewDatabase(cfg *config.BadgerConfig) (*Database, error) {
-	options := badger.DefaultOptions(cfg.Path).
-		WithLoggingLevel(badger.WARNING).
-		WithMemTableSize(64 << 22)
-	return newConfiguredDatabase(&options)
+	return newDatabase(cfg.Backend, cfg.Path, false)
 }
 
 func NewInMemoryDatabase() (*Database, error) {
-	options := badger.DefaultOptions("").
-		WithInMemory(true).
-		WithLoggingLevel(badger.WARNING)
-	return newConfiguredDatabase(&options)
+	return NewInMemoryDatabaseWithBackend(BadgerBackend)
 }
 
-func newConfiguredDatabase(opts *badger.Options) (*Database, error) {
-	bhOptions := bh.DefaultOptions
-	bhOptions.Encoder = Encode
-	bhOptions.Decoder = Decode
-	bhOptions.Options = *opts
+func NewInMemoryDatabaseWithBackend(backendName string) (*Database, error) {
+	return newDatabase(backendName, "", true)
+}
 
-	store, err := bh.Open(bhOptions)
+func newDatabase(backendName, path string, inMemory bool) (*Database, error) {
+	backend, err := openBackend(backendName, path, inMemory)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-
-	return &Database{store: store}, nil
+	return &Database{backend: backend}, nil
 }
 
 func (d *Database) Close() error {
-	err := d.store.Close()
+	err := d.backend.close()
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -52,20 +42,7 @@ func (d *Database) Close() error {
 }
 
 func (d *Database) TriggerGC() error {
-	// We're garbage collecting the value log files, which are a combination WAL and
-	// mechamism for keeping large values out of the LSM levels which are frequently
-	// copied between files as levels are compacted. Each value log file is a
-	// collection of values, some number of which refer to inaccessible versions which
-	// should be garbage collected. The garbage collector works at the granularity of
-	// individual files, in order to garbage collect a file it reads all the records
-	// and creates a new file without all the discardable ones. The float we're
-	// passing in is the proportion of the records in a value log file which must be
-	// discardable for that value log file to be rewritten. e.g. if we pass 1.0 then
-	// value log files will be rewritten if they contain even a single discardable
-	// record. 0.5 is the recommended value and it seems fine, worth revisiting this
-	// number if we end up using too much disk space or if we're doing too much disk
-	// I/O.
-	err := d.store.Badger().RunValueLogGC(0.5)
+	err := d.backend.runGC()
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -84,70 +61,54 @@ func (d *Database) duringUpdateTransaction() bool {
 	return d.duringTransaction() && d.updateTransaction
 }
 
-func (d *Database) View(fn func(txn *badger.Txn) error) error {
-	if d.duringTransaction() {
-		return fn(d.txn)
-	}
-	err := d.store.Badger().View(fn)
+func (d *Database) View(fn func(txn Txn) error) error {
+	err := d.view(func(txn backendTxn) error {
+		return fn(txn)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-func (d *Database) RawUpdate(fn func(txn *badger.Txn) error) error {
+func (d *Database) RawUpdate(fn func(txn Txn) error) error {
 	if d.duringReadOnlyTransaction() {
 		panic("RawUpdate called during ReadOnly transaction")
 	}
-	if d.duringUpdateTransaction() {
-		return fn(d.txn)
-	}
-	err := d.store.Badger().Update(fn)
+	err := d.update(func(txn backendTxn) error {
+		return fn(txn)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-func (d *Database) Count(result interface{}, query *bh.Query) (uint64, error) {
-	if d.duringTransaction() {
-		count, err := d.store.TxCount(d.txn, result, query)
-		if err != nil {
-			return 0, errors.WithStack(err)
-		}
-		return count, nil
-	}
-	count, err := d.store.Count(result, query)
+func (d *Database) Count(result interface{}, query *Query) (count uint64, err error) {
+	err = d.view(func(txn backendTxn) error {
+		count, err = txn.count(result, query)
+		return err
+	})
 	if err != nil {
 		return 0, errors.WithStack(err)
 	}
 	return count, nil
 }
 
-func (d *Database) Find(result interface{}, query *bh.Query) error {
-	if d.duringTransaction() {
-		err := d.store.TxFind(d.txn, result, query)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.Find(result, query)
+func (d *Database) Find(result interface{}, query *Query) error {
+	err := d.view(func(txn backendTxn) error {
+		return txn.find(result, query)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-func (d *Database) FindOne(result interface{}, query *bh.Query) error {
-	if d.duringTransaction() {
-		err := d.store.TxFindOne(d.txn, result, query)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.FindOne(result, query)
+func (d *Database) FindOne(result interface{}, query *Query) error {
+	err := d.view(func(txn backendTxn) error {
+		return txn.findOne(result, query)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -166,13 +127,13 @@ func (d *Database) FindOneUsingIndex(result, key interface{}, index string) erro
 
 	err := d.Find(
 		valResults.Interface(),
-		bh.Where(index).Eq(key).Index(index).Limit(1),
+		Where(index).Eq(key).Index(index).Limit(1),
 	)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	if valResults.Elem().Len() == 0 {
-		return errors.WithStack(bh.ErrNotFound)
+		return errors.WithStack(ErrNotFound)
 	}
 
 	valResult := reflect.ValueOf(result)
@@ -181,14 +142,9 @@ func (d *Database) FindOneUsingIndex(result, key interface{}, index string) erro
 }
 
 func (d *Database) Get(key, result interface{}) error {
-	if d.duringTransaction() {
-		err := d.store.TxGet(d.txn, key, result)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.Get(key, result)
+	err := d.view(func(txn backendTxn) error {
+		return txn.get(key, result)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -199,17 +155,12 @@ func (d *Database) Insert(key, data interface{}) error {
 	if d.duringReadOnlyTransaction() {
 		panic("Insert called during ReadOnly transaction")
 	}
-	if d.duringUpdateTransaction() {
-		err := d.store.TxInsert(d.txn, key, data)
-		if errors.Is(err, bh.ErrKeyExists) {
-			return errors.Wrapf(err, "duplicate key: %x", key)
-		}
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
+	err := d.updateRetryingConflicts(func(txn backendTxn) error {
+		return txn.insert(key, data)
+	})
+	if errors.Is(err, ErrKeyExists) {
+		return errors.Wrapf(err, "duplicate key: %x", key)
 	}
-	err := d.store.Insert(key, data)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -220,14 +171,9 @@ func (d *Database) Upsert(key, data interface{}) error {
 	if d.duringReadOnlyTransaction() {
 		panic("Upsert called during ReadOnly transaction")
 	}
-	if d.duringUpdateTransaction() {
-		err := d.store.TxUpsert(d.txn, key, data)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.Upsert(key, data)
+	err := d.updateRetryingConflicts(func(txn backendTxn) error {
+		return txn.upsert(key, data)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -238,14 +184,9 @@ func (d *Database) Update(key, data interface{}) error {
 	if d.duringReadOnlyTransaction() {
 		panic("Update called during ReadOnly transaction")
 	}
-	if d.duringUpdateTransaction() {
-		err := d.store.TxUpdate(d.txn, key, data)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.Update(key, data)
+	err := d.updateRetryingConflicts(func(txn backendTxn) error {
+		return txn.update(key, data)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -256,27 +197,56 @@ func (d *Database) Delete(key, dataType interface{}) error {
 	if d.duringReadOnlyTransaction() {
 		panic("Delete called during ReadOnly transaction")
 	}
-	if d.duringUpdateTransaction() {
-		err := d.store.TxDelete(d.txn, key, dataType)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	}
-	err := d.store.Delete(key, dataType)
+	err := d.update(func(txn backendTxn) error {
+		return txn.delete(key, dataType)
+	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
+// view runs fn in the current transaction or in a new read-only one
+func (d *Database) view(fn func(txn backendTxn) error) error {
+	if d.duringTransaction() {
+		return fn(d.txn)
+	}
+	txn := d.backend.newTransaction(false)
+	defer txn.discard()
+	return fn(txn)
+}
+
+// update runs fn in the current transaction or in a new one which is committed when fn succeeds
+func (d *Database) update(fn func(txn backendTxn) error) error {
+	if d.duringUpdateTransaction() {
+		return fn(d.txn)
+	}
+	txn := d.backend.newTransaction(true)
+	defer txn.discard()
+
+	err := fn(txn)
+	if err != nil {
+		return err
+	}
+	return txn.commit()
+}
+
+// updateRetryingConflicts retries the update on conflict when it runs in its own transaction, like BadgerHold does
+func (d *Database) updateRetryingConflicts(fn func(txn backendTxn) error) error {
+	err := d.update(fn)
+	for !d.duringTransaction() && errors.Is(err, ErrConflict) {
+		err = d.update(fn)
+	}
+	return err
+}
+
 func (d *Database) BeginTransaction(update bool) (*TxController, *Database) {
 	if d.duringTransaction() {
 		return NewTxController(&ControllerAdapter{d.txn}, true), d
 	}
-	txn := d.store.Badger().NewTransaction(update)
+	txn := d.backend.newTransaction(update)
 	dbDuringTx := &Database{
-		store:             d.store,
+		backend:           d.backend,
 		txn:               txn,
 		updateTransaction: update,
 	}
@@ -284,7 +254,7 @@ func (d *Database) BeginTransaction(update bool) (*TxController, *Database) {
 }
 
 func (d *Database) Prune() error {
-	err := d.store.Badger().DropAll()
+	err := d.backend.dropAll()
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -1,27 +1,26 @@
 package db
 
 import (
-	"github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
 )
 
 var (
 	ErrIteratorFinished = errors.New("iterator finished")
 
-	ReverseKeyIteratorOpts = badger.IteratorOptions{
+	ReverseKeyIteratorOpts = IteratorOptions{
 		PrefetchValues: false,
 		Reverse:        true,
 	}
-	ReversePrefetchIteratorOpts = badger.IteratorOptions{
+	ReversePrefetchIteratorOpts = IteratorOptions{
 		Reverse:        true,
 		PrefetchSize:   100,
 		PrefetchValues: true,
 	}
-	PrefetchIteratorOpts = badger.IteratorOptions{
+	PrefetchIteratorOpts = IteratorOptions{
 		PrefetchSize:   100,
 		PrefetchValues: true,
 	}
-	KeyIteratorOpts = badger.IteratorOptions{
+	KeyIteratorOpts = IteratorOptions{
 		PrefetchValues: false,
 		Reverse:        false,
 	}
@@ -29,12 +28,12 @@ var (
 	Continue = false
 )
 
-type IteratorFilter func(item *badger.Item) (finish bool, err error)
+type IteratorFilter func(item Item) (finish bool, err error)
 
 // Iterator calls filter function for every element matching the prefix.
 // First return value of the filter function is the finish flag.
-func (d *Database) Iterator(prefix []byte, opts badger.IteratorOptions, filter IteratorFilter) error {
-	return d.View(func(txn *badger.Txn) error {
+func (d *Database) Iterator(prefix []byte, opts IteratorOptions, filter IteratorFilter) error {
+	return d.View(func(txn Txn) error {
 		it := txn.NewIterator(opts)
 		defer it.Close()
 
@@ -52,7 +51,7 @@ func (d *Database) Iterator(prefix []byte, opts badger.IteratorOptions, filter I
 	})
 }
 
-func newSeekPrefix(prefix []byte, opts badger.IteratorOptions) []byte {
+func newSeekPrefix(prefix []byte, opts IteratorOptions) []byte {
 	if opts.Reverse {
 		newPrefix := make([]byte, 0, len(prefix)+1)
 		newPrefix = append(newPrefix, prefix...)
@@ -3,7 +3,6 @@ package db
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,7 +14,7 @@ func TestNewSeekPrefix_CopiesToNewMemoryLocationToAvoidRaceConditions(t *testing
 
 	underlyingArrayAddress := &prefix[0]
 
-	newPrefix := newSeekPrefix(prefix, badger.IteratorOptions{
+	newPrefix := newSeekPrefix(prefix, IteratorOptions{
 		Reverse: true,
 	})
 
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v3"
+	bh "github.com/timshannon/badgerhold/v4"
+)
+
+// Errors returned by every backend. They keep the values of their Badger and BadgerHold counterparts,
+// so errors.Is works the same way no matter which backend is used.
+var (
+	ErrKeyNotFound  = badger.ErrKeyNotFound
+	ErrConflict     = badger.ErrConflict
+	ErrTxnTooBig    = badger.ErrTxnTooBig
+	ErrReadOnlyTxn  = badger.ErrReadOnlyTxn
+	ErrNoRewrite    = badger.ErrNoRewrite
+	ErrDiscardedTxn = badger.ErrDiscardedTxn
+
+	ErrNotFound  = bh.ErrNotFound
+	ErrKeyExists = bh.ErrKeyExists
+
+	ErrUnsupportedByBackend = fmt.Errorf("operation is not supported by the database backend")
+)
+
+// Key can be used in Where and And to run criteria against the key of stored values
+const Key = bh.Key
+
+type (
+	Storer  = bh.Storer
+	Index   = bh.Index
+	KeyList = bh.KeyList
+)
+
+type sequence struct{}
+
+// NextSequence used as the key of Insert stores the value under the next number of the sequence of its type
+func NextSequence() interface{} {
+	return sequence{}
+}
+
+// Txn is the raw key-value view of a transaction passed to View and RawUpdate
+type Txn interface {
+	Get(key []byte) (Item, error)
+	Set(key, value []byte) error
+	Delete(key []byte) error
+	NewIterator(opts IteratorOptions) KVIterator
+}
+
+// Item is only valid until the iterator moves on, use KeyCopy and ValueCopy to keep the data
+type Item interface {
+	Key() []byte
+	KeyCopy(dst []byte) []byte
+	Value(fn func(value []byte) error) error
+	ValueCopy(dst []byte) ([]byte, error)
+	EstimatedSize() int64
+}
+
+type KVIterator interface {
+	// Seek moves to the smallest key greater than or equal to the given one, or the largest key smaller than
+	// or equal to it for reverse iterators
+	Seek(key []byte)
+	Valid() bool
+	ValidForPrefix(prefix []byte) bool
+	Next()
+	Item() Item
+	Close()
+}
+
+type IteratorOptions struct {
+	PrefetchValues bool
+	PrefetchSize   int
+	Reverse        bool
+}
+
+var DefaultIteratorOptions = IteratorOptions{
+	PrefetchValues: true,
+	PrefetchSize:   100,
+}
@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+const (
+	// same bandwidth BadgerHold uses for its sequences
+	levelDBSequenceBandwidth = 100
+
+	// limit of the size of writes of a single transaction, exceeding it returns ErrTxnTooBig like Badger does
+	levelDBMaxTxnSize = 16 << 20
+
+	// per entry overhead added to the size of a transaction, an approximation of the one Badger uses
+	levelDBEntryOverhead = 32
+
+	levelDBDropAllBatchSize = 10_000
+)
+
+// levelDBBackend implements optimistic transactions on top of LevelDB snapshots. Reads of update transactions are
+// tracked and a transaction fails to commit with ErrConflict when any of the keys it has read was written by
+// a transaction committed after it had started, which mirrors the semantics of Badger transactions.
+type levelDBBackend struct {
+	db *leveldb.DB
+
+	commitMutex  sync.Mutex
+	lastCommitTs uint64
+	keyCommits   map[string]uint64 // commit timestamp of keys written while update transactions were open
+	activeReads  map[uint64]int    // read timestamps of open update transactions
+	activeTxns   int
+	pruneAt      int
+
+	subscribers map[*levelDBSubscriber]struct{}
+
+	sequenceMutex sync.Mutex
+	sequences     map[string]*levelDBSequence
+}
+
+type levelDBSubscriber struct {
+	cb   func(changes []Change) error
+	done chan error
+}
+
+type levelDBSequence struct {
+	next   uint64
+	leased uint64
+}
+
+func openLevelDBBackend(path string, inMemory bool) (*levelDBBackend, error) {
+	var (
+		db  *leveldb.DB
+		err error
+	)
+	if inMemory {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, &opt.Options{
+			WriteBuffer: 64 << 20,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &levelDBBackend{
+		db:          db,
+		keyCommits:  make(map[string]uint64),
+		activeReads: make(map[uint64]int),
+		subscribers: make(map[*levelDBSubscriber]struct{}),
+		sequences:   make(map[string]*levelDBSequence),
+	}, nil
+}
+
+func (b *levelDBBackend) newTransaction(update bool) backendTxn {
+	b.commitMutex.Lock()
+	defer b.commitMutex.Unlock()
+
+	txn := &levelDBTxn{
+		backend:   b,
+		updateTxn: update,
+		readTs:    b.lastCommitTs,
+	}
+	txn.snapshot, txn.err = b.db.GetSnapshot()
+	if txn.err != nil {
+		return txn
+	}
+	if update {
+		txn.pendingWrites = make(map[string]*levelDBWrite)
+		txn.reads = make(map[string]struct{})
+		b.activeReads[txn.readTs]++
+		b.activeTxns++
+	}
+	return txn
+}
+
+// commitTxn must be called with commitMutex held
+func (b *levelDBBackend) commitTxn(txn *levelDBTxn) error {
+	for key := range txn.reads {
+		if b.keyCommits[key] > txn.readTs {
+			return ErrConflict
+		}
+	}
+
+	batch := new(leveldb.Batch)
+	for key, write := range txn.pendingWrites {
+		if write.deleted {
+			batch.Delete([]byte(key))
+		} else {
+			batch.Put([]byte(key), write.value)
+		}
+	}
+	err := b.db.Write(batch, nil)
+	if err != nil {
+		return err
+	}
+
+	b.lastCommitTs++
+	if b.activeTxns > 1 {
+		for key := range txn.pendingWrites {
+			b.keyCommits[key] = b.lastCommitTs
+		}
+	}
+	b.publishCommit(txn)
+	return nil
+}
+
+// releaseTxn must be called with commitMutex held
+func (b *levelDBBackend) releaseTxn(txn *levelDBTxn) {
+	b.activeTxns--
+	b.activeReads[txn.readTs]--
+	if b.activeReads[txn.readTs] == 0 {
+		delete(b.activeReads, txn.readTs)
+	}
+	b.pruneKeyCommits()
+}
+
+// pruneKeyCommits forgets commits which can't conflict with any of the open transactions
+func (b *levelDBBackend) pruneKeyCommits() {
+	if len(b.activeReads) == 0 {
+		if len(b.keyCommits) > 0 {
+			b.keyCommits = make(map[string]uint64)
+		}
+		return
+	}
+	if len(b.keyCommits) < b.pruneAt {
+		return
+	}
+
+	minReadTs := b.lastCommitTs
+	for readTs := range b.activeReads {
+		if readTs < minReadTs {
+			minReadTs = readTs
+		}
+	}
+	for key, commitTs := range b.keyCommits {
+		if commitTs <= minReadTs {
+			delete(b.keyCommits, key)
+		}
+	}
+	b.pruneAt = 2*len(b.keyCommits) + 1024
+}
+
+// nextSequence mirrors badger.Sequence, leases of every type are stored under the type name
+func (b *levelDBBackend) nextSequence(typeName string) (uint64, error) {
+	b.sequenceMutex.Lock()
+	defer b.sequenceMutex.Unlock()
+
+	seq, ok := b.sequences[typeName]
+	if !ok {
+		seq = &levelDBSequence{}
+		value, err := b.db.Get([]byte(typeName), nil)
+		if err != nil && err != leveldb.ErrNotFound {
+			return 0, err
+		}
+		if err == nil {
+			seq.next = binary.BigEndian.Uint64(value)
+			seq.leased = seq.next
+		}
+		b.sequences[typeName] = seq
+	}
+
+	if seq.next >= seq.leased {
+		leased := seq.leased + levelDBSequenceBandwidth
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, leased)
+		err := b.db.Put([]byte(typeName), value, nil)
+		if err != nil {
+			return 0, err
+		}
+		seq.leased = leased
+	}
+
+	next := seq.next
+	seq.next++
+	return next, nil
+}
+
+func (b *levelDBBackend) close() error {
+	return b.db.Close()
+}
+
+// runGC does nothing, LevelDB reclaims space during its background compactions
+func (b *levelDBBackend) runGC() error {
+	return ErrNoRewrite
+}
+
+func (b *levelDBBackend) dropAll() error {
+	b.commitMutex.Lock()
+	defer b.commitMutex.Unlock()
+
+	b.sequenceMutex.Lock()
+	b.sequences = make(map[string]*levelDBSequence)
+	b.sequenceMutex.Unlock()
+
+	it := b.db.NewIterator(nil, nil)
+	defer it.Release()
+
+	batch := new(leveldb.Batch)
+	for it.Next() {
+		batch.Delete(append([]byte{}, it.Key()...))
+		if batch.Len() >= levelDBDropAllBatchSize {
+			err := b.db.Write(batch, nil)
+			if err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	b.lastCommitTs++
+	return b.db.Write(batch, nil)
+}
+
+func (b *levelDBBackend) backup(_ io.Writer, _ uint64) (uint64, error) {
+	return 0, ErrUnsupportedByBackend
+}
+
+func (b *levelDBBackend) load(_ io.Reader, _ int) error {
+	return ErrUnsupportedByBackend
+}
+
+func (b *levelDBBackend) subscribe(ctx context.Context, ready func(), cb func(changes []Change) error) error {
+	subscriber := &levelDBSubscriber{
+		cb:   cb,
+		done: make(chan error, 1),
+	}
+
+	b.commitMutex.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.commitMutex.Unlock()
+	ready()
+
+	select {
+	case err := <-subscriber.done:
+		return err
+	case <-ctx.Done():
+		b.commitMutex.Lock()
+		delete(b.subscribers, subscriber)
+		b.commitMutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// publishCommit must be called with commitMutex held, callbacks of subscribers must not block
+func (b *levelDBBackend) publishCommit(txn *levelDBTxn) {
+	if len(b.subscribers) == 0 {
+		return
+	}
+
+	changes := make([]Change, 0, len(txn.pendingWrites))
+	for key, write := range txn.pendingWrites {
+		changes = append(changes, Change{
+			Key:     []byte(key),
+			Value:   write.value,
+			Deleted: write.deleted,
+			version: b.lastCommitTs,
+		})
+	}
+	for subscriber := range b.subscribers {
+		err := subscriber.cb(changes)
+		if err != nil {
+			delete(b.subscribers, subscriber)
+			subscriber.done <- err
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+)
+
+// levelDBIterator merges the snapshot of a transaction with the writes pending in it at the time of creation
+type levelDBIterator struct {
+	txn     *levelDBTxn
+	reverse bool
+
+	snapshotIterator iterator.Iterator
+	snapshotValid    bool
+	pendingKeys      []string
+	pendingIndex     int
+
+	item *levelDBItem
+}
+
+func (it *levelDBIterator) Seek(key []byte) {
+	if it.snapshotIterator == nil {
+		return
+	}
+
+	if it.reverse {
+		it.snapshotValid = it.snapshotIterator.Seek(key)
+		if !it.snapshotValid {
+			it.snapshotValid = it.snapshotIterator.Last()
+		} else if bytes.Compare(it.snapshotIterator.Key(), key) > 0 {
+			it.snapshotValid = it.snapshotIterator.Prev()
+		}
+		it.pendingIndex = sort.Search(len(it.pendingKeys), func(i int) bool {
+			return it.pendingKeys[i] > string(key)
+		}) - 1
+	} else {
+		it.snapshotValid = it.snapshotIterator.Seek(key)
+		it.pendingIndex = sort.SearchStrings(it.pendingKeys, string(key))
+	}
+	it.settle()
+}
+
+func (it *levelDBIterator) Valid() bool {
+	return it.item != nil
+}
+
+func (it *levelDBIterator) ValidForPrefix(prefix []byte) bool {
+	return it.Valid() && bytes.HasPrefix(it.item.key, prefix)
+}
+
+func (it *levelDBIterator) Next() {
+	if it.item == nil {
+		return
+	}
+	if it.snapshotValid && bytes.Equal(it.snapshotIterator.Key(), it.item.key) {
+		it.advanceSnapshot()
+	}
+	if it.pendingValid() && it.pendingKeys[it.pendingIndex] == string(it.item.key) {
+		it.advancePending()
+	}
+	it.settle()
+}
+
+func (it *levelDBIterator) Item() Item {
+	if it.item == nil {
+		return nil
+	}
+	return it.item
+}
+
+func (it *levelDBIterator) Close() {
+	if it.snapshotIterator != nil {
+		it.snapshotIterator.Release()
+	}
+}
+
+// settle moves the iterator to the next key which isn't deleted by the pending writes
+func (it *levelDBIterator) settle() {
+	it.item = nil
+	for it.snapshotValid || it.pendingValid() {
+		if !it.pendingValid() || it.snapshotValid && it.snapshotFirst() {
+			it.item = newLevelDBItem(it.snapshotIterator.Key(), it.snapshotIterator.Value())
+			break
+		}
+
+		key := it.pendingKeys[it.pendingIndex]
+		write := it.txn.pendingWrites[key]
+		if !write.deleted {
+			it.item = &levelDBItem{key: []byte(key), value: write.value}
+			break
+		}
+		if it.snapshotValid && string(it.snapshotIterator.Key()) == key {
+			it.advanceSnapshot()
+		}
+		it.advancePending()
+	}
+
+	if it.item != nil {
+		it.txn.addRead(it.item.key)
+	}
+}
+
+// snapshotFirst tells whether the snapshot key comes strictly before the pending one in the iteration order
+func (it *levelDBIterator) snapshotFirst() bool {
+	cmp := bytes.Compare(it.snapshotIterator.Key(), []byte(it.pendingKeys[it.pendingIndex]))
+	if it.reverse {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func (it *levelDBIterator) pendingValid() bool {
+	return it.pendingIndex >= 0 && it.pendingIndex < len(it.pendingKeys)
+}
+
+func (it *levelDBIterator) advanceSnapshot() {
+	if it.reverse {
+		it.snapshotValid = it.snapshotIterator.Prev()
+	} else {
+		it.snapshotValid = it.snapshotIterator.Next()
+	}
+}
+
+func (it *levelDBIterator) advancePending() {
+	if it.reverse {
+		it.pendingIndex--
+	} else {
+		it.pendingIndex++
+	}
+}
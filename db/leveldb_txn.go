@@ -0,0 +1,214 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+type levelDBTxn struct {
+	backend  *levelDBBackend
+	snapshot *leveldb.Snapshot
+	err      error // set when the snapshot couldn't be acquired
+
+	updateTxn     bool
+	readTs        uint64
+	pendingWrites map[string]*levelDBWrite
+	reads         map[string]struct{}
+	size          int
+	discarded     bool
+}
+
+type levelDBWrite struct {
+	value   []byte
+	deleted bool
+}
+
+func (t *levelDBTxn) Get(key []byte) (Item, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.discarded {
+		return nil, ErrDiscardedTxn
+	}
+	t.addRead(key)
+
+	if write, ok := t.pendingWrites[string(key)]; ok {
+		if write.deleted {
+			return nil, ErrKeyNotFound
+		}
+		return newLevelDBItem(key, write.value), nil
+	}
+
+	value, err := t.snapshot.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBItem{key: append([]byte{}, key...), value: value}, nil
+}
+
+func (t *levelDBTxn) Set(key, value []byte) error {
+	return t.write(key, &levelDBWrite{value: append([]byte{}, value...)})
+}
+
+func (t *levelDBTxn) Delete(key []byte) error {
+	return t.write(key, &levelDBWrite{deleted: true})
+}
+
+func (t *levelDBTxn) write(key []byte, write *levelDBWrite) error {
+	if t.err != nil {
+		return t.err
+	}
+	if t.discarded {
+		return ErrDiscardedTxn
+	}
+	if !t.updateTxn {
+		return ErrReadOnlyTxn
+	}
+
+	size := t.size + len(key) + len(write.value) + levelDBEntryOverhead
+	if size > levelDBMaxTxnSize {
+		return ErrTxnTooBig
+	}
+	t.size = size
+	t.pendingWrites[string(key)] = write
+	return nil
+}
+
+func (t *levelDBTxn) addRead(key []byte) {
+	if t.updateTxn {
+		t.reads[string(key)] = struct{}{}
+	}
+}
+
+func (t *levelDBTxn) NewIterator(opts IteratorOptions) KVIterator {
+	it := &levelDBIterator{
+		txn:     t,
+		reverse: opts.Reverse,
+	}
+	if t.err != nil || t.discarded {
+		return it
+	}
+
+	it.snapshotIterator = t.snapshot.NewIterator(nil, nil)
+	it.pendingKeys = make([]string, 0, len(t.pendingWrites))
+	for key := range t.pendingWrites {
+		it.pendingKeys = append(it.pendingKeys, key)
+	}
+	sort.Strings(it.pendingKeys)
+	return it
+}
+
+func (t *levelDBTxn) nextSequence(typeName string) (uint64, error) {
+	return t.backend.nextSequence(typeName)
+}
+
+func (t *levelDBTxn) commit() error {
+	if t.err != nil {
+		return t.err
+	}
+	if t.discarded {
+		return ErrDiscardedTxn
+	}
+	if !t.updateTxn || len(t.pendingWrites) == 0 {
+		t.discard()
+		return nil
+	}
+
+	t.backend.commitMutex.Lock()
+	defer t.backend.commitMutex.Unlock()
+
+	err := t.backend.commitTxn(t)
+	t.release()
+	return err
+}
+
+func (t *levelDBTxn) readVersion() uint64 {
+	return t.readTs
+}
+
+func (t *levelDBTxn) discard() {
+	if t.discarded || t.err != nil {
+		return
+	}
+	if t.updateTxn {
+		t.backend.commitMutex.Lock()
+		defer t.backend.commitMutex.Unlock()
+	}
+	t.release()
+}
+
+func (t *levelDBTxn) release() {
+	t.discarded = true
+	t.snapshot.Release()
+	if t.updateTxn {
+		t.backend.releaseTxn(t)
+	}
+}
+
+func (t *levelDBTxn) count(dataType interface{}, query *Query) (uint64, error) {
+	return objectCount(t, dataType, query)
+}
+
+func (t *levelDBTxn) find(result interface{}, query *Query) error {
+	return objectFind(t, result, query)
+}
+
+func (t *levelDBTxn) findOne(result interface{}, query *Query) error {
+	return objectFindOne(t, result, query)
+}
+
+func (t *levelDBTxn) get(key, result interface{}) error {
+	return objectGet(t, key, result)
+}
+
+func (t *levelDBTxn) insert(key, data interface{}) error {
+	return objectInsert(t, key, data)
+}
+
+func (t *levelDBTxn) upsert(key, data interface{}) error {
+	return objectUpsert(t, key, data)
+}
+
+func (t *levelDBTxn) update(key, data interface{}) error {
+	return objectUpdate(t, key, data)
+}
+
+func (t *levelDBTxn) delete(key, dataType interface{}) error {
+	return objectDelete(t, key, dataType)
+}
+
+type levelDBItem struct {
+	key   []byte
+	value []byte
+}
+
+func newLevelDBItem(key, value []byte) *levelDBItem {
+	return &levelDBItem{
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	}
+}
+
+func (i *levelDBItem) Key() []byte {
+	return i.key
+}
+
+func (i *levelDBItem) KeyCopy(dst []byte) []byte {
+	return append(dst[:0], i.key...)
+}
+
+func (i *levelDBItem) Value(fn func(value []byte) error) error {
+	return fn(i.value)
+}
+
+func (i *levelDBItem) ValueCopy(dst []byte) ([]byte, error) {
+	return append(dst[:0], i.value...), nil
+}
+
+func (i *levelDBItem) EstimatedSize() int64 {
+	return int64(len(i.key) + len(i.value))
+}
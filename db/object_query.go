@@ -0,0 +1,562 @@
+package db
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	bh "github.com/timshannon/badgerhold/v4"
+)
+
+// Query evaluation of the object layer, it returns the same results BadgerHold returns for the supported queries
+
+type objectRecord struct {
+	key   []byte
+	value reflect.Value // pointer to the decoded value
+}
+
+func objectFind(txn objectTxn, result interface{}, query *Query) error {
+	if query == nil {
+		query = &Query{}
+	}
+
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Kind() != reflect.Ptr || resultValue.Elem().Kind() != reflect.Slice {
+		panic("result argument must be a slice address")
+	}
+	sliceValue := resultValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	tp := dereference(elemType)
+
+	var (
+		records []objectRecord
+		err     error
+	)
+	if query.isFindByIndex() {
+		records, err = findByIndex(txn, tp, query)
+	} else {
+		records, err = runQuery(txn, tp, query, query.limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		if elemType.Kind() == reflect.Ptr {
+			sliceValue = reflect.Append(sliceValue, records[i].value)
+		} else {
+			sliceValue = reflect.Append(sliceValue, records[i].value.Elem())
+		}
+	}
+	resultValue.Elem().Set(sliceValue)
+	return nil
+}
+
+func objectFindOne(txn objectTxn, result interface{}, query *Query) error {
+	if query == nil {
+		query = &Query{}
+	}
+
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Kind() != reflect.Ptr {
+		panic("result argument must be an address")
+	}
+
+	records, err := runQuery(txn, dereference(resultValue.Elem().Type()), query, 1)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return ErrNotFound
+	}
+	resultValue.Elem().Set(records[0].value.Elem())
+	return nil
+}
+
+func objectCount(txn objectTxn, dataType interface{}, query *Query) (uint64, error) {
+	if query == nil {
+		query = &Query{}
+	}
+
+	records, err := runQuery(txn, dereference(reflect.TypeOf(dataType)), query, query.limit)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(records)), nil
+}
+
+func (q *Query) isFindByIndex() bool {
+	if q.index == "" || len(q.fieldCriteria[q.index]) != 1 {
+		return false
+	}
+	operator := q.fieldCriteria[q.index][0].operator
+	return operator == eq || operator == in
+}
+
+// findByIndex looks up keys of the index values directly instead of iterating over the index
+func findByIndex(txn objectTxn, tp reflect.Type, query *Query) ([]objectRecord, error) {
+	value := reflect.New(tp)
+	storer := newStorer(value.Interface())
+	err := query.validate(tp, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	crit := query.fieldCriteria[query.index][0]
+	indexValues := crit.values
+	if crit.operator == eq {
+		indexValues = []interface{}{crit.value}
+	}
+
+	keys := make(KeyList, 0, len(indexValues))
+	for i := range indexValues {
+		encodedValue, err := Encode(indexValues[i])
+		if err != nil {
+			return nil, err
+		}
+		keyList := make(KeyList, 0, 1)
+		err = getValue(txn, IndexKey([]byte(storer.Type()), query.index, encodedValue), &keyList)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, keyList...)
+	}
+
+	keyField, hasKeyField := getKeyField(tp)
+	records := make([]objectRecord, 0, len(keys))
+	for i := range keys {
+		value := reflect.New(tp)
+		err = getValue(txn, keys[i], value.Interface())
+		if err == ErrKeyNotFound {
+			return nil, errors.Errorf("inconsistency between index %s and stored key %x", query.index, keys[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hasKeyField {
+			err = setKeyField(keys[i], value, &keyField, storer.Type())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ok, err := query.matchesFields(keys[i], value, storer.Type())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			records = append(records, objectRecord{key: keys[i], value: value})
+		}
+	}
+
+	sortRecords(query, records)
+	return limitRecords(records, query.limit), nil
+}
+
+// runQuery returns records matching the query, sorted and limited when the query asks for it
+func runQuery(txn objectTxn, tp reflect.Type, query *Query, limit int) ([]objectRecord, error) {
+	storer := newStorer(reflect.New(tp).Interface())
+	err := query.validate(tp, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	it := txn.NewIterator(DefaultIteratorOptions)
+	defer it.Close()
+
+	if query.index != "" && !indexExists(it, storer.Type(), query.index) {
+		return nil, errors.Errorf("the index %s does not exist", query.index)
+	}
+
+	// records are only limited after sorting
+	iterationLimit := limit
+	if len(query.sort) > 0 {
+		iterationLimit = 0
+	}
+
+	keyField, hasKeyField := getKeyField(tp)
+	records := make([]objectRecord, 0)
+	err = iterateQueryKeys(it, storer.Type(), query, func(key []byte) (bool, error) {
+		value := reflect.New(tp)
+		err := getValue(txn, key, value.Interface())
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := query.matchesFields(key, value, storer.Type())
+		if err != nil || !ok {
+			return false, err
+		}
+		if hasKeyField {
+			err = setKeyField(key, value, &keyField, storer.Type())
+			if err != nil {
+				return false, err
+			}
+		}
+
+		records = append(records, objectRecord{key: key, value: value})
+		return iterationLimit != 0 && len(records) == iterationLimit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortRecords(query, records)
+	return limitRecords(records, limit), nil
+}
+
+// iterateQueryKeys calls fn with data keys matching criteria of the used index, or the key criteria when
+// there is no index, until fn returns true
+func iterateQueryKeys(it KVIterator, typeName string, query *Query, fn func(key []byte) (finish bool, err error)) error {
+	criteria := query.fieldCriteria[query.index]
+
+	if query.index == "" || len(criteria) == 0 {
+		prefix := typePrefix(typeName)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			ok, err := matchesEncodedCriteria(criteria, key, typeName)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			finish, err := fn(key)
+			if err != nil || finish {
+				return err
+			}
+		}
+		return nil
+	}
+
+	prefix := IndexKeyPrefix([]byte(typeName), query.index)
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		ok, err := matchesEncodedCriteria(criteria, item.Key()[len(prefix):], "")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		keyList := make(KeyList, 0, 1)
+		err = item.Value(func(value []byte) error {
+			return Decode(value, &keyList)
+		})
+		if err != nil {
+			return err
+		}
+		for i := range keyList {
+			finish, err := fn(keyList[i])
+			if err != nil || finish {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// indexExists returns false only when there are values of the type but no entries in the index
+func indexExists(it KVIterator, typeName, indexName string) bool {
+	prefix := typePrefix(typeName)
+	it.Seek(prefix)
+	if !it.ValidForPrefix(prefix) {
+		return true
+	}
+
+	prefix = IndexKeyPrefix([]byte(typeName), indexName)
+	it.Seek(prefix)
+	return it.ValidForPrefix(prefix)
+}
+
+func (q *Query) validate(tp reflect.Type, storer Storer) error {
+	if q.index != "" {
+		if _, ok := storer.(*reflectedStorer); ok {
+			if _, ok := tp.FieldByName(q.index); !ok {
+				return errors.Errorf("the index %s does not exist", q.index)
+			}
+		} else if _, ok := storer.Indexes()[q.index]; !ok {
+			return errors.Errorf("the index %s does not exist", q.index)
+		}
+	}
+
+	for _, field := range q.sort {
+		current := tp
+		for _, name := range strings.Split(field, ".") {
+			structField, ok := dereference(current).FieldByName(name)
+			if !ok {
+				return errors.Errorf("the field %s does not exist in the type %s", field, tp)
+			}
+			current = structField.Type
+		}
+	}
+	return nil
+}
+
+// matchesFields tests criteria of all fields apart from the ones already handled while iterating over the index
+func (q *Query) matchesFields(key []byte, value reflect.Value, typeName string) (bool, error) {
+	for _, field := range q.fields {
+		if field == q.index {
+			continue
+		}
+
+		criteria := q.fieldCriteria[field]
+		if field == Key {
+			ok, err := matchesEncodedCriteria(criteria, key, typeName)
+			if err != nil || !ok {
+				return false, err
+			}
+			continue
+		}
+
+		fieldValue, err := getFieldValue(value, field)
+		if err != nil {
+			return false, err
+		}
+		for i := range criteria {
+			ok, err := criteria[i].test(fieldValue.Interface())
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// matchesEncodedCriteria decodes an encoded value, or a data key when typeName is set, into the type of criteria values
+func matchesEncodedCriteria(criteria []*criterion, encoded []byte, typeName string) (bool, error) {
+	for _, crit := range criteria {
+		var value interface{}
+		if len(encoded) != 0 {
+			if crit.operator == in {
+				value = newElemType(crit.values[0])
+			} else {
+				value = newElemType(crit.value)
+			}
+
+			var err error
+			if typeName != "" {
+				err = decodeKey(encoded, value, typeName)
+			} else {
+				err = Decode(encoded, value)
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+
+		ok, err := crit.test(value)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func getFieldValue(value reflect.Value, field string) (reflect.Value, error) {
+	current := value
+	for _, name := range strings.Split(field, ".") {
+		current = reflect.Indirect(current).FieldByName(name)
+		if !current.IsValid() {
+			return reflect.Value{}, errors.Errorf("the field %s does not exist in the type %s", field, value.Type())
+		}
+	}
+	return current, nil
+}
+
+func (c *criterion) test(value interface{}) (bool, error) {
+	if c.operator == in {
+		for i := range c.values {
+			result, err := compareValues(value, c.values[i])
+			if err != nil {
+				return false, err
+			}
+			if result == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	result, err := compareValues(value, c.value)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.operator {
+	case eq:
+		return result == 0, nil
+	case gt:
+		return result > 0, nil
+	case lt:
+		return result < 0, nil
+	case ge:
+		return result >= 0, nil
+	case le:
+		return result <= 0, nil
+	default:
+		panic(fmt.Sprintf("invalid operator %d", c.operator))
+	}
+}
+
+func sortRecords(query *Query, records []objectRecord) {
+	if len(query.sort) == 0 {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, field := range query.sort {
+			value, _ := getFieldValue(records[i].value, field)
+			other, _ := getFieldValue(records[j].value, field)
+
+			result, err := compareValues(value.Interface(), other.Interface())
+			if err != nil {
+				result = strings.Compare(fmt.Sprintf("%s", value.Interface()), fmt.Sprintf("%s", other.Interface()))
+			}
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+}
+
+func limitRecords(records []objectRecord, limit int) []objectRecord {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
+
+type typeMismatchError struct {
+	value interface{}
+	other interface{}
+}
+
+func (e *typeMismatchError) Error() string {
+	return fmt.Sprintf("%v (%T) cannot be compared with %v (%T)", e.value, e.value, e.other, e.other)
+}
+
+// compareValues follows the rules of BadgerHold, values must have the same type unless they implement bh.Comparer
+// and values of unknown types are compared by their string representation
+// nolint:gocyclo
+func compareValues(value, other interface{}) (int, error) {
+	value = dereferenceValue(value)
+	other = dereferenceValue(other)
+	if value == nil || other == nil {
+		if value == other {
+			return 0, nil
+		}
+		return 0, &typeMismatchError{value, other}
+	}
+
+	mismatch := &typeMismatchError{value, other}
+	switch v := value.(type) {
+	case time.Time:
+		o, ok := other.(time.Time)
+		if !ok {
+			return 0, mismatch
+		}
+		if v.Equal(o) {
+			return 0, nil
+		}
+		if v.Before(o) {
+			return -1, nil
+		}
+		return 1, nil
+	case big.Float:
+		o, ok := other.(big.Float)
+		if !ok {
+			return 0, mismatch
+		}
+		return v.Cmp(&o), nil
+	case big.Int:
+		o, ok := other.(big.Int)
+		if !ok {
+			return 0, mismatch
+		}
+		return v.Cmp(&o), nil
+	case big.Rat:
+		o, ok := other.(big.Rat)
+		if !ok {
+			return 0, mismatch
+		}
+		return v.Cmp(&o), nil
+	case int, int8, int16, int32, int64:
+		if reflect.TypeOf(other) != reflect.TypeOf(value) {
+			return 0, mismatch
+		}
+		return compareInts(reflect.ValueOf(value).Int(), reflect.ValueOf(other).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		if reflect.TypeOf(other) != reflect.TypeOf(value) {
+			return 0, mismatch
+		}
+		return compareUints(reflect.ValueOf(value).Uint(), reflect.ValueOf(other).Uint()), nil
+	case float32, float64:
+		if reflect.TypeOf(other) != reflect.TypeOf(value) {
+			return 0, mismatch
+		}
+		return compareFloats(reflect.ValueOf(value).Float(), reflect.ValueOf(other).Float()), nil
+	case string:
+		o, ok := other.(string)
+		if !ok {
+			return 0, mismatch
+		}
+		return strings.Compare(v, o), nil
+	case bh.Comparer:
+		return v.Compare(other)
+	default:
+		return strings.Compare(fmt.Sprintf("%s", value), fmt.Sprintf("%s", other)), nil
+	}
+}
+
+func dereferenceValue(value interface{}) interface{} {
+	for value != nil && reflect.TypeOf(value).Kind() == reflect.Ptr {
+		pointer := reflect.ValueOf(value)
+		if pointer.IsNil() {
+			return nil
+		}
+		value = pointer.Elem().Interface()
+	}
+	return value
+}
+
+func compareInts(value, other int64) int {
+	switch {
+	case value < other:
+		return -1
+	case value > other:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUints(value, other uint64) int {
+	switch {
+	case value < other:
+		return -1
+	case value > other:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(value, other float64) int {
+	switch {
+	case value < other:
+		return -1
+	case value > other:
+		return 1
+	default:
+		return 0
+	}
+}
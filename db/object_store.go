@@ -0,0 +1,366 @@
+package db
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	bh "github.com/timshannon/badgerhold/v4"
+)
+
+// The object layer used by backends other than Badger. It stores values with the same keys, indexes and encoding
+// as BadgerHold, so that tooling working on raw keys (index verification, iterators over type prefixes) behaves
+// the same with every backend.
+
+const (
+	bhTypePrefix = "bh_"
+	bhTag        = "badgerhold"
+	bhIndexValue = "index"
+	bhUniqueTag  = "unique"
+	bhKeyValue   = "key"
+)
+
+type objectTxn interface {
+	Txn
+	nextSequence(typeName string) (uint64, error)
+}
+
+type reflectedStorer struct {
+	typeName string
+	indexes  map[string]Index
+}
+
+func (s *reflectedStorer) Type() string {
+	return s.typeName
+}
+
+func (s *reflectedStorer) Indexes() map[string]Index {
+	return s.indexes
+}
+
+var reflectedStorers sync.Map
+
+// newStorer resolves indexes of the type the same way BadgerHold does
+func newStorer(dataType interface{}) Storer {
+	if storer, ok := dataType.(Storer); ok {
+		return storer
+	}
+
+	tp := dereference(reflect.TypeOf(dataType))
+	if storer, ok := reflectedStorers.Load(tp); ok {
+		return storer.(Storer)
+	}
+
+	if tp.Name() == "" {
+		panic("invalid type for Storer, type is unnamed")
+	}
+	if tp.Kind() != reflect.Struct {
+		panic("invalid type for Storer, only structs can be stored")
+	}
+
+	storer := &reflectedStorer{
+		typeName: tp.Name(),
+		indexes:  make(map[string]Index),
+	}
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		unique := false
+		if tagValue, ok := field.Tag.Lookup(bh.BadgerHoldIndexTag); ok {
+			if tagValue == "" {
+				continue
+			}
+		} else {
+			switch field.Tag.Get(bhTag) {
+			case bhIndexValue:
+			case bhUniqueTag:
+				unique = true
+			default:
+				continue
+			}
+		}
+		storer.indexes[field.Name] = Index{
+			IndexFunc: func(name string, value interface{}) ([]byte, error) {
+				return Encode(reflect.Indirect(reflect.ValueOf(value)).FieldByName(name).Interface())
+			},
+			Unique: unique,
+		}
+	}
+
+	reflectedStorers.Store(tp, storer)
+	return storer
+}
+
+func typePrefix(typeName string) []byte {
+	return []byte(bhTypePrefix + typeName + ":")
+}
+
+func encodeKey(key interface{}, typeName string) ([]byte, error) {
+	encoded, err := Encode(key)
+	if err != nil {
+		return nil, err
+	}
+	return append(typePrefix(typeName), encoded...), nil
+}
+
+func decodeKey(data []byte, key interface{}, typeName string) error {
+	return Decode(data[len(typePrefix(typeName)):], key)
+}
+
+func getKeyField(tp reflect.Type) (reflect.StructField, bool) {
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if strings.HasPrefix(string(field.Tag), bh.BadgerholdKeyTag) || field.Tag.Get(bhTag) == bhKeyValue {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func setKeyField(key []byte, value reflect.Value, keyField *reflect.StructField, typeName string) error {
+	return decodeKey(key, reflect.Indirect(value).FieldByName(keyField.Name).Addr().Interface(), typeName)
+}
+
+func dereference(tp reflect.Type) reflect.Type {
+	for tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	return tp
+}
+
+func newElemType(dataType interface{}) interface{} {
+	return reflect.New(dereference(reflect.TypeOf(dataType))).Interface()
+}
+
+func getValue(txn Txn, key []byte, result interface{}) error {
+	item, err := txn.Get(key)
+	if err != nil {
+		return err
+	}
+	return item.Value(func(value []byte) error {
+		return Decode(value, result)
+	})
+}
+
+func objectGet(txn objectTxn, key, result interface{}) error {
+	storer := newStorer(result)
+	dataKey, err := encodeKey(key, storer.Type())
+	if err != nil {
+		return err
+	}
+
+	err = getValue(txn, dataKey, result)
+	if err == ErrKeyNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if keyField, ok := getKeyField(dereference(reflect.TypeOf(result))); ok {
+		return setKeyField(dataKey, reflect.ValueOf(result), &keyField, storer.Type())
+	}
+	return nil
+}
+
+func objectInsert(txn objectTxn, key, data interface{}) error {
+	storer := newStorer(data)
+
+	var err error
+	if _, ok := key.(sequence); ok {
+		key, err = txn.nextSequence(storer.Type())
+		if err != nil {
+			return err
+		}
+	}
+
+	dataKey, err := encodeKey(key, storer.Type())
+	if err != nil {
+		return err
+	}
+	_, err = txn.Get(dataKey)
+	if err != ErrKeyNotFound {
+		if err != nil {
+			return err
+		}
+		return ErrKeyExists
+	}
+
+	err = putValue(txn, storer, dataKey, data)
+	if err != nil {
+		return err
+	}
+
+	setInsertedKey(key, data)
+	return nil
+}
+
+// setInsertedKey sets the key field of data passed by reference if it has the zero value, like BadgerHold does
+func setInsertedKey(key, data interface{}) {
+	dataValue := reflect.Indirect(reflect.ValueOf(data))
+	if !dataValue.CanSet() {
+		return
+	}
+	keyField, ok := getKeyField(dataValue.Type())
+	if !ok {
+		return
+	}
+
+	fieldValue := dataValue.FieldByName(keyField.Name)
+	keyValue := reflect.ValueOf(key)
+	if keyValue.Type() != keyField.Type || !fieldValue.CanSet() || !fieldValue.IsZero() {
+		return
+	}
+	fieldValue.Set(keyValue)
+}
+
+func objectUpdate(txn objectTxn, key, data interface{}) error {
+	storer := newStorer(data)
+	dataKey, err := encodeKey(key, storer.Type())
+	if err != nil {
+		return err
+	}
+
+	existing := newElemType(data)
+	err = getValue(txn, dataKey, existing)
+	if err == ErrKeyNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = updateIndexes(txn, storer, dataKey, existing, true)
+	if err != nil {
+		return err
+	}
+	return putValue(txn, storer, dataKey, data)
+}
+
+func objectUpsert(txn objectTxn, key, data interface{}) error {
+	storer := newStorer(data)
+	dataKey, err := encodeKey(key, storer.Type())
+	if err != nil {
+		return err
+	}
+
+	existing := newElemType(data)
+	err = getValue(txn, dataKey, existing)
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
+	if err == nil {
+		err = updateIndexes(txn, storer, dataKey, existing, true)
+		if err != nil {
+			return err
+		}
+	}
+	return putValue(txn, storer, dataKey, data)
+}
+
+func objectDelete(txn objectTxn, key, dataType interface{}) error {
+	storer := newStorer(dataType)
+	dataKey, err := encodeKey(key, storer.Type())
+	if err != nil {
+		return err
+	}
+
+	existing := newElemType(dataType)
+	err = getValue(txn, dataKey, existing)
+	if err == ErrKeyNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = txn.Delete(dataKey)
+	if err != nil {
+		return err
+	}
+	return updateIndexes(txn, storer, dataKey, existing, true)
+}
+
+func putValue(txn objectTxn, storer Storer, dataKey []byte, data interface{}) error {
+	value, err := Encode(data)
+	if err != nil {
+		return err
+	}
+	err = txn.Set(dataKey, value)
+	if err != nil {
+		return err
+	}
+	return updateIndexes(txn, storer, dataKey, data, false)
+}
+
+// updateIndexes adds dataKey to or removes it from the indexes of data, when removing pass the previously stored data
+func updateIndexes(txn objectTxn, storer Storer, dataKey []byte, data interface{}, remove bool) error {
+	for indexName, index := range storer.Indexes() {
+		err := updateIndex(txn, storer.Type(), indexName, index, dataKey, data, remove)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateIndex(txn objectTxn, typeName, indexName string, index Index, dataKey []byte, data interface{}, remove bool) error {
+	indexValue, err := index.IndexFunc(indexName, data)
+	if err != nil {
+		return err
+	}
+	if indexValue == nil {
+		return nil
+	}
+
+	indexKey := IndexKey([]byte(typeName), indexName, indexValue)
+	keyList := make(KeyList, 0, 1)
+	err = getValue(txn, indexKey, &keyList)
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
+	if err == nil && index.Unique && !remove {
+		return bh.ErrUniqueExists
+	}
+
+	if remove {
+		keyList = removeFromKeyList(keyList, dataKey)
+	} else {
+		keyList = addToKeyList(keyList, dataKey)
+	}
+
+	if len(keyList) == 0 {
+		return txn.Delete(indexKey)
+	}
+	encodedKeyList, err := Encode(keyList)
+	if err != nil {
+		return err
+	}
+	return txn.Set(indexKey, encodedKeyList)
+}
+
+func addToKeyList(keyList KeyList, key []byte) KeyList {
+	i := searchKeyList(keyList, key)
+	if i < len(keyList) && bytes.Equal(keyList[i], key) {
+		return keyList
+	}
+	keyList = append(keyList, nil)
+	copy(keyList[i+1:], keyList[i:])
+	keyList[i] = key
+	return keyList
+}
+
+func removeFromKeyList(keyList KeyList, key []byte) KeyList {
+	i := searchKeyList(keyList, key)
+	if i < len(keyList) && bytes.Equal(keyList[i], key) {
+		return append(keyList[:i], keyList[i+1:]...)
+	}
+	return keyList
+}
+
+func searchKeyList(keyList KeyList, key []byte) int {
+	return sort.Search(len(keyList), func(i int) bool {
+		return bytes.Compare(keyList[i], key) >= 0
+	})
+}
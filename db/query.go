@@ -0,0 +1,166 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	bh "github.com/timshannon/badgerhold/v4"
+)
+
+type operator int
+
+const (
+	eq operator = iota
+	gt
+	lt
+	ge
+	le
+	in
+)
+
+// Query is the subset of BadgerHold queries supported by every backend. The Badger backend translates
+// it to a BadgerHold query, other backends evaluate it with the same semantics.
+type Query struct {
+	index         string
+	currentField  string
+	fields        []string // fields in the order they were added, keeps the translation deterministic
+	fieldCriteria map[string][]*criterion
+	limit         int
+	sort          []string
+}
+
+type Criterion struct {
+	query *Query
+}
+
+type criterion struct {
+	operator operator
+	value    interface{}
+	values   []interface{}
+}
+
+func Where(field string) *Criterion {
+	query := &Query{fieldCriteria: make(map[string][]*criterion)}
+	return query.And(field)
+}
+
+func (q *Query) And(field string) *Criterion {
+	if field != Key && strings.ToUpper(field[:1]) != field[:1] {
+		panic("the first letter of a field in a query must be upper-case")
+	}
+	q.currentField = field
+	return &Criterion{query: q}
+}
+
+// Index makes the query look up values of the first criterion on the field in the index with the same name
+func (q *Query) Index(indexName string) *Query {
+	q.index = indexName
+	return q
+}
+
+func (q *Query) Limit(amount int) *Query {
+	if amount <= 0 {
+		panic("limit must be a positive number")
+	}
+	q.limit = amount
+	return q
+}
+
+func (q *Query) SortBy(fields ...string) *Query {
+	for i := range fields {
+		if fields[i] == Key {
+			panic("cannot sort by Key")
+		}
+	}
+	q.sort = append(q.sort, fields...)
+	return q
+}
+
+func (c *Criterion) Eq(value interface{}) *Query {
+	return c.op(eq, value)
+}
+
+func (c *Criterion) Gt(value interface{}) *Query {
+	return c.op(gt, value)
+}
+
+func (c *Criterion) Lt(value interface{}) *Query {
+	return c.op(lt, value)
+}
+
+func (c *Criterion) Ge(value interface{}) *Query {
+	return c.op(ge, value)
+}
+
+func (c *Criterion) Le(value interface{}) *Query {
+	return c.op(le, value)
+}
+
+func (c *Criterion) In(values ...interface{}) *Query {
+	return c.add(&criterion{operator: in, values: values})
+}
+
+func (c *Criterion) op(op operator, value interface{}) *Query {
+	return c.add(&criterion{operator: op, value: value})
+}
+
+func (c *Criterion) add(crit *criterion) *Query {
+	q := c.query
+	if _, ok := q.fieldCriteria[q.currentField]; !ok {
+		q.fields = append(q.fields, q.currentField)
+	}
+	q.fieldCriteria[q.currentField] = append(q.fieldCriteria[q.currentField], crit)
+	return q
+}
+
+func (q *Query) toBadgerhold() *bh.Query {
+	if q == nil {
+		return nil
+	}
+
+	var query *bh.Query
+	for _, field := range q.fields {
+		for _, crit := range q.fieldCriteria[field] {
+			var bhCriterion *bh.Criterion
+			if query == nil {
+				bhCriterion = bh.Where(field)
+			} else {
+				bhCriterion = query.And(field)
+			}
+			query = crit.toBadgerhold(bhCriterion)
+		}
+	}
+	if query == nil {
+		query = &bh.Query{}
+	}
+
+	if q.index != "" {
+		query = query.Index(q.index)
+	}
+	if q.limit != 0 {
+		query = query.Limit(q.limit)
+	}
+	if len(q.sort) > 0 {
+		query = query.SortBy(q.sort...)
+	}
+	return query
+}
+
+func (c *criterion) toBadgerhold(bhCriterion *bh.Criterion) *bh.Query {
+	switch c.operator {
+	case eq:
+		return bhCriterion.Eq(c.value)
+	case gt:
+		return bhCriterion.Gt(c.value)
+	case lt:
+		return bhCriterion.Lt(c.value)
+	case ge:
+		return bhCriterion.Ge(c.value)
+	case le:
+		return bhCriterion.Le(c.value)
+	case in:
+		return bhCriterion.In(c.values...)
+	default:
+		panic(fmt.Sprintf("invalid operator %d", c.operator))
+	}
+}
@@ -0,0 +1,338 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	replicationSnapshotBatchSize = 1000
+	replicationMaxPendingChanges = 1_000_000
+)
+
+var (
+	ErrReplicaTooSlow     = fmt.Errorf("replica does not keep up with the changes of the primary database")
+	ErrSubscriptionClosed = fmt.Errorf("subscription to the changes of the database was closed")
+	ErrMissedChanges      = fmt.Errorf("subscription to the changes of the database started after the snapshot")
+)
+
+// Change is a single key written on the primary database, Value is empty for deleted keys
+type Change struct {
+	Key     []byte
+	Value   []byte
+	Deleted bool
+
+	// version is the commit timestamp of the change on the primary, it isn't sent to replicas
+	version uint64
+}
+
+// StreamChanges calls send with every key of the database in key order, then once with snapshotDone set,
+// and afterwards with the changes of every commit until ctx is cancelled or send fails.
+// The primary database is never written to, commit timestamps tell which changes the snapshot already includes.
+func (d *Database) StreamChanges(ctx context.Context, send func(changes []Change, snapshotDone bool) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := newChangeQueue()
+	subscribed := make(chan struct{})
+	subscription := make(chan error, 1)
+	go func() {
+		subscription <- d.backend.subscribe(ctx, func() { close(subscribed) }, queue.push)
+	}()
+
+	select {
+	case <-subscribed:
+	case err := <-subscription:
+		return subscriptionError(err)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	snapshotVersion, err := d.sendSnapshot(send)
+	if err != nil {
+		return err
+	}
+	err = queue.startAfter(snapshotVersion)
+	if err != nil {
+		return err
+	}
+	err = send(nil, true)
+	if err != nil {
+		return err
+	}
+
+	for {
+		changes, err := queue.pop(ctx, subscription)
+		if err != nil {
+			return err
+		}
+		err = send(changes, false)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sendSnapshot returns the commit timestamp the snapshot was read at
+func (d *Database) sendSnapshot(send func(changes []Change, snapshotDone bool) error) (version uint64, err error) {
+	err = d.view(func(txn backendTxn) error {
+		version = txn.readVersion()
+
+		it := txn.NewIterator(PrefetchIteratorOpts)
+		defer it.Close()
+
+		changes := make([]Change, 0, replicationSnapshotBatchSize)
+		for it.Seek(nil); it.Valid(); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, Change{Key: item.KeyCopy(nil), Value: value})
+			if len(changes) < replicationSnapshotBatchSize {
+				continue
+			}
+
+			err = send(changes, false)
+			if err != nil {
+				return err
+			}
+			changes = make([]Change, 0, replicationSnapshotBatchSize)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return send(changes, false)
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return version, nil
+}
+
+// ApplyChanges writes the changes of a primary database, in a single transaction unless it gets too big
+func (d *Database) ApplyChanges(changes []Change) error {
+	err := d.RawUpdate(func(txn Txn) error {
+		for i := range changes {
+			err := applyChange(txn, &changes[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrTxnTooBig) && len(changes) > 1 {
+		half := len(changes) / 2
+		err = d.ApplyChanges(changes[:half])
+		if err != nil {
+			return err
+		}
+		return d.ApplyChanges(changes[half:])
+	}
+	return err
+}
+
+func applyChange(txn Txn, change *Change) error {
+	if change.Deleted {
+		return txn.Delete(change.Key)
+	}
+	return txn.Set(change.Key, change.Value)
+}
+
+// SnapshotWriter overwrites a database with the snapshot sent by StreamChanges of a primary database.
+// The keys missing from the snapshot are deleted, so a replica can resync without dropping its data first.
+type SnapshotWriter struct {
+	database *Database
+	lastKey  []byte
+}
+
+func (d *Database) NewSnapshotWriter() *SnapshotWriter {
+	return &SnapshotWriter{database: d}
+}
+
+// Write expects the batches of the snapshot in the order they were sent
+func (w *SnapshotWriter) Write(changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	lastKey := changes[len(changes)-1].Key
+	staleKeys, err := w.staleKeys(changes, lastKey)
+	if err != nil {
+		return err
+	}
+	for i := range staleKeys {
+		changes = append(changes, Change{Key: staleKeys[i], Deleted: true})
+	}
+
+	err = w.database.ApplyChanges(changes)
+	if err != nil {
+		return err
+	}
+	w.lastKey = lastKey
+	return nil
+}
+
+// Finish deletes the keys sorting after the last key of the snapshot
+func (w *SnapshotWriter) Finish() error {
+	staleKeys, err := w.staleKeys(nil, nil)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]Change, 0, len(staleKeys))
+	for i := range staleKeys {
+		changes = append(changes, Change{Key: staleKeys[i], Deleted: true})
+	}
+	return w.database.ApplyChanges(changes)
+}
+
+// staleKeys returns the keys after the previous batch and up to lastKey, or all remaining keys when lastKey is nil,
+// which are not part of the sorted changes
+func (w *SnapshotWriter) staleKeys(changes []Change, lastKey []byte) (staleKeys [][]byte, err error) {
+	err = w.database.View(func(txn Txn) error {
+		it := txn.NewIterator(KeyIteratorOpts)
+		defer it.Close()
+
+		i := 0
+		for it.Seek(w.lastKey); it.Valid(); it.Next() {
+			key := it.Item().Key()
+			if w.lastKey != nil && bytes.Equal(key, w.lastKey) {
+				continue
+			}
+			if lastKey != nil && bytes.Compare(key, lastKey) > 0 {
+				return nil
+			}
+			for i < len(changes) && bytes.Compare(changes[i].Key, key) < 0 {
+				i++
+			}
+			if i < len(changes) && bytes.Equal(changes[i].Key, key) {
+				continue
+			}
+			staleKeys = append(staleKeys, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+	return staleKeys, err
+}
+
+// changeQueue buffers the published changes, so that a slow replica never blocks commits of the primary.
+// Commit timestamps grow by one with every commit and commits are published in order, so the first commit
+// after the snapshot having a later timestamp means that the subscription started too late. The stream
+// then fails and the replica resyncs.
+type changeQueue struct {
+	mutex   sync.Mutex
+	batches [][]Change
+	pending int
+	notify  chan struct{}
+	started bool
+	// changes up to snapshotVersion are part of the snapshot, lastVersion is the timestamp of the last queued commit
+	snapshotVersion uint64
+	lastVersion     uint64
+}
+
+func newChangeQueue() *changeQueue {
+	return &changeQueue{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// startAfter drops the buffered changes which are part of the snapshot read at the given version
+func (q *changeQueue) startAfter(snapshotVersion uint64) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	batches := q.batches
+	q.batches = nil
+	q.pending = 0
+	q.started = true
+	q.snapshotVersion = snapshotVersion
+	q.lastVersion = snapshotVersion
+	for i := range batches {
+		err := q.enqueue(batches[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *changeQueue) push(changes []Change) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.started {
+		return q.append(changes)
+	}
+	return q.enqueue(changes)
+}
+
+// enqueue must be called with mutex held after the snapshot was taken. Changes with timestamps lower than
+// the last one are either part of the snapshot or values rewritten by the garbage collector of Badger.
+func (q *changeQueue) enqueue(changes []Change) error {
+	batch := make([]Change, 0, len(changes))
+	for i := range changes {
+		version := changes[i].version
+		if version <= q.snapshotVersion || version < q.lastVersion {
+			continue
+		}
+		if version > q.lastVersion+1 {
+			return errors.WithStack(ErrMissedChanges)
+		}
+		q.lastVersion = version
+		batch = append(batch, changes[i])
+	}
+	return q.append(batch)
+}
+
+func (q *changeQueue) append(batch []Change) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if q.pending+len(batch) > replicationMaxPendingChanges {
+		return errors.WithStack(ErrReplicaTooSlow)
+	}
+	q.batches = append(q.batches, batch)
+	q.pending += len(batch)
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *changeQueue) pop(ctx context.Context, subscription <-chan error) ([]Change, error) {
+	for {
+		q.mutex.Lock()
+		if len(q.batches) > 0 {
+			batch := q.batches[0]
+			q.batches[0] = nil
+			q.batches = q.batches[1:]
+			q.pending -= len(batch)
+			q.mutex.Unlock()
+			return batch, nil
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-q.notify:
+		case err := <-subscription:
+			return nil, subscriptionError(err)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// subscriptionError is never nil, a subscription only ends without an error when the database gets closed
+func subscriptionError(err error) error {
+	if err == nil {
+		return errors.WithStack(ErrSubscriptionClosed)
+	}
+	return errors.WithStack(err)
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReplicationTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	backendName string
+	primary     *Database
+	replica     *Database
+}
+
+func (s *ReplicationTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ReplicationTestSuite) SetupTest() {
+	var err error
+	s.primary, err = NewInMemoryDatabaseWithBackend(s.backendName)
+	s.NoError(err)
+	s.replica, err = NewInMemoryDatabaseWithBackend(s.backendName)
+	s.NoError(err)
+}
+
+func (s *ReplicationTestSuite) TearDownTest() {
+	s.NoError(s.primary.Close())
+	s.NoError(s.replica.Close())
+}
+
+func (s *ReplicationTestSuite) TestStreamChanges_ReplicatesSnapshotAndLaterCommits() {
+	s.setKeys(s.primary, "a", "c", "e")
+	s.setKeys(s.replica, "b", "c", "f")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	snapshotDone := make(chan struct{})
+	streamDone := make(chan error, 1)
+	go func() {
+		writer := s.replica.NewSnapshotWriter()
+		synced := false
+		streamDone <- s.primary.StreamChanges(ctx, func(changes []Change, done bool) error {
+			if done {
+				defer close(snapshotDone)
+				synced = true
+				return writer.Finish()
+			}
+			if !synced {
+				return writer.Write(changes)
+			}
+			return s.replica.ApplyChanges(changes)
+		})
+	}()
+
+	select {
+	case <-snapshotDone:
+	case err := <-streamDone:
+		s.Failf("stream failed before the snapshot was sent", "%+v", err)
+	}
+	s.Equal(s.keys(s.primary), s.keys(s.replica))
+
+	s.setKeys(s.primary, "d")
+	err := s.primary.RawUpdate(func(txn Txn) error {
+		return txn.Delete([]byte("a"))
+	})
+	s.NoError(err)
+
+	s.Eventually(func() bool {
+		return reflect.DeepEqual(s.keys(s.primary), s.keys(s.replica))
+	}, time.Second, 10*time.Millisecond)
+	s.Equal([]string{"c", "d", "e"}, s.keys(s.replica))
+
+	cancel()
+	s.ErrorIs(<-streamDone, context.Canceled)
+}
+
+func (s *ReplicationTestSuite) TestStreamChanges_DoesNotWriteToPrimary() {
+	s.setKeys(s.primary, "a")
+	version := s.readVersion(s.primary)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	snapshotDone := make(chan struct{})
+	streamDone := make(chan error, 1)
+	go func() {
+		streamDone <- s.primary.StreamChanges(ctx, func(changes []Change, done bool) error {
+			if done {
+				close(snapshotDone)
+			}
+			return nil
+		})
+	}()
+	<-snapshotDone
+	cancel()
+	s.ErrorIs(<-streamDone, context.Canceled)
+
+	s.Equal(version, s.readVersion(s.primary))
+	s.Equal([]string{"a"}, s.keys(s.primary))
+}
+
+func (s *ReplicationTestSuite) TestChangeQueue_DropsChangesOfSnapshot() {
+	queue := newChangeQueue()
+	err := queue.push([]Change{{Key: []byte("a"), version: 4}, {Key: []byte("b"), version: 5}})
+	s.NoError(err)
+
+	err = queue.startAfter(4)
+	s.NoError(err)
+	err = queue.push([]Change{{Key: []byte("c"), version: 6}, {Key: []byte("a"), version: 2}})
+	s.NoError(err)
+
+	changes, err := queue.pop(context.Background(), nil)
+	s.NoError(err)
+	s.Equal([]Change{{Key: []byte("b"), version: 5}}, changes)
+	changes, err = queue.pop(context.Background(), nil)
+	s.NoError(err)
+	s.Equal([]Change{{Key: []byte("c"), version: 6}}, changes)
+}
+
+func (s *ReplicationTestSuite) TestChangeQueue_MissedChanges() {
+	queue := newChangeQueue()
+	err := queue.startAfter(4)
+	s.NoError(err)
+
+	err = queue.push([]Change{{Key: []byte("a"), version: 6}})
+	s.ErrorIs(err, ErrMissedChanges)
+}
+
+func (s *ReplicationTestSuite) TestSnapshotWriter_DeletesKeysMissingFromSnapshot() {
+	s.setKeys(s.replica, "a", "b", "d", "f", "g")
+
+	writer := s.replica.NewSnapshotWriter()
+	err := writer.Write([]Change{{Key: []byte("b"), Value: []byte("b")}, {Key: []byte("c"), Value: []byte("c")}})
+	s.NoError(err)
+	err = writer.Write([]Change{{Key: []byte("e"), Value: []byte("e")}})
+	s.NoError(err)
+	s.Equal([]string{"b", "c", "e", "f", "g"}, s.keys(s.replica))
+
+	err = writer.Finish()
+	s.NoError(err)
+	s.Equal([]string{"b", "c", "e"}, s.keys(s.replica))
+}
+
+func (s *ReplicationTestSuite) setKeys(database *Database, keys ...string) {
+	err := database.RawUpdate(func(txn Txn) error {
+		for _, key := range keys {
+			err := txn.Set([]byte(key), []byte(key))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.NoError(err)
+}
+
+func (s *ReplicationTestSuite) readVersion(database *Database) (version uint64) {
+	err := database.view(func(txn backendTxn) error {
+		version = txn.readVersion()
+		return nil
+	})
+	s.NoError(err)
+	return version
+}
+
+func (s *ReplicationTestSuite) keys(database *Database) []string {
+	keys := make([]string, 0)
+	err := database.Iterator(nil, KeyIteratorOpts, func(item Item) (bool, error) {
+		keys = append(keys, string(item.Key()))
+		return Continue, nil
+	})
+	if !errors.Is(err, ErrIteratorFinished) {
+		s.NoError(err)
+	}
+	return keys
+}
+
+func TestReplicationTestSuite(t *testing.T) {
+	for _, backendName := range []string{BadgerBackend, LevelDBBackend} {
+		t.Run(backendName, func(t *testing.T) {
+			suite.Run(t, &ReplicationTestSuite{backendName: backendName})
+		})
+	}
+}
@@ -6,13 +6,16 @@ import (
 	"github.com/pkg/errors"
 )
 
+const cloneBatchSize = 1000
+
 type TestDB struct {
 	DB       *Database
 	Teardown func() error
 }
 
+// NewTestDB creates an in-memory database with the backend chosen by the HUBBLE_TEST_DB_BACKEND env variable
 func NewTestDB() (*TestDB, error) {
-	db, err := NewInMemoryDatabase()
+	db, err := NewInMemoryDatabaseWithBackend(testBackend())
 	if err != nil {
 		return nil, err
 	}
@@ -28,21 +31,63 @@ func NewTestDB() (*TestDB, error) {
 }
 
 func (d *TestDB) Clone() (*TestDB, error) {
-	clonedBadger, err := NewTestDB()
+	clonedDB, err := NewTestDB()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
 	var backup bytes.Buffer
-	_, err = d.DB.store.Badger().Backup(&backup, 0)
+	_, err = d.DB.backend.backup(&backup, 0)
+	if errors.Is(err, ErrUnsupportedByBackend) {
+		err = copyAllKeys(d.DB, clonedDB.DB)
+		if err != nil {
+			return nil, err
+		}
+		return clonedDB, nil
+	}
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	err = clonedBadger.DB.store.Badger().Load(&backup, 16)
+	err = clonedDB.DB.backend.load(&backup, 16)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	return clonedBadger, nil
+	return clonedDB, nil
+}
+
+func copyAllKeys(src, dst *Database) error {
+	keys := make([][]byte, 0, cloneBatchSize)
+	values := make([][]byte, 0, cloneBatchSize)
+	writeBatch := func() error {
+		err := dst.RawUpdate(func(txn Txn) error {
+			for i := range keys {
+				err := txn.Set(keys[i], values[i])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		keys, values = keys[:0], values[:0]
+		return err
+	}
+
+	err := src.Iterator(nil, PrefetchIteratorOpts, func(item Item) (bool, error) {
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, item.KeyCopy(nil))
+		values = append(values, value)
+		if len(keys) < cloneBatchSize {
+			return false, nil
+		}
+		return false, writeBatch()
+	})
+	if err != nil && !errors.Is(err, ErrIteratorFinished) {
+		return err
+	}
+	return writeBatch()
 }
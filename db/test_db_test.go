@@ -4,7 +4,6 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type someStruct struct {
@@ -43,7 +42,7 @@ func TestTestDB_Prune(t *testing.T) {
 
 	var res someStruct
 	err = bdg.DB.Get(testStruct.Name, &res)
-	require.ErrorIs(t, err, bh.ErrNotFound)
+	require.ErrorIs(t, err, ErrNotFound)
 }
 
 func TestTestDB_Clone(t *testing.T) {
@@ -132,6 +132,17 @@ func (s *WithdrawalsE2ETestSuite) testClaimTokens(transactionHash common.Hash) {
 		s.NoError(err)
 		s.NotZero(receipt.Status)
 	})
+
+	claimed, err := s.ETHClient.IsWithdrawClaimed(proof.Root, proof.Path.Path)
+	s.NoError(err)
+	s.True(claimed)
+
+	var claims []dto.WithdrawClaim
+	err = s.RPCClient.CallFor(&claims, "hubble_getWithdrawProofsByPublicKey", []interface{}{s.senderWallet.PublicKey()})
+	s.NoError(err)
+	for i := range claims {
+		s.NotEqual(transactionHash, claims[i].TransactionHash)
+	}
 }
 
 func (s *WithdrawalsE2ETestSuite) getWithdrawManager() (*withdrawmanager.WithdrawManager, common.Address) {
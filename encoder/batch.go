@@ -3,6 +3,7 @@ package encoder
 import (
 	"math/big"
 
+	"github.com/Worldcoin/hubble-commander/contracts/rollup"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -90,6 +91,54 @@ func DecodeMMBatchCalldata(rollupABI *abi.ABI, calldata []byte) ([]DecodedMMComm
 	return commitments, nil
 }
 
+type DecodedDepositBatchCalldata struct {
+	BatchID      models.Uint256
+	Previous     models.CommitmentInclusionProof
+	VacancyProof models.SubtreeVacancyProof
+}
+
+// DecodeDepositBatchCalldata
+//   uint256 batchID,
+//   CommitmentInclusionProof previous,
+//   SubtreeVacancyProof vacant
+func DecodeDepositBatchCalldata(rollupABI *abi.ABI, calldata []byte) (*DecodedDepositBatchCalldata, error) {
+	unpacked, err := rollupABI.Methods["submitDeposits"].Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	batchID := unpacked[0].(*big.Int)
+	previous := *abi.ConvertType(unpacked[1], new(rollup.TypesCommitmentInclusionProof)).(*rollup.TypesCommitmentInclusionProof)
+	vacant := *abi.ConvertType(unpacked[2], new(rollup.TypesSubtreeVacancyProof)).(*rollup.TypesSubtreeVacancyProof)
+
+	return &DecodedDepositBatchCalldata{
+		BatchID: models.MakeUint256FromBig(*batchID),
+		Previous: models.CommitmentInclusionProof{
+			CommitmentInclusionProofBase: models.CommitmentInclusionProofBase{
+				StateRoot: previous.Commitment.StateRoot,
+				Path: &models.MerklePath{
+					Path:  uint32(previous.Path.Uint64()),
+					Depth: uint8(len(previous.Witness)),
+				},
+				Witness: bytesToWitness(previous.Witness),
+			},
+			BodyRoot: previous.Commitment.BodyRoot,
+		},
+		VacancyProof: models.SubtreeVacancyProof{
+			PathAtDepth: uint32(vacant.PathAtDepth.Uint64()),
+			Witness:     bytesToWitness(vacant.Witness),
+		},
+	}, nil
+}
+
+func bytesToWitness(witness [][32]byte) models.Witness {
+	result := make(models.Witness, 0, len(witness))
+	for i := range witness {
+		result = append(result, witness[i])
+	}
+	return result
+}
+
 func CommitmentsToTransferAndC2TSubmitBatchFields(batchID *models.Uint256, commitments []models.CommitmentWithTxs) (
 	bigBatchID *big.Int,
 	stateRoots [][32]byte,
@@ -1,6 +1,7 @@
 package encoder
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -88,3 +89,35 @@ func TestDecodeMMBatchCalldata(t *testing.T) {
 	require.Equal(t, *batchID, decoded.ID.BatchID)
 	require.EqualValues(t, 0, decoded.ID.IndexInBatch)
 }
+
+func TestDecodeDepositBatchCalldata(t *testing.T) {
+	//goland:noinspection GoDeprecation
+	rollupABI, err := abi.JSON(strings.NewReader(rollup.RollupABI))
+	require.NoError(t, err)
+
+	previous := rollup.TypesCommitmentInclusionProof{
+		Commitment: rollup.TypesCommitment{
+			StateRoot: utils.RandomHash(),
+			BodyRoot:  utils.RandomHash(),
+		},
+		Path:    big.NewInt(3),
+		Witness: [][32]byte{utils.RandomHash(), utils.RandomHash()},
+	}
+	vacant := rollup.TypesSubtreeVacancyProof{
+		PathAtDepth: big.NewInt(5),
+		Witness:     [][32]byte{utils.RandomHash()},
+	}
+	calldata, err := rollupABI.Pack("submitDeposits", big.NewInt(7), previous, vacant)
+	require.NoError(t, err)
+
+	decoded, err := DecodeDepositBatchCalldata(&rollupABI, calldata)
+	require.NoError(t, err)
+
+	require.Equal(t, models.MakeUint256(7), decoded.BatchID)
+	require.EqualValues(t, previous.Commitment.StateRoot, decoded.Previous.StateRoot)
+	require.EqualValues(t, previous.Commitment.BodyRoot, decoded.Previous.BodyRoot)
+	require.Equal(t, models.MerklePath{Path: 3, Depth: 2}, *decoded.Previous.Path)
+	require.Equal(t, previous.Witness, decoded.Previous.Witness.Bytes())
+	require.EqualValues(t, 5, decoded.VacancyProof.PathAtDepth)
+	require.Equal(t, vacant.Witness, decoded.VacancyProof.Witness.Bytes())
+}
@@ -0,0 +1,24 @@
+package eth
+
+import (
+	"context"
+
+	"github.com/Worldcoin/hubble-commander/models"
+)
+
+// GetBalance returns the balance of the commander account in wei
+func (c *Client) GetBalance() (*models.Uint256, error) {
+	balance, err := c.Blockchain.GetBackend().BalanceAt(context.Background(), c.Blockchain.GetAccount().From, nil)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewUint256FromBig(*balance), nil
+}
+
+func (c *Client) SuggestGasPrice() (*models.Uint256, error) {
+	gasPrice, err := c.Blockchain.GetBackend().SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return models.NewUint256FromBig(*gasPrice), nil
+}
@@ -32,7 +32,7 @@ func (a *AccountManager) RegisterBatchAccountAndWait(publicKeys []models.PublicK
 		return nil, err
 	}
 
-	return a.retrieveRegisteredPubKeyIDs(receipt)
+	return a.RetrieveRegisteredPubKeyIDs(receipt)
 }
 
 func (a *AccountManager) RegisterBatchAccount(ctx context.Context, publicKeys []models.PublicKey) (*types.Transaction, error) {
@@ -53,7 +53,7 @@ func (a *AccountManager) RegisterBatchAccount(ctx context.Context, publicKeys []
 	return tx, err
 }
 
-func (a *AccountManager) retrieveRegisteredPubKeyIDs(receipt *types.Receipt) ([]uint32, error) {
+func (a *AccountManager) RetrieveRegisteredPubKeyIDs(receipt *types.Receipt) ([]uint32, error) {
 	log, err := retrieveLog(receipt, BatchPubkeyRegisteredEvent)
 	if err != nil {
 		return nil, err
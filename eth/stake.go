@@ -0,0 +1,8 @@
+package eth
+
+import "github.com/Worldcoin/hubble-commander/models"
+
+// GetStakeAmount returns the amount of wei staked with every submitted batch
+func (c *Client) GetStakeAmount() models.Uint256 {
+	return *c.config.StakeAmount
+}
@@ -59,6 +59,7 @@ func NewConfiguredTestClient(cfg *rollup.DeploymentConfig, clientCfg *TestClient
 			SpokeRegistry:                  contracts.SpokeRegistryAddress,
 			DepositManager:                 contracts.DepositManagerAddress,
 			Rollup:                         contracts.RollupAddress,
+			WithdrawManager:                contracts.WithdrawManagerAddress,
 			GenesisAccounts:                nil,
 		},
 		Rollup:          contracts.Rollup,
@@ -0,0 +1,26 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// withdrawBitmapSlot is the storage slot of WithdrawManager.bitmap, a mapping(bytes32 => mapping(uint256 => uint256))
+// from withdraw roots to words with one bit per withdraw tree leaf, set when the leaf is claimed. The contract
+// has no getter for it so it is read directly from the storage.
+const withdrawBitmapSlot = 0
+
+// IsWithdrawClaimed returns whether the tokens of the leaf at the given index of the withdraw tree were claimed
+func (c *Client) IsWithdrawClaimed(withdrawRoot common.Hash, leafIndex uint32) (bool, error) {
+	rootSlot := crypto.Keccak256(withdrawRoot.Bytes(), common.BigToHash(big.NewInt(withdrawBitmapSlot)).Bytes())
+	wordSlot := crypto.Keccak256Hash(common.BigToHash(big.NewInt(int64(leafIndex/256))).Bytes(), rootSlot)
+
+	word, err := c.Blockchain.GetBackend().StorageAt(context.Background(), c.ChainState.WithdrawManager, wordSlot, nil)
+	if err != nil {
+		return false, err
+	}
+	return new(big.Int).SetBytes(word).Bit(int(leafIndex%256)) == 1, nil
+}
@@ -0,0 +1,39 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type IsWithdrawClaimedTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	client *TestClient
+}
+
+func (s *IsWithdrawClaimedTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *IsWithdrawClaimedTestSuite) SetupTest() {
+	client, err := NewTestClient()
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *IsWithdrawClaimedTestSuite) TearDownTest() {
+	s.client.Close()
+}
+
+func (s *IsWithdrawClaimedTestSuite) TestIsWithdrawClaimed_NotClaimed() {
+	claimed, err := s.client.IsWithdrawClaimed(utils.RandomHash(), 300)
+	s.NoError(err)
+	s.False(claimed)
+}
+
+func TestIsWithdrawClaimedTestSuite(t *testing.T) {
+	suite.Run(t, new(IsWithdrawClaimedTestSuite))
+}
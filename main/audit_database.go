@@ -7,7 +7,6 @@ import (
 
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/db"
-	"github.com/dgraph-io/badger/v3"
 	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -18,14 +17,11 @@ import (
 func auditDatabase(ctx *cli.Context) error {
 	log.Info("Iterating the database")
 
-	path := "/home/parallels/wc/hc/db/data/hubble"
-
-	cfg := &config.BadgerConfig{
-		Path: path,
-	}
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	path := cfg.Badger.Path
 
 	// 1. open the database
-	database, err := db.NewDatabase(cfg)
+	database, err := db.NewDatabase(cfg.Badger)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -39,7 +35,7 @@ func auditDatabase(ctx *cli.Context) error {
 	binToKeySize := make(map[string]uint64)
 	binToValueSize := make(map[string]uint64)
 
-	err = database.Iterator([]byte{}, db.PrefetchIteratorOpts, func(item *badger.Item) (bool, error) {
+	err = database.Iterator([]byte{}, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
 		key := item.Key()
 		var value []byte
 
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/scripts"
+	"github.com/urfave/cli/v2"
+)
+
+func backupDatabase(ctx *cli.Context) error {
+	hubbleClient := client.NewClient(ctx.String("rpcurl"), ctx.String("authKey"))
+	backup, err := hubbleClient.Backup(dto.BackupParams{
+		Path:  ctx.String("file"),
+		Since: ctx.Uint64("since"),
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", result)
+	return nil
+}
+
+func restoreDatabase(ctx *cli.Context) error {
+	return scripts.RestoreDatabase(ctx.StringSlice("file"))
+}
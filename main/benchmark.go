@@ -4,13 +4,11 @@ import (
 	"crypto/rand"
 	"time"
 
-	"github.com/Worldcoin/hubble-commander/api"
 	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/client"
 	"github.com/Worldcoin/hubble-commander/models"
-	"github.com/Worldcoin/hubble-commander/models/dto"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	"github.com/ybbus/jsonrpc/v2"
 )
 
 func randomPublicKey() *models.PublicKey {
@@ -28,45 +26,6 @@ func randomPublicKey() *models.PublicKey {
 	return wallet.PublicKey()
 }
 
-func sendC2T(client jsonrpc.RPCClient, wallet *bls.Wallet, from uint32, nonce models.Uint256) string {
-	toPublicKey := randomPublicKey()
-	amount := models.MakeUint256(1)
-	fee := models.MakeUint256(1)
-
-	transfer := dto.Create2Transfer{
-		FromStateID: &from,
-		ToPublicKey: toPublicKey,
-		Amount:      &amount,
-		Nonce:       &nonce,
-		Fee:         &fee,
-		Signature:   nil,
-	}
-
-	create2Transfer, err := api.SignCreate2Transfer(wallet, transfer)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	resp, err := client.Call(
-		"hubble_sendTransaction",
-		[]dto.Create2Transfer{*create2Transfer},
-	)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if resp.Error != nil {
-		log.Fatal(resp.Error)
-	}
-
-	txHash, err := resp.GetString()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return txHash
-}
-
 // runs commands against a running hubble instance
 // e2e/bench/bench_transactions_test.go
 func benchmarkHubble(ctx *cli.Context) error {
@@ -79,55 +38,20 @@ func benchmarkHubble(ctx *cli.Context) error {
 	// stateID
 	fromStateID := uint32(6)
 
-	rpcURL := "http://localhost:8080"
-	client := jsonrpc.NewClient(rpcURL)
-
-	networkInfo := getNetworkInfo(client)
-	chainIDAsString := networkInfo.ChainID.String()
-
-	log.Infof(
-		"Connected to remote hubble. ChainID=%s BlockNumber=%d",
-		chainIDAsString,
-		networkInfo.BlockNumber,
-	)
-
-	// check that we have the correct private key for this state
-
-	userState := getUserState(client, fromStateID)
-	log.Infof(
-		"Found sender account. ID=%d Token=%s Balance=%s Nonce=%s",
-		fromStateID,
-		userState.TokenID.String(),
-		userState.Balance.String(),
-		userState.Nonce.String(),
-	)
+	hubble := client.NewClient("http://localhost:8080", "")
+	account := openAccount(hubble, fromStateID, privateKey)
 
 	// TODO: check that the balance is sufficient to create our transactions
 
-	wallet, err := bls.NewWallet(privateKey, networkInfo.SignatureDomain)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	serverPublicKey := getPublicKey(client, fromStateID)
-	walletPublicKey := wallet.PublicKey()
-	if serverPublicKey.String() != walletPublicKey.String() {
-		log.Error("derived public key: ", walletPublicKey)
-		log.Error("expected public key: ", serverPublicKey)
-		log.Fatal("cannot run benchmark, provided private key is incorrect")
-	}
-
-	nonce := &userState.Nonce
-
 	ticker := time.NewTicker(time.Second)
 	for range ticker.C {
-		txHash := sendC2T(client, wallet, fromStateID, *nonce)
+		txHash, err := account.SendCreate2Transfer(randomPublicKey(), models.MakeUint256(1), models.MakeUint256(1))
+		if err != nil {
+			log.Fatal(err)
+		}
 		log.Infof(
-			"Sent C2T txHash=%s", txHash,
+			"Sent C2T txHash=%s", txHash.String(),
 		)
-
-		one := models.MakeUint256(1)
-		nonce = nonce.Add(&one)
 	}
 
 	return nil
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var errInvalidConfig = fmt.Errorf("invalid config")
+
+func configCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "check and print the commander config",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "validate",
+				Usage:  "report unknown keys, values of the wrong type and inconsistent values of the config",
+				Action: validateConfig,
+			},
+			{
+				Name:   "show",
+				Usage:  "print the effective config, merged from defaults, the config file and env vars, with secrets redacted",
+				Action: showConfig,
+			},
+		},
+	}
+}
+
+func validateConfig(_ *cli.Context) error {
+	problems := config.ValidateConfig()
+	if len(problems) == 0 {
+		fmt.Println("Config is valid")
+		return nil
+	}
+
+	for i := range problems {
+		fmt.Println(problems[i].Error())
+	}
+	return errors.WithMessagef(errInvalidConfig, "found %d problems", len(problems))
+}
+
+func showConfig(_ *cli.Context) error {
+	cfg := config.GetConfig()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	return errors.WithStack(encoder.Encode(config.Redact(cfg)))
+}
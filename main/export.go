@@ -3,11 +3,13 @@ package main
 import (
 	"fmt"
 
+	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/scripts"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
 	"github.com/urfave/cli/v2"
 )
 
-var exportTypes = []string{"state", "accounts"}
+var exportTypes = []string{"state", "accounts", "snapshot", "batches", "commitments", "transactions", "deposits", "tokens"}
 
 func exportData(ctx *cli.Context) error {
 	file := ctx.String("file")
@@ -18,8 +20,44 @@ func exportData(ctx *cli.Context) error {
 		err = scripts.ExportStateLeaves(file)
 	case exportTypes[1]:
 		err = scripts.ExportAccounts(file)
+	case exportTypes[2]:
+		err = scripts.ExportSnapshot(file)
+	case exportTypes[3]:
+		err = scripts.ExportBatches(file, exportOptions(ctx))
+	case exportTypes[4]:
+		err = scripts.ExportCommitments(file, exportOptions(ctx))
+	case exportTypes[5]:
+		err = scripts.ExportTransactions(file, exportOptions(ctx))
+	case exportTypes[6]:
+		err = scripts.ExportDeposits(file, exportOptions(ctx))
+	case exportTypes[7]:
+		err = scripts.ExportTokens(file, exportOptions(ctx))
 	default:
 		return fmt.Errorf("invalid export data type, supported: %v", exportTypes)
 	}
 	return err
 }
+
+func exportOptions(ctx *cli.Context) *scripts.ExportOptions {
+	opts := &scripts.ExportOptions{
+		Format:      ctx.String("format"),
+		Incremental: ctx.Bool("incremental"),
+		BackupFiles: ctx.StringSlice("backup"),
+	}
+	if ctx.IsSet("from-batch") {
+		opts.FromBatch = models.NewUint256(ctx.Uint64("from-batch"))
+	}
+	if ctx.IsSet("to-batch") {
+		opts.ToBatch = models.NewUint256(ctx.Uint64("to-batch"))
+	}
+	if ctx.IsSet("since-batch") {
+		opts.SinceBatch = models.NewUint256(ctx.Uint64("since-batch"))
+	}
+	if ctx.IsSet("from-block") {
+		opts.FromBlock = ref.Uint32(uint32(ctx.Uint("from-block")))
+	}
+	if ctx.IsSet("to-block") {
+		opts.ToBlock = ref.Uint32(uint32(ctx.Uint("to-block")))
+	}
+	return opts
+}
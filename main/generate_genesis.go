@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/Worldcoin/hubble-commander/scripts"
+	"github.com/urfave/cli/v2"
+)
+
+func generateGenesis(ctx *cli.Context) error {
+	return scripts.GenerateGenesis(ctx.String("state"), ctx.String("accounts"), ctx.String("file"), ctx.Bool("merge"))
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/encoder"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/consts"
+	"github.com/Worldcoin/hubble-commander/utils/merkletree"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	errMissingBatch         = fmt.Errorf("set either --id or --tx-hash")
+	errNotBatchSubmission   = fmt.Errorf("transaction is not a direct batch submission to the rollup contract")
+	errRollupEventNotFound  = fmt.Errorf("transaction did not emit the event of a batch submission")
+	errBatchHashMismatch    = fmt.Errorf("computed commitment root does not match the batch hash on chain")
+	errInvalidInspectFormat = fmt.Errorf("--format must be either table or json")
+)
+
+type inspectedBatch struct {
+	ID              models.Uint256
+	Type            batchtype.BatchType
+	TransactionHash common.Hash
+	BlockNumber     uint64
+	AccountRoot     common.Hash
+	// nil when the batch was rolled back, its ID may have been reused by a later submission
+	Hash         *common.Hash
+	ComputedHash common.Hash
+	Commitments  []inspectedCommitment
+	Deposits     *inspectedDeposits `json:",omitempty"`
+}
+
+type inspectedCommitment struct {
+	Index        uint8
+	StateRoot    common.Hash
+	BodyHash     common.Hash
+	LeafHash     common.Hash
+	FeeReceiver  *uint32                   `json:",omitempty"`
+	Signature    *models.Signature         `json:",omitempty"`
+	Meta         *models.MassMigrationMeta `json:",omitempty"`
+	WithdrawRoot *common.Hash              `json:",omitempty"`
+	Transactions []inspectedTx
+}
+
+type inspectedTx struct {
+	FromStateID uint32
+	ToStateID   *uint32 `json:",omitempty"`
+	ToPubKeyID  *uint32 `json:",omitempty"`
+	Amount      models.Uint256
+	Fee         models.Uint256
+}
+
+type inspectedDeposits struct {
+	PrevStateRoot common.Hash
+	SubtreeID     models.Uint256
+	SubtreeRoot   common.Hash
+	PathAtDepth   uint32
+}
+
+func inspectBatchCommand() *cli.Command {
+	return &cli.Command{
+		Name: "inspectBatch",
+		Usage: "decode the calldata of a batch submission and check the commitment root against the batch hash " +
+			"on chain, uses the ethereum node of the config",
+		Flags: []cli.Flag{
+			chainSpecFlag(),
+			&cli.Uint64Flag{
+				Name:  "id",
+				Usage: "ID of the batch, its L1 transaction is looked up in the commander",
+			},
+			&cli.StringFlag{
+				Name:  "tx-hash",
+				Usage: "hash of the L1 transaction which submitted the batch",
+			},
+			&cli.StringFlag{
+				Name:  "rpcurl",
+				Usage: "location of the hubble commander, used with --id",
+				Value: "http://localhost:8080",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "either table or json",
+				Value: "table",
+			},
+		},
+		Action: inspectBatch,
+	}
+}
+
+func inspectBatch(ctx *cli.Context) error {
+	format := ctx.String("format")
+	if format != "table" && format != "json" {
+		return errInvalidInspectFormat
+	}
+
+	txHash, err := getBatchTxHash(ctx)
+	if err != nil {
+		return err
+	}
+	ethClient, err := newL1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	batch, err := decodeBatchSubmission(ethClient, *txHash)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		err = printJSON(batch)
+	} else {
+		err = printInspectedBatch(batch)
+	}
+	if err != nil {
+		return err
+	}
+
+	if batch.Hash != nil && *batch.Hash != batch.ComputedHash {
+		return errors.WithStack(errBatchHashMismatch)
+	}
+	return nil
+}
+
+func getBatchTxHash(ctx *cli.Context) (*common.Hash, error) {
+	if ctx.IsSet("tx-hash") {
+		return ref.Hash(common.HexToHash(ctx.String("tx-hash"))), nil
+	}
+	if !ctx.IsSet("id") {
+		return nil, errMissingBatch
+	}
+
+	batch, err := client.NewClient(ctx.String("rpcurl"), "").GetBatchByID(models.MakeUint256(ctx.Uint64("id")))
+	if err != nil {
+		return nil, err
+	}
+	return &batch.TransactionHash, nil
+}
+
+func decodeBatchSubmission(ethClient *eth.Client, txHash common.Hash) (*inspectedBatch, error) {
+	backend := ethClient.Blockchain.GetBackend()
+	tx, _, err := backend.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	receipt, err := backend.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	newBatchLog, err := findRollupLog(ethClient, receipt, eth.NewBatchEvent)
+	if err != nil {
+		return nil, err
+	}
+	newBatch, err := ethClient.Rollup.ParseNewBatch(*newBatchLog)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	batch := &inspectedBatch{
+		ID:              models.MakeUint256FromBig(*newBatch.BatchID),
+		Type:            batchtype.BatchType(newBatch.BatchType),
+		TransactionHash: txHash,
+		BlockNumber:     receipt.BlockNumber.Uint64(),
+		AccountRoot:     newBatch.AccountRoot,
+	}
+
+	contractBatch, err := ethClient.GetContractBatch(&batch.ID)
+	if err != nil && err.Error() != eth.MsgInvalidBatchID {
+		return nil, errors.WithStack(err)
+	}
+	if contractBatch != nil {
+		var isSameSubmission bool
+		isSameSubmission, err = isSubmittedInBlock(ethClient, contractBatch, batch.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if isSameSubmission {
+			batch.Hash = &contractBatch.Hash
+		}
+	}
+
+	rollupABI := ethClient.Rollup.ABI
+	methodID := tx.Data()[:4]
+	switch {
+	case bytes.Equal(methodID, rollupABI.Methods["submitTransfer"].ID),
+		bytes.Equal(methodID, rollupABI.Methods["submitCreate2Transfer"].ID):
+		var commitments []encoder.DecodedCommitment
+		commitments, err = encoder.DecodeTransferBatchCalldata(rollupABI, tx.Data())
+		if err != nil {
+			return nil, err
+		}
+		err = batch.setTxCommitments(encoder.DecodedCommitmentsToCommitments(commitments...))
+	case bytes.Equal(methodID, rollupABI.Methods["submitMassMigration"].ID):
+		var commitments []encoder.DecodedMMCommitment
+		commitments, err = encoder.DecodeMMBatchCalldata(rollupABI, tx.Data())
+		if err != nil {
+			return nil, err
+		}
+		err = batch.setTxCommitments(encoder.DecodedMMCommitmentsToCommitments(commitments...))
+	case bytes.Equal(methodID, rollupABI.Methods["submitDeposits"].ID):
+		err = batch.setDepositCommitment(ethClient, receipt, tx.Data())
+	default:
+		return nil, errors.WithStack(errNotBatchSubmission)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// isSubmittedInBlock tells whether the batch stored on chain was submitted in the block, the ID
+// of a rolled back batch is reused by the next batch submission
+func isSubmittedInBlock(ethClient *eth.Client, contractBatch *eth.ContractBatch, blockNumber uint64) (bool, error) {
+	blocksToFinalise, err := ethClient.GetBlocksToFinalise()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return uint64(contractBatch.FinaliseOn) == blockNumber+uint64(*blocksToFinalise), nil
+}
+
+func findRollupLog(ethClient *eth.Client, receipt *types.Receipt, eventName string) (*types.Log, error) {
+	for _, log := range receipt.Logs {
+		if len(log.Topics) > 0 && log.Topics[0] == ethClient.Rollup.ABI.Events[eventName].ID {
+			return log, nil
+		}
+	}
+	return nil, errors.WithMessage(errRollupEventNotFound, eventName)
+}
+
+func (b *inspectedBatch) setTxCommitments(commitments []encoder.Commitment) error {
+	leafHashes := make([]common.Hash, 0, len(commitments))
+	b.Commitments = make([]inspectedCommitment, 0, len(commitments))
+	for i := range commitments {
+		decoded := commitments[i].ToDecodedCommitment()
+		commitment := inspectedCommitment{
+			Index:       decoded.ID.IndexInBatch,
+			StateRoot:   decoded.StateRoot,
+			BodyHash:    *commitments[i].BodyHash(b.AccountRoot),
+			LeafHash:    commitments[i].LeafHash(b.AccountRoot),
+			FeeReceiver: &decoded.FeeReceiver,
+			Signature:   &decoded.CombinedSignature,
+		}
+		if mmCommitment, ok := commitments[i].(*encoder.DecodedMMCommitment); ok {
+			commitment.Meta = mmCommitment.Meta
+			commitment.WithdrawRoot = &mmCommitment.WithdrawRoot
+		}
+
+		txs, err := decodeCommitmentTxs(b.Type, decoded.Transactions)
+		if err != nil {
+			return err
+		}
+		commitment.Transactions = txs
+
+		b.Commitments = append(b.Commitments, commitment)
+		leafHashes = append(leafHashes, commitment.LeafHash)
+	}
+	return b.setComputedHash(leafHashes)
+}
+
+// setDepositCommitment computes the state root after the deposits from the subtree root of the DepositsFinalised
+// event and the vacancy proof in the calldata like the rollup contract does
+func (b *inspectedBatch) setDepositCommitment(ethClient *eth.Client, receipt *types.Receipt, calldata []byte) error {
+	decoded, err := encoder.DecodeDepositBatchCalldata(ethClient.Rollup.ABI, calldata)
+	if err != nil {
+		return err
+	}
+	depositsFinalisedLog, err := findRollupLog(ethClient, receipt, eth.DepositsFinalisedEvent)
+	if err != nil {
+		return err
+	}
+	depositsFinalised, err := ethClient.Rollup.ParseDepositsFinalised(*depositsFinalisedLog)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	b.Deposits = &inspectedDeposits{
+		PrevStateRoot: decoded.Previous.StateRoot,
+		SubtreeID:     models.MakeUint256FromBig(*depositsFinalised.SubtreeID),
+		SubtreeRoot:   depositsFinalised.DepositSubTreeRoot,
+		PathAtDepth:   decoded.VacancyProof.PathAtDepth,
+	}
+
+	stateRoot := merkletree.ComputeRoot(b.Deposits.SubtreeRoot, b.Deposits.PathAtDepth, decoded.VacancyProof.Witness)
+
+	commitment := inspectedCommitment{
+		StateRoot:    stateRoot,
+		BodyHash:     consts.ZeroHash,
+		LeafHash:     utils.HashTwo(stateRoot, consts.ZeroHash),
+		Transactions: []inspectedTx{},
+	}
+	b.Commitments = []inspectedCommitment{commitment}
+	return b.setComputedHash([]common.Hash{commitment.LeafHash})
+}
+
+func (b *inspectedBatch) setComputedHash(leafHashes []common.Hash) error {
+	tree, err := merkletree.NewMerkleTree(leafHashes)
+	if err != nil {
+		return err
+	}
+	b.ComputedHash = tree.Root()
+	return nil
+}
+
+func decodeCommitmentTxs(batchType batchtype.BatchType, data []byte) ([]inspectedTx, error) {
+	switch batchType {
+	case batchtype.Transfer:
+		transfers, err := encoder.DeserializeTransfers(data)
+		if err != nil {
+			return nil, err
+		}
+		txs := make([]inspectedTx, 0, len(transfers))
+		for i := range transfers {
+			txs = append(txs, makeInspectedTx(&transfers[i].TransactionBase, &transfers[i].ToStateID, nil))
+		}
+		return txs, nil
+	case batchtype.Create2Transfer:
+		transfers, pubKeyIDs, err := encoder.DeserializeCreate2Transfers(data)
+		if err != nil {
+			return nil, err
+		}
+		txs := make([]inspectedTx, 0, len(transfers))
+		for i := range transfers {
+			txs = append(txs, makeInspectedTx(&transfers[i].TransactionBase, transfers[i].ToStateID, &pubKeyIDs[i]))
+		}
+		return txs, nil
+	case batchtype.MassMigration:
+		massMigrations, err := encoder.DeserializeMassMigrations(data)
+		if err != nil {
+			return nil, err
+		}
+		txs := make([]inspectedTx, 0, len(massMigrations))
+		for i := range massMigrations {
+			txs = append(txs, makeInspectedTx(&massMigrations[i].TransactionBase, nil, nil))
+		}
+		return txs, nil
+	case batchtype.Genesis, batchtype.Deposit:
+	}
+	return nil, errors.Errorf("%s batches have no transactions in their calldata", batchType.String())
+}
+
+func makeInspectedTx(tx *models.TransactionBase, toStateID, toPubKeyID *uint32) inspectedTx {
+	return inspectedTx{
+		FromStateID: tx.FromStateID,
+		ToStateID:   toStateID,
+		ToPubKeyID:  toPubKeyID,
+		Amount:      tx.Amount,
+		Fee:         tx.Fee,
+	}
+}
+
+func printInspectedBatch(batch *inspectedBatch) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Batch\t#%s %s\n", batch.ID.String(), batch.Type.String())
+	fmt.Fprintf(writer, "Transaction\t%s (block %d)\n", batch.TransactionHash.String(), batch.BlockNumber)
+	fmt.Fprintf(writer, "Account root\t%s\n", batch.AccountRoot.String())
+	if batch.Hash == nil {
+		fmt.Fprintf(writer, "Batch hash\trolled back\n")
+	} else {
+		fmt.Fprintf(writer, "Batch hash\t%s\n", batch.Hash.String())
+	}
+	fmt.Fprintf(writer, "Computed hash\t%s %s\n", batch.ComputedHash.String(), hashCheck(batch.Hash, batch.ComputedHash))
+	if batch.Deposits != nil {
+		fmt.Fprintf(writer, "Previous state root\t%s\n", batch.Deposits.PrevStateRoot.String())
+		fmt.Fprintf(writer, "Subtree\t#%s %s\n", batch.Deposits.SubtreeID.String(), batch.Deposits.SubtreeRoot.String())
+		fmt.Fprintf(writer, "Path at depth\t%d\n", batch.Deposits.PathAtDepth)
+	}
+	err := writer.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for i := range batch.Commitments {
+		err = printInspectedCommitment(&batch.Commitments[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printInspectedCommitment(commitment *inspectedCommitment) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "\nCommitment\t#%d\n", commitment.Index)
+	fmt.Fprintf(writer, "State root\t%s\n", commitment.StateRoot.String())
+	fmt.Fprintf(writer, "Body hash\t%s\n", commitment.BodyHash.String())
+	fmt.Fprintf(writer, "Leaf hash\t%s\n", commitment.LeafHash.String())
+	if commitment.FeeReceiver != nil {
+		fmt.Fprintf(writer, "Fee receiver\t%d\n", *commitment.FeeReceiver)
+	}
+	if commitment.Meta != nil {
+		fmt.Fprintf(
+			writer,
+			"Mass migration\tspoke %d, token %s, amount %s\n",
+			commitment.Meta.SpokeID,
+			commitment.Meta.TokenID.String(),
+			commitment.Meta.Amount.String(),
+		)
+		fmt.Fprintf(writer, "Withdraw root\t%s\n", commitment.WithdrawRoot.String())
+	}
+	err := writer.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(commitment.Transactions) == 0 {
+		return nil
+	}
+
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "FROM\tTO\tTO PUBKEY ID\tAMOUNT\tFEE\t")
+	for i := range commitment.Transactions {
+		tx := &commitment.Transactions[i]
+		fmt.Fprintf(
+			writer,
+			"%d\t%s\t%s\t%s\t%s\t\n",
+			tx.FromStateID,
+			optionalUint32(tx.ToStateID),
+			optionalUint32(tx.ToPubKeyID),
+			tx.Amount.String(),
+			tx.Fee.String(),
+		)
+	}
+	return errors.WithStack(writer.Flush())
+}
+
+func hashCheck(batchHash *common.Hash, computedHash common.Hash) string {
+	if batchHash == nil {
+		return ""
+	}
+	if *batchHash != computedHash {
+		return "MISMATCH"
+	}
+	return "OK"
+}
+
+func optionalUint32(value *uint32) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *value)
+}
@@ -5,91 +5,56 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Worldcoin/hubble-commander/api"
 	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/client"
 	"github.com/Worldcoin/hubble-commander/models"
-	"github.com/Worldcoin/hubble-commander/models/dto"
 	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	"github.com/ybbus/jsonrpc/v2"
 )
 
-func getNetworkInfo(client jsonrpc.RPCClient) *dto.NetworkInfo {
-	resp, err := client.Call("hubble_getNetworkInfo")
+// openAccount connects to the commander and checks that the private key owns the state
+func openAccount(hubble *client.Client, stateID uint32, privateKey []byte) *client.Account {
+	networkInfo, err := hubble.GetNetworkInfo()
 	if err != nil {
 		log.Fatal(err)
 	}
+	log.Infof(
+		"Connected to remote hubble. ChainID=%s BlockNumber=%d",
+		networkInfo.ChainID.String(),
+		networkInfo.BlockNumber,
+	)
 
-	if resp.Error != nil {
-		log.Fatal(resp.Error)
-	}
-
-	var networkInfo dto.NetworkInfo
-	err = resp.GetObject(&networkInfo)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return &networkInfo
-}
-
-func getUserState(client jsonrpc.RPCClient, treeIndex uint32) *dto.UserStateWithID {
-	resp, err := client.Call("hubble_getUserState", treeIndex)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	if resp.Error != nil {
-		log.Fatal(resp.Error)
-	}
-
-	var userState dto.UserStateWithID
-	err = resp.GetObject(&userState)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return &userState
-}
-
-func getUserStates(client jsonrpc.RPCClient, pubkey *models.PublicKey) []dto.UserStateWithID {
-	params := []models.PublicKey{*pubkey} // wrapping in an array tells the jsonrpc library not to perform any magic
-	resp, err := client.Call("hubble_getUserStates", params)
+	userState, err := hubble.GetUserState(stateID)
 	if err != nil {
 		log.Fatal(err)
 	}
+	log.Infof(
+		"Found sender account. ID=%d Token=%s Balance=%s Nonce=%s",
+		stateID,
+		userState.TokenID.String(),
+		userState.Balance.String(),
+		userState.Nonce.String(),
+	)
 
-	if resp.Error != nil {
-		log.Fatal(resp.Error)
-	}
-
-	result := make([]dto.UserStateWithID, 0)
-	err = resp.GetObject(&result)
+	wallet, err := bls.NewWallet(privateKey, networkInfo.SignatureDomain)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	return result
-}
-
-func getPublicKey(client jsonrpc.RPCClient, treeIndex uint32) *models.PublicKey {
-	resp, err := client.Call("hubble_getPublicKeyByStateID", treeIndex)
+	serverPublicKey, err := hubble.GetPublicKeyByStateID(stateID)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	if resp.Error != nil {
-		log.Fatal(resp.Error)
-	}
-
-	var publicKey models.PublicKey
-	err = resp.GetObject(&publicKey)
-	if err != nil {
-		log.Fatal(err)
+	walletPublicKey := wallet.PublicKey()
+	if serverPublicKey.String() != walletPublicKey.String() {
+		log.Error("derived public key: ", walletPublicKey)
+		log.Error("expected public key: ", serverPublicKey)
+		log.Fatal("provided private key does not own the sender state")
 	}
 
-	return &publicKey
+	return hubble.NewAccount(wallet, stateID)
 }
 
 func getToStateID(ctx *cli.Context) uint32 {
@@ -159,7 +124,6 @@ func getTxType(ctx *cli.Context) txtype.TransactionType {
 	return 0
 }
 
-//nolint:funlen
 func sendTransaction(ctx *cli.Context) error {
 	txType := getTxType(ctx)
 
@@ -177,106 +141,27 @@ func sendTransaction(ctx *cli.Context) error {
 		panic("unreachable")
 	}
 
-	client := jsonrpc.NewClient(ctx.String("rpcurl"))
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	account := openAccount(hubble, uint32(ctx.Int("from")), decodeHexString(ctx.String("privateKey")))
 
-	networkInfo := getNetworkInfo(client)
-	chainIDAsString := networkInfo.ChainID.String()
-
-	log.Infof(
-		"Connected to remote hubble. ChainID=%s BlockNumber=%d",
-		chainIDAsString,
-		networkInfo.BlockNumber,
-	)
-
-	var fromStateID uint32 = uint32(ctx.Int("from"))
-
-	userState := getUserState(client, fromStateID)
-	log.Infof(
-		"Found sender account. ID=%d Token=%s Balance=%s Nonce=%s",
-		fromStateID,
-		userState.TokenID.String(),
-		userState.Balance.String(),
-		userState.Nonce.String(),
-	)
-
-	var amount models.Uint256 = models.MakeUint256(ctx.Uint64("amount"))
-	var fee models.Uint256 = models.MakeUint256(ctx.Uint64("fee"))
-
-	privateKeyAsString := ctx.String("privateKey")
-	privateKey := decodeHexString(privateKeyAsString)
-
-	wallet, err := bls.NewWallet(privateKey, networkInfo.SignatureDomain)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	serverPublicKey := getPublicKey(client, fromStateID)
-	walletPublicKey := wallet.PublicKey()
-	if serverPublicKey.String() != walletPublicKey.String() {
-		log.Error("derived public key: ", walletPublicKey)
-		log.Error("expected public key: ", serverPublicKey)
-		log.Fatal("cannot sign transaction, provided private key is incorrect")
-	}
-
-	var resp *jsonrpc.RPCResponse
+	amount := models.MakeUint256(ctx.Uint64("amount"))
+	fee := models.MakeUint256(ctx.Uint64("fee"))
 
+	var txHash *common.Hash
+	var err error
 	switch txType {
 	case txtype.Transfer:
-		transfer := &dto.Transfer{
-			FromStateID: &fromStateID,
-			ToStateID:   &toStateID,
-			Amount:      &amount,
-			Nonce:       &userState.Nonce,
-			Fee:         &fee,
-			Signature:   nil,
-		}
-
-		transfer, err = api.SignTransfer(wallet, *transfer)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		resp, err = client.Call(
-			"hubble_sendTransaction",
-			[]dto.Transfer{*transfer},
-		)
-		if err != nil {
-			log.Fatal(err)
-		}
+		txHash, err = account.SendTransfer(toStateID, amount, fee)
 	case txtype.Create2Transfer:
-		create2Transfer := &dto.Create2Transfer{
-			FromStateID: &fromStateID,
-			ToPublicKey: toPublicKey,
-			Amount:      &amount,
-			Nonce:       &userState.Nonce,
-			Fee:         &fee,
-			Signature:   nil,
-		}
-
-		create2Transfer, err = api.SignCreate2Transfer(wallet, *create2Transfer)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		resp, err = client.Call(
-			"hubble_sendTransaction",
-			[]dto.Create2Transfer{*create2Transfer},
-		)
-		if err != nil {
-			log.Fatal(err)
-		}
+		txHash, err = account.SendCreate2Transfer(toPublicKey, amount, fee)
 	default:
 		panic("unreachable")
 	}
 
-	if resp.Error != nil {
-		log.Error("Error: ", resp.Error)
+	if err != nil {
+		log.Error("Error: ", err)
 	} else {
-		txHash, err := resp.GetString()
-		if err != nil {
-			log.Fatal(err)
-		}
-		log.Infof("Submitted transaction. TxHash=%s", txHash)
+		log.Infof("Submitted transaction. TxHash=%s", txHash.String())
 	}
 
 	return nil
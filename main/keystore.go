@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// read from the environment or a file to keep the password and imported keys out of the shell history
+const (
+	keystorePasswordEnv = "HUBBLE_KEYSTORE_PASSWORD"
+	privateKeyEnv       = "HUBBLE_PRIVATE_KEY"
+)
+
+const keyFileExtension = ".json"
+
+var (
+	keyNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+	errInvalidKeyName      = fmt.Errorf("key names may only contain letters, digits, '_', '.' and '-'")
+	errKeyExists           = fmt.Errorf("key with this name already exists")
+	errMissingKeyPassword  = fmt.Errorf("set the keystore password with --password-file or " + keystorePasswordEnv)
+	errMissingPrivateKey   = fmt.Errorf("pass the private key with --private-key-file, '-' for stdin, or " + privateKeyEnv)
+	errInvalidPrivateKey   = fmt.Errorf("private key must be hex-encoded")
+	errKeystoreNotReadable = fmt.Errorf("could not read the keystore")
+)
+
+// keystoreDir stores each key in <name>.json, encrypted with bls.EncryptKey
+type keystoreDir string
+
+func defaultKeystoreDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "keystore"
+	}
+	return filepath.Join(home, ".hubble", "keystore")
+}
+
+func openKeystore(ctx *cli.Context) keystoreDir {
+	return keystoreDir(ctx.String("keystore"))
+}
+
+func keystorePassword(ctx *cli.Context) (string, error) {
+	if ctx.IsSet("password-file") {
+		password, err := os.ReadFile(ctx.String("password-file"))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return strings.TrimRight(string(password), "\r\n"), nil
+	}
+
+	password, ok := os.LookupEnv(keystorePasswordEnv)
+	if !ok {
+		return "", errMissingKeyPassword
+	}
+	return password, nil
+}
+
+// importedPrivateKey reads the hex-encoded private key from the file, stdin or the environment
+func importedPrivateKey(ctx *cli.Context) ([]byte, error) {
+	var privateKey string
+	switch {
+	case ctx.String("private-key-file") == "-":
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		privateKey = string(content)
+	case ctx.IsSet("private-key-file"):
+		content, err := os.ReadFile(ctx.String("private-key-file"))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		privateKey = string(content)
+	default:
+		var ok bool
+		privateKey, ok = os.LookupEnv(privateKeyEnv)
+		if !ok {
+			return nil, errMissingPrivateKey
+		}
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(privateKey), "0x"))
+	if err != nil {
+		return nil, errInvalidPrivateKey
+	}
+	return decoded, nil
+}
+
+func (d keystoreDir) keyPath(name string) (string, error) {
+	if !keyNameRegexp.MatchString(name) {
+		return "", errInvalidKeyName
+	}
+	return filepath.Join(string(d), name+keyFileExtension), nil
+}
+
+func (d keystoreDir) saveKey(name string, wallet *bls.Wallet, password string) error {
+	path, err := d.keyPath(name)
+	if err != nil {
+		return err
+	}
+
+	keyFile, err := bls.EncryptKey(wallet, password, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return err
+	}
+	encodedKeyFile, err := json.MarshalIndent(keyFile, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = os.MkdirAll(string(d), 0700)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return errors.WithMessage(errKeyExists, name)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = file.Write(encodedKeyFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(file.Sync())
+}
+
+func (d keystoreDir) readKeyFile(name string) (*bls.KeyFile, error) {
+	path, err := d.keyPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedKeyFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var keyFile bls.KeyFile
+	err = json.Unmarshal(encodedKeyFile, &keyFile)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "key file %s", path)
+	}
+	return &keyFile, nil
+}
+
+func (d keystoreDir) loadKey(name, password string, domain bls.Domain) (*bls.Wallet, error) {
+	keyFile, err := d.readKeyFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return bls.DecryptKey(keyFile, password, domain)
+}
+
+// keyNames returns the names of the stored keys in alphabetical order
+func (d keystoreDir) keyNames() ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithMessage(errKeystoreNotReadable, err.Error())
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExtension {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), keyFileExtension))
+	}
+	sort.Strings(names)
+	return names, nil
+}
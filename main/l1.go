@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/commander"
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/contracts/erc20"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var errMissingChainSpec = fmt.Errorf("set the chain spec with --chain-spec or bootstrap.chain_spec_path of the config")
+
+// l1Flags are shared by the commands which send transactions to the contracts of the chain spec from the
+// ethereum account of the config and wait for the commander to sync them
+func l1Flags(flags ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{
+		chainSpecFlag(),
+		&cli.StringFlag{
+			Name:  "rpcurl",
+			Usage: "location of the hubble commander to wait for",
+			Value: "http://localhost:8080",
+		},
+		&cli.DurationFlag{
+			Name:  "poll-interval",
+			Usage: "how often the synced block of the commander is checked",
+			Value: 5 * time.Second,
+		},
+	}, flags...)
+}
+
+func chainSpecFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "chain-spec",
+		Usage: "chain spec with the contract addresses, bootstrap.chain_spec_path of the config when not set",
+	}
+}
+
+func depositCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "deposit",
+		Usage: "approve the deposit manager and deposit ERC20 tokens to a new state of a registered public key",
+		Flags: l1Flags(
+			&cli.UintFlag{
+				Name:     "pubkey-id",
+				Usage:    "pubKeyID of the receiver",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "token",
+				Usage: "ID of the registered token",
+				Value: "0",
+			},
+			&cli.StringFlag{
+				Name:     "amount",
+				Usage:    "how many tokens to deposit in the smallest unit of the ERC20 token",
+				Required: true,
+			},
+		),
+		Action: deposit,
+	}
+}
+
+func registerTokenCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "registerToken",
+		Usage: "register an ERC20 token in the token registry",
+		Flags: l1Flags(&cli.StringFlag{
+			Name:     "address",
+			Usage:    "address of the ERC20 token contract",
+			Required: true,
+		}),
+		Action: registerToken,
+	}
+}
+
+func registerSpokeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "registerSpoke",
+		Usage: "register a spoke for mass migrations in the spoke registry",
+		Flags: l1Flags(&cli.StringFlag{
+			Name:     "address",
+			Usage:    "address of the spoke contract",
+			Required: true,
+		}),
+		Action: registerSpoke,
+	}
+}
+
+func deposit(ctx *cli.Context) error {
+	amount, err := parseUint256Flag(ctx, "amount")
+	if err != nil {
+		return err
+	}
+	tokenID, err := parseUint256Flag(ctx, "token")
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := newL1Client(ctx)
+	if err != nil {
+		return err
+	}
+	token, err := ethClient.GetRegisteredToken(tokenID)
+	if err != nil {
+		return err
+	}
+	err = approveDepositManager(ethClient, token.Contract, amount)
+	if err != nil {
+		return err
+	}
+
+	depositID, l2Amount, err := ethClient.QueueDepositAndWait(models.NewUint256(uint64(ctx.Uint("pubkey-id"))), amount, tokenID)
+	if err != nil {
+		return err
+	}
+	log.Infof(
+		"Queued deposit %s/%s of %s tokens to pubKeyID %d",
+		depositID.SubtreeID.String(),
+		depositID.DepositIndex.String(),
+		l2Amount.String(),
+		ctx.Uint("pubkey-id"),
+	)
+
+	return waitForCommanderSync(ctx, ethClient)
+}
+
+// approveDepositManager allows the deposit manager to transfer the amount unless it is allowed already
+func approveDepositManager(ethClient *eth.Client, tokenAddress common.Address, amount *models.Uint256) error {
+	token, err := erc20.NewERC20(tokenAddress, ethClient.Blockchain.GetBackend())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	account := ethClient.Blockchain.GetAccount()
+	allowance, err := token.Allowance(&bind.CallOpts{}, account.From, ethClient.ChainState.DepositManager)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if allowance.Cmp(amount.ToBig()) >= 0 {
+		return nil
+	}
+
+	tx, err := token.Approve(account, ethClient.ChainState.DepositManager, amount.ToBig())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = ethClient.WaitToBeMined(tx)
+	if err != nil {
+		return err
+	}
+	log.Infof("Approved the deposit manager to transfer %s tokens of %s", amount.String(), tokenAddress.String())
+	return nil
+}
+
+func registerToken(ctx *cli.Context) error {
+	ethClient, err := newL1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	tokenID, err := ethClient.RegisterTokenAndWait(common.HexToAddress(ctx.String("address")))
+	if err != nil {
+		return err
+	}
+	log.Infof("Registered token %s with tokenID %s", ctx.String("address"), tokenID.String())
+
+	return waitForCommanderSync(ctx, ethClient)
+}
+
+func registerSpoke(ctx *cli.Context) error {
+	ethClient, err := newL1Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	spokeID, err := ethClient.RegisterSpokeAndWait(common.HexToAddress(ctx.String("address")))
+	if err != nil {
+		return err
+	}
+	log.Infof("Registered spoke %s with spokeID %s", ctx.String("address"), spokeID.String())
+
+	return waitForCommanderSync(ctx, ethClient)
+}
+
+func newL1Client(ctx *cli.Context) (*eth.Client, error) {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+
+	chainSpecPath := ctx.String("chain-spec")
+	if !ctx.IsSet("chain-spec") && cfg.Bootstrap.ChainSpecPath != nil {
+		chainSpecPath = *cfg.Bootstrap.ChainSpecPath
+	}
+	if chainSpecPath == "" {
+		return nil, errors.WithStack(errMissingChainSpec)
+	}
+	chainSpec, err := commander.ReadChainSpecFile(chainSpecPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	blockchain, err := commander.GetChainConnection(cfg.Ethereum)
+	if err != nil {
+		return nil, err
+	}
+	return commander.NewClientFromChainSpec(blockchain, chainSpec, cfg.Ethereum)
+}
+
+// waitForCommanderSync waits until the commander synced the events of the latest block, which includes
+// the mined transaction
+func waitForCommanderSync(ctx *cli.Context, ethClient *eth.Client) error {
+	latestBlock, err := ethClient.Blockchain.GetLatestBlockNumber()
+	if err != nil {
+		return err
+	}
+
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	for {
+		networkInfo, err := hubble.GetNetworkInfo()
+		if err != nil {
+			return err
+		}
+		if networkInfo.SyncedBlock >= *latestBlock {
+			log.Infof("Commander synced block %d", networkInfo.SyncedBlock)
+			return nil
+		}
+		log.Infof("Waiting for the commander to sync block %d, synced block %d", *latestBlock, networkInfo.SyncedBlock)
+		time.Sleep(ctx.Duration("poll-interval"))
+	}
+}
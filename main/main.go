@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/Worldcoin/hubble-commander/bls"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -17,6 +18,20 @@ func main() {
 				Name:   "start",
 				Usage:  "start the commander",
 				Action: startCommander,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "from-snapshot",
+						Usage: "snapshot file to import when starting with an empty database",
+					},
+					&cli.BoolFlag{
+						Name:  "api-only",
+						Usage: "only serve the hubble API, requires --replica-of",
+					},
+					&cli.StringFlag{
+						Name:  "replica-of",
+						Usage: "http(s) URL of the primary commander to replicate the database of, requires --api-only",
+					},
+				},
 			},
 			{
 				Name:   "auditDatabase",
@@ -24,8 +39,68 @@ func main() {
 				Action: auditDatabase,
 			},
 			{
-				Name:   "newWallet",
-				Usage:  "create a new BLS wallet",
+				Name:  "verifyDatabase",
+				Usage: "check the integrity of the database, the commander must be stopped",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "repair",
+						Usage: "rewrite the pending state and indexes which do not match the recomputed ones",
+					},
+				},
+				Action: verifyDatabase,
+			},
+			{
+				Name:  "migrateDatabase",
+				Usage: "run pending schema migrations of the database, the commander must be stopped",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "only list the migrations which would run",
+					},
+				},
+				Action: migrateDatabase,
+			},
+			{
+				Name:  "generateGenesis",
+				Usage: "generate a genesis file from exported state leaves and accounts",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "state",
+						Usage:    "state leaves exported with `export -type state`",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "accounts",
+						Usage:    "accounts exported with `export -type accounts`",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "file",
+						Usage: "target file to save the genesis accounts to",
+						Value: "genesis.yaml",
+					},
+					&cli.BoolFlag{
+						Name:  "merge",
+						Usage: "merge states of the same public key and token into one genesis account",
+					},
+				},
+				Action: generateGenesis,
+			},
+			{
+				Name:  "newWallet",
+				Usage: "create a new BLS wallet",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name: "mnemonic",
+						Usage: "derive the wallet from a BIP-39 mnemonic, a new one is generated unless " + mnemonicEnv +
+							" is set, with an optional " + mnemonicPassphraseEnv,
+					},
+					&cli.StringFlag{
+						Name:  "path",
+						Usage: "derivation path of the wallet key, used with --mnemonic",
+						Value: bls.DefaultDerivationPath,
+					},
+				},
 				Action: newWallet,
 			},
 			{
@@ -71,6 +146,14 @@ func main() {
 					},
 				},
 			},
+			walletCommand(),
+			depositCommand(),
+			registerTokenCommand(),
+			registerSpokeCommand(),
+			withdrawCommand(),
+			inspectBatchCommand(),
+			statusCommand(),
+			configCommand(),
 			{
 				Name:   "benchmark",
 				Usage:  "run transactions against a commander",
@@ -90,11 +173,11 @@ func main() {
 			},
 			{
 				Name:  "export",
-				Usage: "export data to file in json format",
+				Usage: "export data to file in json, jsonl or csv format",
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:     "type",
-						Usage:    "type of data to export",
+						Usage:    "type of data to export: state, accounts, snapshot, batches, commitments, transactions, deposits or tokens",
 						Required: true,
 					},
 					&cli.StringFlag{
@@ -102,9 +185,82 @@ func main() {
 						Usage: "target file to save exported data to",
 						Value: "exported-data.json",
 					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "format of batches, commitments, transactions, deposits and tokens exports, jsonl or csv",
+						Value: "jsonl",
+					},
+					&cli.Uint64Flag{
+						Name:  "from-batch",
+						Usage: "first batch ID to export",
+					},
+					&cli.Uint64Flag{
+						Name:  "to-batch",
+						Usage: "last batch ID to export",
+					},
+					&cli.UintFlag{
+						Name:  "from-block",
+						Usage: "only export batches finalised at or after this block",
+					},
+					&cli.UintFlag{
+						Name:  "to-block",
+						Usage: "only export batches finalised at or before this block",
+					},
+					&cli.Uint64Flag{
+						Name:  "since-batch",
+						Usage: "append finalised batches after this batch ID to the file, for incremental exports",
+					},
+					&cli.BoolFlag{
+						Name:  "incremental",
+						Usage: "append finalised batches after the last batch exported to the file, which is kept in <file>.last-batch",
+					},
+					&cli.StringSliceFlag{
+						Name:  "backup",
+						Usage: "export batch data from backup files, a full backup followed by incremental ones, instead of badger.path",
+					},
 				},
 				Action: exportData,
 			},
+			{
+				Name:  "backup",
+				Usage: "create a backup of the database of a running commander",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "rpcurl",
+						Usage: "location of the hubble commander",
+						Value: "http://localhost:8080",
+					},
+					&cli.StringFlag{
+						Name:     "authKey",
+						Aliases:  []string{"authkey"},
+						Usage:    "authentication key of the admin API",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "file",
+						Usage:    "name of the backup file to create in backup.dir on the commander host",
+						Required: true,
+					},
+					&cli.Uint64Flag{
+						Name:  "since",
+						Usage: "version returned by the previous backup, creates an incremental backup",
+						Value: 0,
+					},
+				},
+				Action: backupDatabase,
+			},
+			{
+				Name:  "restore",
+				Usage: "restore the database from backup files, the commander must be stopped",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:     "file",
+						Usage:    "backup files to load, a full backup followed by incremental ones in order",
+						Required: true,
+					},
+				},
+				Action: restoreDatabase,
+			},
 		},
 	}
 
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/Worldcoin/hubble-commander/scripts"
+	"github.com/urfave/cli/v2"
+)
+
+func migrateDatabase(ctx *cli.Context) error {
+	return scripts.MigrateDatabase(ctx.Bool("dry-run"))
+}
@@ -1,18 +1,42 @@
 package main
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/Worldcoin/hubble-commander/commander"
 	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
-func startCommander(_ *cli.Context) error {
+var (
+	errReplicaRequiresAPIOnly = fmt.Errorf("--api-only and --replica-of must be used together")
+	errReplicaRequiresURL     = fmt.Errorf(
+		"--replica-of must be the http(s) URL of the primary commander, its database directory is locked while it runs",
+	)
+)
+
+func startCommander(ctx *cli.Context) error {
+	if ctx.Bool("api-only") != ctx.IsSet("replica-of") {
+		return errReplicaRequiresAPIOnly
+	}
+
 	cfg := config.GetCommanderConfigAndSetupLogger()
+	if ctx.IsSet("from-snapshot") {
+		cfg.Bootstrap.SnapshotPath = ref.String(ctx.String("from-snapshot"))
+	}
+	if ctx.IsSet("replica-of") {
+		err := validatePrimaryURL(ctx.String("replica-of"))
+		if err != nil {
+			return err
+		}
+		cfg.Replica = &config.ReplicaConfig{PrimaryURL: ctx.String("replica-of")}
+	}
 	blockchain, err := commander.GetChainConnection(cfg.Ethereum)
 	if err != nil {
 		return err
@@ -24,6 +48,15 @@ func startCommander(_ *cli.Context) error {
 	return cmd.StartAndWait()
 }
 
+// validatePrimaryURL rejects local paths, replicas only support reading the database of the primary over its API
+func validatePrimaryURL(primaryURL string) error {
+	parsedURL, err := url.Parse(primaryURL)
+	if err != nil || parsedURL.Host == "" || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return errReplicaRequiresURL
+	}
+	return nil
+}
+
 func setupCloseHandler(cmd *commander.Commander) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
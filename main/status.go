@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var errInvalidStatusFormat = fmt.Errorf("--format must be either table or json")
+
+func statusCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "print the sync progress and the state of the rollup loop of a running commander",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "rpcurl",
+				Usage: "location of the hubble commander",
+				Value: "http://localhost:8080",
+			},
+			&cli.StringFlag{
+				Name:     "authKey",
+				Aliases:  []string{"authkey"},
+				Usage:    "authentication key of the admin API",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "either table or json",
+				Value: "table",
+			},
+		},
+		Action: printNodeStatus,
+	}
+}
+
+func printNodeStatus(ctx *cli.Context) error {
+	format := ctx.String("format")
+	if format != "table" && format != "json" {
+		return errInvalidStatusFormat
+	}
+
+	hubble := client.NewClient(ctx.String("rpcurl"), ctx.String("authKey"))
+	status, err := hubble.GetNodeStatus()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printJSON(status)
+	}
+	return printNodeStatusTable(status)
+}
+
+func printNodeStatusTable(status *dto.NodeStatus) error {
+	latestBatch := "none"
+	if status.LatestBatch != nil {
+		latestBatch = "#" + status.LatestBatch.String()
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Version\t%s\n", status.Version)
+	fmt.Fprintf(writer, "Remote block\t%d\n", status.RemoteBlock)
+	fmt.Fprintf(writer, "Local block\t%d\n", status.LocalBlock)
+	fmt.Fprintf(writer, "Synced block\t%d (%d behind)\n", status.SyncedBlock, blocksBehind(status))
+	fmt.Fprintf(writer, "Latest batch\t%s\n", latestBatch)
+	fmt.Fprintf(writer, "Active proposer\t%s\n", yesNo(status.IsActiveProposer))
+	fmt.Fprintf(writer, "Rollup loop\t%s\n", yesNo(status.Rollup.LoopActive))
+	fmt.Fprintf(writer, "Batch creation\t%s\n", enabledDisabled(status.Rollup.BatchCreationEnabled))
+	fmt.Fprintf(writer, "Paused on low balance\t%s\n", yesNo(status.Rollup.PausedOnLowBalance))
+	fmt.Fprintf(writer, "Accepting transactions\t%s\n", yesNo(status.AcceptingTransactions))
+	fmt.Fprintf(writer, "Migrating\t%s\n", yesNo(status.Rollup.Migrating))
+	fmt.Fprintf(writer, "Pending L1 transactions\t%d\n", status.Rollup.PendingL1Txs)
+	fmt.Fprintf(
+		writer,
+		"Mempool\t%d transfers, %d create2Transfers, %d mass migrations\n",
+		status.Mempool.Transfer,
+		status.Mempool.Create2Transfer,
+		status.Mempool.MassMigration,
+	)
+	return errors.WithStack(writer.Flush())
+}
+
+func blocksBehind(status *dto.NodeStatus) uint64 {
+	if status.SyncedBlock >= status.RemoteBlock {
+		return 0
+	}
+	return status.RemoteBlock - status.SyncedBlock
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func enabledDisabled(value bool) string {
+	if value {
+		return "enabled"
+	}
+	return "disabled"
+}
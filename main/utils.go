@@ -10,14 +10,26 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// read from the environment to keep the mnemonic out of the shell history
+const (
+	mnemonicEnv           = "HUBBLE_MNEMONIC"
+	mnemonicPassphraseEnv = "HUBBLE_MNEMONIC_PASSPHRASE"
+)
+
+var placeholderDomain = bls.Domain{0x00, 0x00, 0x00, 0x00}
+
 func newWallet(ctx *cli.Context) error {
+	if ctx.Bool("mnemonic") {
+		return newWalletFromMnemonic(ctx.String("path"))
+	}
+
 	privateKey := make([]byte, 32)
 	_, err := rand.Read(privateKey)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	wallet, err := bls.NewWallet(privateKey, bls.Domain{0x00, 0x00, 0x00, 0x00})
+	wallet, err := bls.NewWallet(privateKey, placeholderDomain)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -35,3 +47,27 @@ func newWallet(ctx *cli.Context) error {
 	fmt.Printf("%s\n", result)
 	return nil
 }
+
+func newWalletFromMnemonic(path string) error {
+	wallet, generatedMnemonic, err := walletFromMnemonic(path)
+	if err != nil {
+		return err
+	}
+	privateKey, _ := wallet.Bytes()
+
+	result := struct {
+		Mnemonic   string `json:",omitempty"`
+		Path       string
+		PrivateKey string
+		PublicKey  string
+	}{
+		Mnemonic:   generatedMnemonic,
+		Path:       path,
+		PrivateKey: fmt.Sprintf("0x%x", privateKey),
+		PublicKey:  wallet.PublicKey().String(),
+	}
+
+	encodedResult, _ := json.Marshal(result)
+	fmt.Printf("%s\n", encodedResult)
+	return nil
+}
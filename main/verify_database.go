@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Worldcoin/hubble-commander/scripts"
+	"github.com/urfave/cli/v2"
+)
+
+var errDatabaseInconsistent = fmt.Errorf("database is inconsistent, see the report")
+
+func verifyDatabase(ctx *cli.Context) error {
+	report, err := scripts.VerifyDatabase(ctx.Bool("repair"))
+	if err != nil {
+		return err
+	}
+
+	result, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", result)
+
+	if report.HasUnrepairableIssues() || (!report.Repaired && !report.IsConsistent()) {
+		return errDatabaseInconsistent
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/bls"
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/txstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// user states not found, see api/Readme.md
+const userStatesNotFoundCode = 99003
+
+var errTransactionFailed = fmt.Errorf("transaction failed")
+
+//nolint:funlen
+func walletCommand() *cli.Command {
+	keystoreFlag := &cli.StringFlag{
+		Name:  "keystore",
+		Usage: "directory of the encrypted keys",
+		Value: defaultKeystoreDir(),
+	}
+	passwordFileFlag := &cli.StringFlag{
+		Name:  "password-file",
+		Usage: "file holding the password of the keystore, read from " + keystorePasswordEnv + " when not set",
+	}
+	nameFlag := &cli.StringFlag{
+		Name:     "name",
+		Usage:    "name of the key in the keystore",
+		Required: true,
+	}
+	rpcURLFlag := &cli.StringFlag{
+		Name:  "rpcurl",
+		Usage: "location of the hubble commander",
+		Value: "http://localhost:8080",
+	}
+
+	return &cli.Command{
+		Name:  "wallet",
+		Usage: "manage BLS keys in an encrypted keystore and send transactions with them",
+		Subcommands: cli.Commands{
+			{
+				Name:  "new",
+				Usage: "create a new key",
+				Flags: []cli.Flag{
+					keystoreFlag,
+					passwordFileFlag,
+					nameFlag,
+					&cli.BoolFlag{
+						Name: "mnemonic",
+						Usage: "derive the key from a BIP-39 mnemonic, a new one is generated unless " + mnemonicEnv +
+							" is set, with an optional " + mnemonicPassphraseEnv,
+					},
+					&cli.StringFlag{
+						Name:  "path",
+						Usage: "derivation path of the key, used with --mnemonic",
+						Value: bls.DefaultDerivationPath,
+					},
+				},
+				Action: walletNew,
+			},
+			{
+				Name:  "import",
+				Usage: "import a hex-encoded private key",
+				Flags: []cli.Flag{
+					keystoreFlag,
+					passwordFileFlag,
+					nameFlag,
+					&cli.StringFlag{
+						Name:  "private-key-file",
+						Usage: "file holding the hex-encoded private key, '-' reads it from stdin, read from " + privateKeyEnv + " when not set",
+					},
+				},
+				Action: walletImport,
+			},
+			{
+				Name:   "list",
+				Usage:  "list the keys of the keystore",
+				Flags:  []cli.Flag{keystoreFlag},
+				Action: walletList,
+			},
+			{
+				Name:   "register",
+				Usage:  "register the public key of a key in the account tree",
+				Flags:  []cli.Flag{keystoreFlag, passwordFileFlag, nameFlag, rpcURLFlag},
+				Action: walletRegister,
+			},
+			{
+				Name:   "balances",
+				Usage:  "print the balances of all states of a key",
+				Flags:  []cli.Flag{keystoreFlag, nameFlag, rpcURLFlag},
+				Action: walletBalances,
+			},
+			{
+				Name:  "send",
+				Usage: "sign and send a transfer, create2Transfer or mass migration with the nonce of the sender state",
+				Flags: []cli.Flag{
+					keystoreFlag,
+					passwordFileFlag,
+					nameFlag,
+					rpcURLFlag,
+					&cli.StringFlag{
+						Name:  "type",
+						Usage: "either TRANSFER or CREATE2TRANSFER, inferred from --to when not set",
+					},
+					&cli.UintFlag{
+						Name:     "from",
+						Usage:    "state ID of the sender",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "state ID or public key of the receiver",
+					},
+					&cli.UintFlag{
+						Name:  "spoke",
+						Usage: "spoke ID to send a mass migration to instead of --to",
+					},
+					&cli.StringFlag{
+						Name:     "amount",
+						Usage:    "how much to send",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "fee",
+						Usage:    "how much to pay the sequencer",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "wait",
+						Usage: "watch the transaction until it is finalised",
+					},
+					&cli.DurationFlag{
+						Name:  "poll-interval",
+						Usage: "how often the status of the transaction is checked with --wait",
+						Value: 5 * time.Second,
+					},
+				},
+				Action: walletSend,
+			},
+		},
+	}
+}
+
+func walletNew(ctx *cli.Context) error {
+	password, err := keystorePassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wallet *bls.Wallet
+	var mnemonic string
+	if ctx.Bool("mnemonic") {
+		wallet, mnemonic, err = walletFromMnemonic(ctx.String("path"))
+	} else {
+		wallet, err = bls.NewRandomWallet(placeholderDomain)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = openKeystore(ctx).saveKey(ctx.String("name"), wallet, password)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(struct {
+		Name      string
+		PublicKey string
+		Mnemonic  string `json:",omitempty"`
+	}{
+		Name:      ctx.String("name"),
+		PublicKey: wallet.PublicKey().String(),
+		Mnemonic:  mnemonic,
+	})
+}
+
+// walletFromMnemonic returns the mnemonic only when it is generated
+func walletFromMnemonic(path string) (wallet *bls.Wallet, generatedMnemonic string, err error) {
+	mnemonic, restored := os.LookupEnv(mnemonicEnv)
+	if !restored {
+		mnemonic, err = bls.NewMnemonic()
+		if err != nil {
+			return nil, "", err
+		}
+		generatedMnemonic = mnemonic
+	}
+
+	wallet, err = bls.NewWalletFromMnemonic(mnemonic, os.Getenv(mnemonicPassphraseEnv), path, placeholderDomain)
+	if err != nil {
+		return nil, "", err
+	}
+	return wallet, generatedMnemonic, nil
+}
+
+func walletImport(ctx *cli.Context) error {
+	password, err := keystorePassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := importedPrivateKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	wallet, err := bls.NewWallet(privateKey, placeholderDomain)
+	if err != nil {
+		return err
+	}
+
+	err = openKeystore(ctx).saveKey(ctx.String("name"), wallet, password)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(struct {
+		Name      string
+		PublicKey string
+	}{
+		Name:      ctx.String("name"),
+		PublicKey: wallet.PublicKey().String(),
+	})
+}
+
+func walletList(ctx *cli.Context) error {
+	keys := openKeystore(ctx)
+	names, err := keys.keyNames()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tPUBLIC KEY")
+	for _, name := range names {
+		keyFile, err := keys.readKeyFile(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", name, keyFile.PublicKey.String())
+	}
+	return writer.Flush()
+}
+
+func walletRegister(ctx *cli.Context) error {
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	wallet, err := loadWallet(ctx, hubble)
+	if err != nil {
+		return err
+	}
+
+	pubKeyID, err := hubble.RegisterWallet(wallet)
+	if err != nil {
+		return err
+	}
+	log.Infof("Registered public key %s with pubKeyID %d", wallet.PublicKey().String(), *pubKeyID)
+	return nil
+}
+
+func walletBalances(ctx *cli.Context) error {
+	keyFile, err := openKeystore(ctx).readKeyFile(ctx.String("name"))
+	if err != nil {
+		return err
+	}
+
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	userStates, err := hubble.GetUserStates(&keyFile.PublicKey)
+	if client.ErrorCode(err) == userStatesNotFoundCode {
+		userStates, err = []dto.UserStateWithID{}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "STATE ID\tTOKEN ID\tBALANCE\tNONCE\t")
+	for i := range userStates {
+		state := &userStates[i]
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t\n", state.StateID, state.TokenID.String(), state.Balance.String(), state.Nonce.String())
+	}
+	return writer.Flush()
+}
+
+func walletSend(ctx *cli.Context) error {
+	amount, err := parseUint256Flag(ctx, "amount")
+	if err != nil {
+		return err
+	}
+	fee, err := parseUint256Flag(ctx, "fee")
+	if err != nil {
+		return err
+	}
+
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	wallet, err := loadWallet(ctx, hubble)
+	if err != nil {
+		return err
+	}
+	privateKey, _ := wallet.Bytes()
+	account := openAccount(hubble, uint32(ctx.Uint("from")), privateKey)
+
+	var txHash *common.Hash
+	switch walletTxType(ctx) {
+	case txtype.Transfer:
+		txHash, err = account.SendTransfer(getToStateID(ctx), *amount, *fee)
+	case txtype.Create2Transfer:
+		txHash, err = account.SendCreate2Transfer(decodePublicKey(ctx.String("to")), *amount, *fee)
+	case txtype.MassMigration:
+		txHash, err = account.SendMassMigration(uint32(ctx.Uint("spoke")), *amount, *fee)
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Submitted transaction. TxHash=%s", txHash.String())
+
+	if !ctx.Bool("wait") {
+		return nil
+	}
+	return watchTransaction(hubble, *txHash, ctx.Duration("poll-interval"))
+}
+
+func walletTxType(ctx *cli.Context) txtype.TransactionType {
+	if ctx.IsSet("spoke") {
+		return txtype.MassMigration
+	}
+	return getTxType(ctx)
+}
+
+// loadWallet decrypts the key of the keystore to sign for the network of the commander
+func loadWallet(ctx *cli.Context, hubble *client.Client) (*bls.Wallet, error) {
+	password, err := keystorePassword(ctx)
+	if err != nil {
+		return nil, err
+	}
+	networkInfo, err := hubble.GetNetworkInfo()
+	if err != nil {
+		return nil, err
+	}
+	return openKeystore(ctx).loadKey(ctx.String("name"), password, networkInfo.SignatureDomain)
+}
+
+// watchTransaction logs the status changes of the transaction until it is finalised or fails
+func watchTransaction(hubble *client.Client, txHash common.Hash, pollInterval time.Duration) error {
+	var lastStatus *txstatus.TransactionStatus
+	for {
+		receipt, err := hubble.GetTransaction(txHash)
+		if err != nil {
+			return err
+		}
+
+		if lastStatus == nil || *lastStatus != receipt.Status {
+			log.Infof("Transaction %s is %s", txHash.String(), receipt.Status.String())
+			lastStatus = receipt.Status.Ref()
+		}
+
+		switch receipt.Status {
+		case txstatus.Finalised:
+			return nil
+		case txstatus.Error:
+			if receipt.ErrorMessage != nil {
+				return errors.WithMessage(errTransactionFailed, *receipt.ErrorMessage)
+			}
+			return errors.WithStack(errTransactionFailed)
+		case txstatus.Pending, txstatus.Submitted, txstatus.Mined:
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func parseUint256Flag(ctx *cli.Context, name string) (*models.Uint256, error) {
+	value, ok := new(big.Int).SetString(ctx.String(name), 10)
+	if !ok || value.Sign() < 0 {
+		return nil, errors.Errorf("--%s must be a non-negative decimal number", name)
+	}
+	return models.NewUint256FromBig(*value), nil
+}
+
+func printJSON(value interface{}) error {
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Printf("%s\n", encodedValue)
+	return nil
+}
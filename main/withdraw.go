@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Worldcoin/hubble-commander/client"
+	"github.com/Worldcoin/hubble-commander/contracts/withdrawmanager"
+	"github.com/Worldcoin/hubble-commander/eth"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/txstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/consts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	errNotMassMigration    = fmt.Errorf("only the tokens of mass migrations can be withdrawn")
+	errMassMigrationStatus = fmt.Errorf("mass migration must be finalised to withdraw its tokens")
+	errRecipientNotSender  = fmt.Errorf(
+		"claimTokens pays the sender of the L1 transaction, --recipient must be the ethereum account of the config",
+	)
+	errWithdrawKeyDiff = fmt.Errorf("key does not match the public key of the state the mass migration was sent from")
+	errL1TxReverted    = fmt.Errorf("L1 transaction reverted")
+)
+
+func withdrawCommand() *cli.Command {
+	return &cli.Command{
+		Name: "withdraw",
+		Usage: "claim the tokens of a finalised mass migration to the withdraw manager on L1, signing the recipient " +
+			"with a key of the keystore",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "keystore",
+				Usage: "directory of the encrypted keys",
+				Value: defaultKeystoreDir(),
+			},
+			&cli.StringFlag{
+				Name:  "password-file",
+				Usage: "file holding the password of the keystore, read from " + keystorePasswordEnv + " when not set",
+			},
+			&cli.StringFlag{
+				Name:     "name",
+				Usage:    "name of the key which sent the mass migration",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "rpcurl",
+				Usage: "location of the hubble commander, the proof methods of its API must be enabled",
+				Value: "http://localhost:8080",
+			},
+			chainSpecFlag(),
+			&cli.StringFlag{
+				Name:     "tx-hash",
+				Usage:    "hash of the mass migration",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "recipient",
+				Usage: "L1 address to receive the tokens, must be the ethereum account of the config which sends claimTokens",
+			},
+			&cli.BoolFlag{
+				Name:  "process-commitment",
+				Usage: "process the commitment of the mass migration first, required once per commitment before claiming",
+			},
+		},
+		Action: withdraw,
+	}
+}
+
+func withdraw(ctx *cli.Context) error {
+	hubble := client.NewClient(ctx.String("rpcurl"), "")
+	wallet, err := loadWallet(ctx, hubble)
+	if err != nil {
+		return err
+	}
+
+	txHash := common.HexToHash(ctx.String("tx-hash"))
+	commitmentID, err := getMassMigrationCommitmentID(hubble, txHash)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := newL1Client(ctx)
+	if err != nil {
+		return err
+	}
+	account := ethClient.Blockchain.GetAccount()
+	if ctx.IsSet("recipient") && common.HexToAddress(ctx.String("recipient")) != account.From {
+		return errors.WithStack(errRecipientNotSender)
+	}
+
+	withdrawManager, err := withdrawmanager.NewWithdrawManager(ethClient.ChainState.WithdrawManager, ethClient.Blockchain.GetBackend())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if ctx.Bool("process-commitment") {
+		err = processWithdrawCommitment(hubble, ethClient, withdrawManager, commitmentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	withdrawProof, err := hubble.GetWithdrawProof(*commitmentID, txHash)
+	if err != nil {
+		return err
+	}
+	publicKeyProof, err := hubble.GetPublicKeyProofByPubKeyID(withdrawProof.UserState.PubKeyID)
+	if err != nil {
+		return err
+	}
+	if *publicKeyProof.PublicKey != *wallet.PublicKey() {
+		return errors.WithStack(errWithdrawKeyDiff)
+	}
+
+	signature, err := wallet.Sign(account.From.Bytes())
+	if err != nil {
+		return err
+	}
+
+	tx, err := withdrawManager.ClaimTokens(
+		account,
+		utils.ByteSliceTo32ByteArray(withdrawProof.Root.Bytes()),
+		withdrawProofToCalldata(withdrawProof),
+		wallet.PublicKey().BigInts(),
+		signature.BigInts(),
+		publicKeyProof.Witness.Bytes(),
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = waitForL1Tx(ethClient, tx)
+	if err != nil {
+		return errors.WithMessage(err, "claimTokens")
+	}
+
+	log.Infof(
+		"Claimed %s of token %s to %s. L1 TxHash=%s",
+		withdrawProof.UserState.Balance.MulN(consts.L2Unit).String(),
+		withdrawProof.UserState.TokenID.String(),
+		account.From.String(),
+		tx.Hash().String(),
+	)
+	return nil
+}
+
+func getMassMigrationCommitmentID(hubble *client.Client, txHash common.Hash) (*models.CommitmentID, error) {
+	receipt, err := hubble.GetTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.TxType != txtype.MassMigration {
+		return nil, errors.WithStack(errNotMassMigration)
+	}
+	if receipt.Status != txstatus.Finalised || receipt.CommitmentSlot == nil {
+		return nil, errors.WithMessagef(errMassMigrationStatus, "status %s", receipt.Status.String())
+	}
+	return &models.CommitmentID{
+		BatchID:      receipt.CommitmentSlot.BatchID,
+		IndexInBatch: receipt.CommitmentSlot.IndexInBatch,
+	}, nil
+}
+
+// processWithdrawCommitment moves the tokens of the commitment to the withdraw manager
+func processWithdrawCommitment(
+	hubble *client.Client,
+	ethClient *eth.Client,
+	withdrawManager *withdrawmanager.WithdrawManager,
+	commitmentID *models.CommitmentID,
+) error {
+	proof, err := hubble.GetMassMigrationCommitmentProof(*commitmentID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := withdrawManager.ProcessWithdrawCommitment(
+		ethClient.Blockchain.GetAccount(),
+		commitmentID.BatchID.ToBig(),
+		massMigrationCommitmentProofToCalldata(proof),
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = waitForL1Tx(ethClient, tx)
+	if err != nil {
+		return errors.WithMessage(err, "processWithdrawCommitment")
+	}
+	log.Infof("Processed commitment #%d of batch #%s", commitmentID.IndexInBatch, commitmentID.BatchID.String())
+	return nil
+}
+
+func waitForL1Tx(ethClient *eth.Client, tx *types.Transaction) error {
+	receipt, err := ethClient.WaitToBeMined(tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.WithMessagef(errL1TxReverted, "TxHash=%s", tx.Hash().String())
+	}
+	return nil
+}
+
+func massMigrationCommitmentProofToCalldata(proof *dto.MassMigrationCommitmentProof) withdrawmanager.TypesMMCommitmentInclusionProof {
+	return withdrawmanager.TypesMMCommitmentInclusionProof{
+		Commitment: withdrawmanager.TypesMassMigrationCommitment{
+			StateRoot: utils.ByteSliceTo32ByteArray(proof.StateRoot.Bytes()),
+			Body: withdrawmanager.TypesMassMigrationBody{
+				AccountRoot:  utils.ByteSliceTo32ByteArray(proof.Body.AccountRoot.Bytes()),
+				Signature:    proof.Body.Signature.BigInts(),
+				SpokeID:      big.NewInt(int64(proof.Body.Meta.SpokeID)),
+				WithdrawRoot: utils.ByteSliceTo32ByteArray(proof.Body.WithdrawRoot.Bytes()),
+				TokenID:      proof.Body.Meta.TokenID.ToBig(),
+				Amount:       proof.Body.Meta.Amount.ToBig(),
+				FeeReceiver:  big.NewInt(int64(proof.Body.Meta.FeeReceiverStateID)),
+				Txs:          proof.Body.Transactions,
+			},
+		},
+		Path:    big.NewInt(int64(proof.Path.Path)),
+		Witness: proof.Witness.Bytes(),
+	}
+}
+
+func withdrawProofToCalldata(proof *dto.WithdrawProof) withdrawmanager.TypesStateMerkleProofWithPath {
+	return withdrawmanager.TypesStateMerkleProofWithPath{
+		State: withdrawmanager.TypesUserState{
+			PubkeyID: big.NewInt(int64(proof.UserState.PubKeyID)),
+			TokenID:  proof.UserState.TokenID.ToBig(),
+			Balance:  proof.UserState.Balance.ToBig(),
+			Nonce:    proof.UserState.Nonce.ToBig(),
+		},
+		Path:    big.NewInt(int64(proof.Path.Path)),
+		Witness: proof.Witness.Bytes(),
+	}
+}
@@ -8,6 +8,9 @@ const (
 	rollupSubsystem     = "rollup"
 	syncingSubsystem    = "syncing"
 	blockchainSubsystem = "blockchain"
+	stakeSubsystem      = "stake"
+	operatorSubsystem   = "operator"
+	pruningSubsystem    = "pruning"
 )
 
 // API metrics
@@ -31,6 +31,20 @@ type CommanderMetrics struct {
 	MempoolSizeTransfer        prometheus.Gauge
 	MempoolSizeCreate2Transfer prometheus.Gauge
 	MempoolSizeMassMigration   prometheus.Gauge
+
+	// Stake
+	StakeLocked    prometheus.Gauge
+	StakeWithdrawn prometheus.Counter
+
+	// Operator
+	OperatorBalance            prometheus.Gauge
+	OperatorAffordableBatches  prometheus.Gauge
+	OperatorAffordableDisputes prometheus.Gauge
+
+	// Pruning
+	PrunedStateUpdates    prometheus.Counter
+	PruningReclaimedBytes prometheus.Counter
+	PrunedUpToBatchID     prometheus.Gauge
 }
 
 func NewCommanderMetrics() *CommanderMetrics {
@@ -42,6 +56,9 @@ func NewCommanderMetrics() *CommanderMetrics {
 	commanderMetrics.initializeRollupLoopMetrics()
 	commanderMetrics.initializeSyncingMetrics()
 	commanderMetrics.initializeBlockchainMetrics()
+	commanderMetrics.initializeStakeMetrics()
+	commanderMetrics.initializeOperatorMetrics()
+	commanderMetrics.initializePruningMetrics()
 
 	commanderMetrics.MempoolSize = prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespace,
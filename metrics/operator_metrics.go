@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (c *CommanderMetrics) initializeOperatorMetrics() {
+	c.OperatorBalance = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: operatorSubsystem,
+		Name:      "balance_wei",
+		Help:      "Balance of the operator account",
+	})
+	c.OperatorAffordableBatches = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: operatorSubsystem,
+		Name:      "affordable_batches",
+		Help:      "Estimated number of batches the operator can still submit after reserving funds for disputes",
+	})
+	c.OperatorAffordableDisputes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: operatorSubsystem,
+		Name:      "affordable_disputes",
+		Help:      "Estimated number of disputes the operator can still submit",
+	})
+
+	c.registry.MustRegister(
+		c.OperatorBalance,
+		c.OperatorAffordableBatches,
+		c.OperatorAffordableDisputes,
+	)
+}
+
+func (c *CommanderMetrics) SaveOperatorBudget(balance *models.Uint256, affordableBatches, affordableDisputes uint64) {
+	c.OperatorBalance.Set(weiToFloat(balance))
+	c.OperatorAffordableBatches.Set(float64(affordableBatches))
+	c.OperatorAffordableDisputes.Set(float64(affordableDisputes))
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (c *CommanderMetrics) initializePruningMetrics() {
+	c.PrunedStateUpdates = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: pruningSubsystem,
+		Name:      "state_updates_total",
+		Help:      "Number of state updates deleted by pruning since the commander started",
+	})
+	c.PruningReclaimedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: pruningSubsystem,
+		Name:      "reclaimed_bytes_total",
+		Help:      "Estimated size of the data deleted by pruning since the commander started",
+	})
+	c.PrunedUpToBatchID = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: pruningSubsystem,
+		Name:      "batch_id",
+		Help:      "ID of the batch up to which state updates were pruned",
+	})
+
+	c.registry.MustRegister(
+		c.PrunedStateUpdates,
+		c.PruningReclaimedBytes,
+		c.PrunedUpToBatchID,
+	)
+}
+
+func (c *CommanderMetrics) SavePruningResult(batchID *models.Uint256, prunedStateUpdates, reclaimedBytes uint64) {
+	c.PrunedStateUpdates.Add(float64(prunedStateUpdates))
+	c.PruningReclaimedBytes.Add(float64(reclaimedBytes))
+	c.PrunedUpToBatchID.Set(float64(batchID.Uint64()))
+}
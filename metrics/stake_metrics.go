@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"math/big"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func (c *CommanderMetrics) initializeStakeMetrics() {
+	c.StakeLocked = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: stakeSubsystem,
+		Name:      "locked_wei",
+		Help:      "Total amount of stake locked in batches which were not withdrawn yet",
+	})
+	c.StakeWithdrawn = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: stakeSubsystem,
+		Name:      "withdrawn_wei",
+		Help:      "Total amount of stake withdrawn since the commander started",
+	})
+
+	c.registry.MustRegister(
+		c.StakeLocked,
+		c.StakeWithdrawn,
+	)
+}
+
+func (c *CommanderMetrics) SaveStakeLocked(stakeAmount *models.Uint256, stakesCount int) {
+	locked := stakeAmount.MulN(uint64(stakesCount))
+	c.StakeLocked.Set(weiToFloat(locked))
+}
+
+func (c *CommanderMetrics) AddStakeWithdrawn(stakeAmount *models.Uint256) {
+	c.StakeWithdrawn.Add(weiToFloat(stakeAmount))
+}
+
+func weiToFloat(value *models.Uint256) float64 {
+	result, _ := new(big.Float).SetInt(value.ToBig()).Float64()
+	return result
+}
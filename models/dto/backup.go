@@ -0,0 +1,16 @@
+package dto
+
+type BackupParams struct {
+	// name of the backup file in backup.dir on the commander host, it must not exist yet
+	Path string
+	// version returned by the previous backup, 0 creates a full backup
+	Since uint64
+}
+
+type Backup struct {
+	// full path of the backup file on the commander host
+	Path  string
+	Since uint64
+	// pass it as Since of the next backup to create an incremental one
+	Version uint64
+}
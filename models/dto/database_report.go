@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type DatabaseReport struct {
+	// nil when the root rebuilt from the leaves matches the stored one
+	StateTreeRoot   *TreeRootMismatch
+	AccountTreeRoot *TreeRootMismatch
+	// batched transactions whose CommitmentSlot points to a missing commitment
+	OrphanedTxs           []models.CommitmentSlot
+	PendingStates         []PendingStateMismatch
+	PendingPubkeyBalances []PendingPubkeyBalanceMismatch
+	Indexes               []IndexMismatch
+	// only pending states, pending pubkey balances and indexes are repaired
+	Repaired bool
+}
+
+type TreeRootMismatch struct {
+	Stored     common.Hash
+	Recomputed common.Hash
+}
+
+type PendingStateMismatch struct {
+	StateID           uint32
+	StoredNonce       models.Uint256
+	StoredBalance     models.Uint256
+	RecomputedNonce   models.Uint256
+	RecomputedBalance models.Uint256
+}
+
+type PendingPubkeyBalanceMismatch struct {
+	PubKey            models.PublicKey
+	StoredBalance     models.Uint256
+	RecomputedBalance models.Uint256
+}
+
+type IndexMismatch struct {
+	Type  string
+	Index string
+	// indexed value, or the whole key of the entry for the indexes stored with one raw key per item
+	Value hexutil.Bytes
+	// number of indexed keys missing from the stored entry
+	MissingKeys int
+	// number of keys in the stored entry which are not indexed by any item
+	DanglingKeys int
+}
+
+func (r *DatabaseReport) IsConsistent() bool {
+	return r.StateTreeRoot == nil &&
+		r.AccountTreeRoot == nil &&
+		len(r.OrphanedTxs) == 0 &&
+		len(r.PendingStates) == 0 &&
+		len(r.PendingPubkeyBalances) == 0 &&
+		len(r.Indexes) == 0
+}
+
+// HasUnrepairableIssues is true when the database has to be restored from a backup or resynced
+func (r *DatabaseReport) HasUnrepairableIssues() bool {
+	return r.StateTreeRoot != nil || r.AccountTreeRoot != nil || len(r.OrphanedTxs) > 0
+}
@@ -52,3 +52,13 @@ type CommitmentProofBody struct {
 	FeeReceiver  uint32
 	Transactions interface{}
 }
+
+// WithdrawClaim contains all arguments of WithdrawManager.claimTokens except for the
+// signature of the recipient address
+type WithdrawClaim struct {
+	CommitmentID    models.CommitmentID
+	TransactionHash common.Hash
+	WithdrawProof
+	PublicKey        *models.PublicKey
+	PublicKeyWitness models.Witness
+}
@@ -16,6 +16,7 @@ type NetworkInfo struct {
 	WithdrawManager                common.Address
 	Rollup                         common.Address
 	BlockNumber                    uint32
+	SyncedBlock                    uint64
 	TransactionCount               uint64
 	AccountCount                   uint32
 	LatestBatch                    *models.Uint256
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/Worldcoin/hubble-commander/models"
+
+type NodeStatus struct {
+	Version               string
+	LocalBlock            uint32
+	SyncedBlock           uint64
+	RemoteBlock           uint64
+	LatestBatch           *models.Uint256
+	IsActiveProposer      bool
+	AcceptingTransactions bool
+	Rollup                RollupStatus
+	Mempool               MempoolStatus
+}
+
+// RollupStatus is the state of the rollup loop kept in memory by the commander
+type RollupStatus struct {
+	LoopActive           bool
+	BatchCreationEnabled bool
+	PausedOnLowBalance   bool
+	Migrating            bool
+	PendingL1Txs         int
+}
+
+type MempoolStatus struct {
+	Transfer        uint32
+	Create2Transfer uint32
+	MassMigration   uint32
+}
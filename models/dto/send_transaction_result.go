@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/ethereum/go-ethereum/common"
+
+// SendTransactionResult holds either the hash of an accepted transaction or the reason it was rejected
+type SendTransactionResult struct {
+	Hash  *common.Hash          `json:",omitempty"`
+	Error *SendTransactionError `json:",omitempty"`
+}
+
+type SendTransactionError struct {
+	Code    int
+	Message string
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "github.com/Worldcoin/hubble-commander/models"
+
+type Stake struct {
+	BatchID           models.Uint256
+	Amount            models.Uint256
+	FinalisationBlock uint32
+	Withdrawable      bool
+}
@@ -36,3 +36,8 @@ func MakeUserState(userState *models.UserState) UserState {
 		Nonce:    userState.Nonce,
 	}
 }
+
+type UserStatesPage struct {
+	States     []UserStateWithID
+	NextCursor *uint32
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var RegisteredSpokePrefix = GetBadgerHoldPrefix(RegisteredSpoke{})
+
 type RegisteredSpoke struct {
 	ID       Uint256
 	Contract common.Address
@@ -4,6 +4,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var RegisteredTokenPrefix = GetBadgerHoldPrefix(RegisteredToken{})
+
 type RegisteredToken struct {
 	ID       Uint256
 	Contract common.Address
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// A snapshot holds the rollup state at a finalised batch. New commanders can import it and sync only the batches
+// submitted after it instead of replaying the whole rollup history. It is streamed as a SnapshotHeader followed by
+// SnapshotRecords, each of them a separate JSON value, so the state never has to be held in memory at once.
+
+type SnapshotHeader struct {
+	ChainState      ChainState
+	GenesisAccounts GenesisAccounts
+
+	// Batch is the snapshot batch, the headers of all batches up to and including it follow as records
+	Batch Batch
+	// Commitments of the snapshot batch encoded the same way they are stored in the database
+	Commitments []hexutil.Bytes
+
+	// LastDepositSubtreeID is the ID of the deposit subtree submitted in the last deposit batch
+	// included in the snapshot, nil if there were no deposit batches
+	LastDepositSubtreeID *Uint256
+}
+
+// SnapshotRecord holds exactly one of its fields
+type SnapshotRecord struct {
+	Batch           *Batch           `json:",omitempty"`
+	StateLeaf       *StateLeaf       `json:",omitempty"`
+	Account         *AccountLeaf     `json:",omitempty"`
+	RegisteredToken *RegisteredToken `json:",omitempty"`
+	RegisteredSpoke *RegisteredSpoke `json:",omitempty"`
+}
@@ -0,0 +1,461 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// batches are read in pages so that exports of long-running chains don't have to fit in memory
+	exportBatchPageSize = 256
+
+	// incremental exports keep the ID of the last exported batch next to the export file
+	lastExportedBatchFileSuffix = ".last-batch"
+)
+
+type ExportOptions struct {
+	Format    string
+	FromBatch *models.Uint256
+	ToBatch   *models.Uint256
+	// filter on the finalisation block of batches, submitted batches are skipped when set
+	FromBlock *uint32
+	ToBlock   *uint32
+	// SinceBatch exports batches after the given one and appends them to the file. Only finalised batches are
+	// exported in this mode, so records never change after they were written and every batch is exported once.
+	SinceBatch *models.Uint256
+	// Incremental appends finalised batches after the last batch exported to the file, which is kept in
+	// a file next to it. SinceBatch overrides the stored batch ID.
+	Incremental bool
+	// BackupFiles are loaded into a temporary database which the data is exported from, so that exports
+	// don't need to stop the commander which locks its database
+	BackupFiles []string
+}
+
+func (o *ExportOptions) validate() error {
+	if o.isIncremental() && o.FromBatch != nil {
+		return fmt.Errorf("since-batch and incremental can't be used together with from-batch")
+	}
+	if o.FromBatch != nil && o.ToBatch != nil && o.FromBatch.Cmp(o.ToBatch) > 0 {
+		return fmt.Errorf("from-batch is greater than to-batch")
+	}
+	if o.FromBlock != nil && o.ToBlock != nil && *o.FromBlock > *o.ToBlock {
+		return fmt.Errorf("from-block is greater than to-block")
+	}
+	return nil
+}
+
+func (o *ExportOptions) hasFilters() bool {
+	return o.FromBatch != nil || o.ToBatch != nil || o.FromBlock != nil || o.ToBlock != nil || o.isIncremental()
+}
+
+func (o *ExportOptions) isIncremental() bool {
+	return o.Incremental || o.SinceBatch != nil
+}
+
+func (o *ExportOptions) matchesBlock(batch *models.Batch) bool {
+	if o.FromBlock == nil && o.ToBlock == nil {
+		return true
+	}
+	if batch.FinalisationBlock == nil {
+		return false
+	}
+	if o.FromBlock != nil && *batch.FinalisationBlock < *o.FromBlock {
+		return false
+	}
+	return o.ToBlock == nil || *batch.FinalisationBlock <= *o.ToBlock
+}
+
+type writeRecordFunc func(record exportRecord) error
+
+type batchRecordsFunc func(storage *st.Storage, batch *models.Batch, status batchstatus.BatchStatus, write writeRecordFunc) error
+
+type exportResult struct {
+	Batches     int
+	Records     int
+	LastBatchID *models.Uint256
+}
+
+func ExportBatches(filePath string, opts *ExportOptions) error {
+	return exportBatchData(filePath, batchHeader, opts, writeBatchRecords)
+}
+
+func ExportCommitments(filePath string, opts *ExportOptions) error {
+	return exportBatchData(filePath, commitmentHeader, opts, writeCommitmentRecords)
+}
+
+func ExportTransactions(filePath string, opts *ExportOptions) error {
+	return exportBatchData(filePath, transactionHeader, opts, writeTransactionRecords)
+}
+
+func ExportDeposits(filePath string, opts *ExportOptions) error {
+	return exportBatchData(filePath, depositHeader, opts, writeDepositRecords)
+}
+
+// ExportTokens exports all registered tokens, batch and block filters don't apply to them
+func ExportTokens(filePath string, opts *ExportOptions) error {
+	if opts.hasFilters() {
+		return fmt.Errorf("batch and block filters are not supported when exporting tokens")
+	}
+
+	return withExportFile(filePath, opts.BackupFiles, false, func(storage *st.Storage, file *os.File, isEmpty bool) error {
+		writer, err := newRecordWriter(opts.Format, file, tokenHeader, isEmpty)
+		if err != nil {
+			return err
+		}
+
+		count, err := writeTokenRecords(storage, writer)
+		if err != nil {
+			return err
+		}
+		log.Infof("exported %d registered tokens", count)
+		return nil
+	})
+}
+
+func exportBatchData(filePath string, header []string, opts *ExportOptions, recordsFunc batchRecordsFunc) error {
+	err := opts.validate()
+	if err != nil {
+		return err
+	}
+	if opts.Incremental && opts.SinceBatch == nil {
+		opts.SinceBatch, err = readLastExportedBatch(filePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return withExportFile(filePath, opts.BackupFiles, opts.isIncremental(), func(storage *st.Storage, file *os.File, isEmpty bool) error {
+		writer, err := newRecordWriter(opts.Format, file, header, isEmpty)
+		if err != nil {
+			return err
+		}
+
+		result, err := exportBatchRecords(storage, writer, opts, recordsFunc)
+		if err != nil {
+			return err
+		}
+
+		if result.LastBatchID == nil {
+			log.Infof("no batches to export")
+			return nil
+		}
+		log.Infof("exported %d records from %d batches, last exported batch #%s", result.Records, result.Batches, result.LastBatchID)
+
+		if !opts.isIncremental() {
+			return nil
+		}
+		// records must be on disk before the batch is marked as exported, otherwise they would be skipped by the next run
+		err = file.Sync()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return writeLastExportedBatch(filePath, result.LastBatchID)
+	})
+}
+
+// withExportFile opens the storage and the export file, which is truncated unless appendToFile is set
+func withExportFile(
+	filePath string,
+	backupFiles []string,
+	appendToFile bool,
+	exportFunc func(storage *st.Storage, file *os.File, isEmpty bool) error,
+) (err error) {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	storage, closeStorage, err := openExportStorage(cfg, backupFiles)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := closeStorage()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendToFile {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(filePath, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return exportFunc(storage, file, info.Size() == 0)
+}
+
+// openExportStorage opens the database at badger.path, which is locked while the commander is running, unless backup
+// files are given. Backups are loaded into a temporary database which is removed once the storage is closed.
+func openExportStorage(cfg *config.Config, backupFiles []string) (storage *st.Storage, closeStorage func() error, err error) {
+	if len(backupFiles) == 0 {
+		storage, err = st.NewStorage(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return storage, storage.Close, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "hubble-export-")
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(tempDir)
+		}
+	}()
+
+	badgerCfg := *cfg.Badger
+	badgerCfg.Path = tempDir
+	err = loadBackups(&badgerCfg, backupFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storage, err = st.NewStorage(&config.Config{
+		Badger:    &badgerCfg,
+		Bootstrap: &config.CommanderBootstrapConfig{},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeStorage = func() error {
+		closeErr := storage.Close()
+		removeErr := os.RemoveAll(tempDir)
+		if closeErr != nil {
+			return closeErr
+		}
+		return errors.WithStack(removeErr)
+	}
+	return storage, closeStorage, nil
+}
+
+func lastExportedBatchFilePath(filePath string) string {
+	return filePath + lastExportedBatchFileSuffix
+}
+
+// readLastExportedBatch returns nil when nothing was exported to the file yet
+func readLastExportedBatch(filePath string) (*models.Uint256, error) {
+	content, err := os.ReadFile(lastExportedBatchFilePath(filePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	batchID, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid last exported batch in %s", lastExportedBatchFilePath(filePath))
+	}
+	return models.NewUint256(batchID), nil
+}
+
+// writeLastExportedBatch replaces the file with a rename, so that it never holds a partially written ID
+func writeLastExportedBatch(filePath string, batchID *models.Uint256) error {
+	path := lastExportedBatchFilePath(filePath)
+	tempPath := path + ".tmp"
+	err := os.WriteFile(tempPath, []byte(batchID.String()+"\n"), 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tempPath, path))
+}
+
+func exportBatchRecords(
+	storage *st.Storage,
+	writer recordWriter,
+	opts *ExportOptions,
+	recordsFunc batchRecordsFunc,
+) (*exportResult, error) {
+	syncedBlock, err := storage.GetSyncedBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &exportResult{}
+	write := func(record exportRecord) error {
+		result.Records++
+		return writer.Write(record)
+	}
+
+	from := models.MakeUint256(0)
+	if opts.FromBatch != nil {
+		from = *opts.FromBatch
+	}
+	if opts.SinceBatch != nil {
+		from = *opts.SinceBatch.AddN(1)
+	}
+
+	for {
+		to := from.AddN(exportBatchPageSize - 1)
+		if opts.ToBatch != nil && opts.ToBatch.Cmp(to) < 0 {
+			to = opts.ToBatch
+		}
+		if from.Cmp(to) > 0 {
+			break
+		}
+
+		batches, err := storage.GetBatchesInRange(&from, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(batches) == 0 {
+			break
+		}
+
+		done, err := exportBatchPage(storage, batches, uint32(*syncedBlock), opts, recordsFunc, write, result)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+		from = *to.AddN(1)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// exportBatchPage returns true when the export has to stop before the end of the requested range
+func exportBatchPage(
+	storage *st.Storage,
+	batches []models.Batch,
+	syncedBlock uint32,
+	opts *ExportOptions,
+	recordsFunc batchRecordsFunc,
+	write writeRecordFunc,
+	result *exportResult,
+) (bool, error) {
+	for i := range batches {
+		batch := &batches[i]
+		status := calculateBatchStatus(syncedBlock, batch)
+		if opts.isIncremental() && status != batchstatus.Finalised {
+			return true, nil
+		}
+		if !opts.matchesBlock(batch) {
+			continue
+		}
+
+		err := recordsFunc(storage, batch, status, write)
+		if err != nil {
+			return false, err
+		}
+		result.Batches++
+		result.LastBatchID = &batch.ID
+	}
+	return false, nil
+}
+
+// calculateBatchStatus mirrors the API, using the last block synced by the commander as the latest block
+func calculateBatchStatus(syncedBlock uint32, batch *models.Batch) batchstatus.BatchStatus {
+	if batch.FinalisationBlock == nil {
+		return batchstatus.Submitted
+	}
+	if syncedBlock < *batch.FinalisationBlock {
+		return batchstatus.Mined
+	}
+	return batchstatus.Finalised
+}
+
+func writeBatchRecords(_ *st.Storage, batch *models.Batch, status batchstatus.BatchStatus, write writeRecordFunc) error {
+	return write(newBatchRecord(batch, status))
+}
+
+func writeCommitmentRecords(storage *st.Storage, batch *models.Batch, _ batchstatus.BatchStatus, write writeRecordFunc) error {
+	commitments, err := storage.GetCommitmentsByBatchID(batch.ID)
+	if err != nil {
+		return err
+	}
+	for i := range commitments {
+		err = write(newCommitmentRecord(commitments[i]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTransactionRecords(storage *st.Storage, batch *models.Batch, status batchstatus.BatchStatus, write writeRecordFunc) error {
+	if batch.Type == batchtype.Deposit || batch.Type == batchtype.Genesis {
+		return nil
+	}
+
+	commitments, err := storage.GetCommitmentsByBatchID(batch.ID)
+	if err != nil {
+		return err
+	}
+	for i := range commitments {
+		txs, err := storage.GetTransactionsByCommitmentID(commitments[i].GetCommitmentBase().ID)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < txs.Len(); j++ {
+			err = write(newTransactionRecord(txs.At(j), batch, status))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeDepositRecords(storage *st.Storage, batch *models.Batch, _ batchstatus.BatchStatus, write writeRecordFunc) error {
+	if batch.Type != batchtype.Deposit {
+		return nil
+	}
+
+	commitments, err := storage.GetCommitmentsByBatchID(batch.ID)
+	if err != nil {
+		return err
+	}
+	for i := range commitments {
+		commitment := commitments[i].ToDepositCommitment()
+		for j := range commitment.Deposits {
+			err = write(newDepositRecord(&commitment.ID, &commitment.Deposits[j]))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeTokenRecords(storage *st.Storage, writer recordWriter) (int, error) {
+	tokens, err := storage.GetRegisteredTokens()
+	if err != nil {
+		return 0, err
+	}
+	for i := range tokens {
+		err = writer.Write((*tokenRecord)(&tokens[i]))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(tokens), writer.Flush()
+}
@@ -0,0 +1,260 @@
+package scripts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/models/enums/txstatus"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/testutils"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportBatchDataTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *st.TestStorage
+}
+
+func (s *ExportBatchDataTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *ExportBatchDataTestSuite) SetupTest() {
+	var err error
+	s.storage, err = st.NewTestStorage()
+	s.NoError(err)
+
+	err = s.storage.SetSyncedBlock(25)
+	s.NoError(err)
+
+	s.addTransferBatch(1, ref.Uint32(10))
+	s.addDepositBatch(2, ref.Uint32(20))
+	s.addTransferBatch(3, ref.Uint32(30))
+	s.addTransferBatch(4, nil)
+}
+
+func (s *ExportBatchDataTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_Batches() {
+	output, result := s.export(FormatJSONL, &ExportOptions{}, writeBatchRecords)
+	s.Equal(4, result.Batches)
+	s.Equal(models.NewUint256(4), result.LastBatchID)
+
+	records := decodeJSONL(s.T(), output)
+	s.Len(records, 4)
+	s.Equal("FINALISED", records[1]["Status"])
+	s.Equal("MINED", records[2]["Status"])
+	s.Equal("SUBMITTED", records[3]["Status"])
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_Transactions() {
+	output, result := s.export(FormatJSONL, &ExportOptions{}, writeTransactionRecords)
+	s.Equal(3, result.Records)
+
+	var record transactionRecord
+	err := json.Unmarshal(bytes.Split(output, []byte{'\n'})[0], &record)
+	s.NoError(err)
+	s.Equal(txstatus.Finalised, record.Status)
+	s.Equal(models.MakeUint256(1), record.BatchID)
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_Deposits() {
+	output, result := s.export(FormatJSONL, &ExportOptions{}, writeDepositRecords)
+	s.Equal(2, result.Records)
+
+	var record depositRecord
+	err := json.Unmarshal(bytes.Split(output, []byte{'\n'})[1], &record)
+	s.NoError(err)
+	s.Equal(models.MakeUint256(2), record.BatchID)
+	s.Equal(models.MakeUint256(1), record.DepositIndex)
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_CSV() {
+	output, _ := s.export(FormatCSV, &ExportOptions{}, writeCommitmentRecords)
+
+	rows, err := csv.NewReader(bytes.NewReader(output)).ReadAll()
+	s.NoError(err)
+	s.Len(rows, 5)
+	s.Equal(commitmentHeader, rows[0])
+	s.Equal("DEPOSIT", rows[2][2])
+	s.Equal("2", rows[2][len(commitmentHeader)-1])
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_BatchAndBlockFilters() {
+	_, result := s.export(FormatJSONL, &ExportOptions{FromBatch: models.NewUint256(2), ToBatch: models.NewUint256(3)}, writeBatchRecords)
+	s.Equal(2, result.Batches)
+	s.Equal(models.NewUint256(3), result.LastBatchID)
+
+	_, result = s.export(FormatJSONL, &ExportOptions{FromBlock: ref.Uint32(15)}, writeBatchRecords)
+	s.Equal(2, result.Batches)
+	s.Equal(models.NewUint256(3), result.LastBatchID)
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_SinceBatchStopsAtFirstNotFinalisedBatch() {
+	_, result := s.export(FormatJSONL, &ExportOptions{SinceBatch: models.NewUint256(1)}, writeBatchRecords)
+	s.Equal(1, result.Batches)
+	s.Equal(models.NewUint256(2), result.LastBatchID)
+
+	_, result = s.export(FormatJSONL, &ExportOptions{SinceBatch: models.NewUint256(2)}, writeBatchRecords)
+	s.Equal(0, result.Batches)
+	s.Nil(result.LastBatchID)
+}
+
+func (s *ExportBatchDataTestSuite) TestExportOptions_SinceBatchConflictsWithFromBatch() {
+	opts := &ExportOptions{FromBatch: models.NewUint256(1), SinceBatch: models.NewUint256(1)}
+	s.Error(opts.validate())
+}
+
+func (s *ExportBatchDataTestSuite) TestOpenExportStorage_LoadsBackups() {
+	dir, err := os.MkdirTemp("", "export_batch_data_test")
+	s.NoError(err)
+	defer func() {
+		s.NoError(os.RemoveAll(dir))
+	}()
+
+	backupFile := filepath.Join(dir, "full.bak")
+	file, err := os.Create(backupFile)
+	s.NoError(err)
+	_, err = s.storage.Backup(file, 0)
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	storage, closeStorage, err := openExportStorage(&config.Config{Badger: &config.BadgerConfig{}}, []string{backupFile})
+	s.NoError(err)
+
+	var output bytes.Buffer
+	writer, err := newRecordWriter(FormatJSONL, &output, batchHeader, true)
+	s.NoError(err)
+	result, err := exportBatchRecords(storage, writer, &ExportOptions{}, writeBatchRecords)
+	s.NoError(err)
+	s.Equal(4, result.Batches)
+
+	s.NoError(closeStorage())
+}
+
+func (s *ExportBatchDataTestSuite) TestLastExportedBatch() {
+	dir, err := os.MkdirTemp("", "export_batch_data_test")
+	s.NoError(err)
+	defer func() {
+		s.NoError(os.RemoveAll(dir))
+	}()
+	filePath := filepath.Join(dir, "batches.jsonl")
+
+	batchID, err := readLastExportedBatch(filePath)
+	s.NoError(err)
+	s.Nil(batchID)
+
+	err = writeLastExportedBatch(filePath, models.NewUint256(12))
+	s.NoError(err)
+
+	batchID, err = readLastExportedBatch(filePath)
+	s.NoError(err)
+	s.Equal(models.NewUint256(12), batchID)
+	s.NoFileExists(filePath + lastExportedBatchFileSuffix + ".tmp")
+}
+
+func (s *ExportBatchDataTestSuite) TestExportOptions_IncrementalConflictsWithFromBatch() {
+	opts := &ExportOptions{FromBatch: models.NewUint256(1), Incremental: true}
+	s.Error(opts.validate())
+}
+
+func (s *ExportBatchDataTestSuite) TestExportBatchRecords_IncrementalStopsAtFirstNotFinalisedBatch() {
+	_, result := s.export(FormatJSONL, &ExportOptions{Incremental: true}, writeBatchRecords)
+	s.Equal(2, result.Batches)
+	s.Equal(models.NewUint256(2), result.LastBatchID)
+}
+
+func (s *ExportBatchDataTestSuite) export(format string, opts *ExportOptions, recordsFunc batchRecordsFunc) ([]byte, *exportResult) {
+	var output bytes.Buffer
+	writer, err := newRecordWriter(format, &output, commitmentHeader, true)
+	s.NoError(err)
+
+	result, err := exportBatchRecords(s.storage.Storage, writer, opts, recordsFunc)
+	s.NoError(err)
+	return output.Bytes(), result
+}
+
+func (s *ExportBatchDataTestSuite) addTransferBatch(batchID uint64, finalisationBlock *uint32) {
+	commitmentID := s.addBatch(batchID, batchtype.Transfer, finalisationBlock)
+	err := s.storage.AddCommitment(&models.TxCommitment{
+		CommitmentBase: models.CommitmentBase{
+			ID:            commitmentID,
+			Type:          batchtype.Transfer,
+			PostStateRoot: utils.RandomHash(),
+		},
+		CombinedSignature: models.MakeRandomSignature(),
+		BodyHash:          utils.NewRandomHash(),
+	})
+	s.NoError(err)
+
+	tx := testutils.NewTransfer(1, 2, batchID, 10)
+	tx.CommitmentSlot = models.NewCommitmentSlot(commitmentID, 0)
+	err = s.storage.AddTransaction(tx)
+	s.NoError(err)
+}
+
+func (s *ExportBatchDataTestSuite) addDepositBatch(batchID uint64, finalisationBlock *uint32) {
+	commitmentID := s.addBatch(batchID, batchtype.Deposit, finalisationBlock)
+
+	deposits := make([]models.PendingDeposit, 2)
+	for i := range deposits {
+		deposits[i] = models.PendingDeposit{
+			ID:         models.DepositID{SubtreeID: models.MakeUint256(1), DepositIndex: models.MakeUint256(uint64(i))},
+			ToPubKeyID: 1,
+			TokenID:    models.MakeUint256(0),
+			L2Amount:   models.MakeUint256(100),
+		}
+	}
+	err := s.storage.AddCommitment(&models.DepositCommitment{
+		CommitmentBase: models.CommitmentBase{
+			ID:            commitmentID,
+			Type:          batchtype.Deposit,
+			PostStateRoot: utils.RandomHash(),
+		},
+		SubtreeID:   models.MakeUint256(1),
+		SubtreeRoot: utils.RandomHash(),
+		Deposits:    deposits,
+	})
+	s.NoError(err)
+}
+
+func (s *ExportBatchDataTestSuite) addBatch(batchID uint64, batchType batchtype.BatchType, finalisationBlock *uint32) models.CommitmentID {
+	err := s.storage.AddBatch(&models.Batch{
+		ID:                models.MakeUint256(batchID),
+		Type:              batchType,
+		TransactionHash:   utils.RandomHash(),
+		Hash:              utils.NewRandomHash(),
+		FinalisationBlock: finalisationBlock,
+	})
+	s.NoError(err)
+	return models.CommitmentID{BatchID: models.MakeUint256(batchID)}
+}
+
+func decodeJSONL(t *testing.T, data []byte) []map[string]interface{} {
+	records := make([]map[string]interface{}, 0, 4)
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte{'\n'}) {
+		var record map[string]interface{}
+		err := json.Unmarshal(line, &record)
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExportBatchDataTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportBatchDataTestSuite))
+}
@@ -0,0 +1,316 @@
+package scripts
+
+import (
+	"strconv"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/models/enums/txstatus"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// exportRecord is a single line of a JSONL or CSV export. Field names of the record
+// structs are used as JSON keys, csvRow must list the values in the order of the header.
+type exportRecord interface {
+	csvRow() []string
+}
+
+var (
+	batchHeader = []string{
+		"ID", "Type", "Status", "Hash", "TransactionHash", "FinalisationBlock", "MinedTime", "AccountTreeRoot", "PrevStateRoot",
+	}
+	commitmentHeader = []string{
+		"BatchID", "IndexInBatch", "Type", "PostStateRoot", "FeeReceiver", "CombinedSignature", "BodyHash",
+		"SpokeID", "TokenID", "Amount", "WithdrawRoot", "SubtreeID", "SubtreeRoot", "DepositCount",
+	}
+	transactionHeader = []string{
+		"Hash", "Type", "Status", "BatchID", "IndexInBatch", "IndexInCommitment", "BatchHash", "MinedTime",
+		"FromStateID", "ToStateID", "ToPublicKey", "SpokeID", "Amount", "Fee", "Nonce", "Signature", "ReceiveTime",
+	}
+	depositHeader = []string{
+		"BatchID", "IndexInBatch", "SubtreeID", "DepositIndex", "ToPubKeyID", "TokenID", "L2Amount",
+	}
+	tokenHeader = []string{"ID", "Contract"}
+)
+
+type batchRecord struct {
+	ID                models.Uint256
+	Type              batchtype.BatchType
+	Status            batchstatus.BatchStatus
+	Hash              *common.Hash
+	TransactionHash   common.Hash
+	FinalisationBlock *uint32
+	MinedTime         *models.Timestamp
+	AccountTreeRoot   *common.Hash
+	PrevStateRoot     *common.Hash
+}
+
+func newBatchRecord(batch *models.Batch, status batchstatus.BatchStatus) *batchRecord {
+	return &batchRecord{
+		ID:                batch.ID,
+		Type:              batch.Type,
+		Status:            status,
+		Hash:              batch.Hash,
+		TransactionHash:   batch.TransactionHash,
+		FinalisationBlock: batch.FinalisationBlock,
+		MinedTime:         batch.MinedTime,
+		AccountTreeRoot:   batch.AccountTreeRoot,
+		PrevStateRoot:     batch.PrevStateRoot,
+	}
+}
+
+func (r *batchRecord) csvRow() []string {
+	return []string{
+		r.ID.String(),
+		r.Type.String(),
+		r.Status.String(),
+		formatHash(r.Hash),
+		r.TransactionHash.String(),
+		formatUint32(r.FinalisationBlock),
+		formatTimestamp(r.MinedTime),
+		formatHash(r.AccountTreeRoot),
+		formatHash(r.PrevStateRoot),
+	}
+}
+
+// commitmentRecord holds the fields of all commitment types, the ones which don't apply to Type are empty
+type commitmentRecord struct {
+	BatchID           models.Uint256
+	IndexInBatch      uint8
+	Type              batchtype.BatchType
+	PostStateRoot     common.Hash
+	FeeReceiver       *uint32
+	CombinedSignature *models.Signature
+	BodyHash          *common.Hash
+	SpokeID           *uint32
+	TokenID           *models.Uint256
+	Amount            *models.Uint256
+	WithdrawRoot      *common.Hash
+	SubtreeID         *models.Uint256
+	SubtreeRoot       *common.Hash
+	DepositCount      *int
+}
+
+func newCommitmentRecord(commitment models.Commitment) *commitmentRecord {
+	base := commitment.GetCommitmentBase()
+	record := &commitmentRecord{
+		BatchID:       base.ID.BatchID,
+		IndexInBatch:  base.ID.IndexInBatch,
+		Type:          base.Type,
+		PostStateRoot: base.PostStateRoot,
+	}
+
+	switch base.Type {
+	case batchtype.Transfer, batchtype.Create2Transfer:
+		txCommitment := commitment.ToTxCommitment()
+		record.FeeReceiver = &txCommitment.FeeReceiver
+		record.CombinedSignature = &txCommitment.CombinedSignature
+		record.BodyHash = txCommitment.BodyHash
+	case batchtype.MassMigration:
+		mmCommitment := commitment.ToMMCommitment()
+		record.CombinedSignature = &mmCommitment.CombinedSignature
+		record.BodyHash = mmCommitment.BodyHash
+		record.WithdrawRoot = &mmCommitment.WithdrawRoot
+		if mmCommitment.Meta != nil {
+			record.FeeReceiver = &mmCommitment.Meta.FeeReceiver
+			record.SpokeID = &mmCommitment.Meta.SpokeID
+			record.TokenID = &mmCommitment.Meta.TokenID
+			record.Amount = &mmCommitment.Meta.Amount
+		}
+	case batchtype.Deposit:
+		depositCommitment := commitment.ToDepositCommitment()
+		depositCount := len(depositCommitment.Deposits)
+		record.SubtreeID = &depositCommitment.SubtreeID
+		record.SubtreeRoot = &depositCommitment.SubtreeRoot
+		record.DepositCount = &depositCount
+	case batchtype.Genesis:
+	}
+	return record
+}
+
+func (r *commitmentRecord) csvRow() []string {
+	var combinedSignature string
+	if r.CombinedSignature != nil {
+		combinedSignature = r.CombinedSignature.String()
+	}
+	var depositCount string
+	if r.DepositCount != nil {
+		depositCount = strconv.Itoa(*r.DepositCount)
+	}
+
+	return []string{
+		r.BatchID.String(),
+		strconv.FormatUint(uint64(r.IndexInBatch), 10),
+		r.Type.String(),
+		r.PostStateRoot.String(),
+		formatUint32(r.FeeReceiver),
+		combinedSignature,
+		formatHash(r.BodyHash),
+		formatUint32(r.SpokeID),
+		formatUint256(r.TokenID),
+		formatUint256(r.Amount),
+		formatHash(r.WithdrawRoot),
+		formatUint256(r.SubtreeID),
+		formatHash(r.SubtreeRoot),
+		depositCount,
+	}
+}
+
+type transactionRecord struct {
+	Hash              common.Hash
+	Type              txtype.TransactionType
+	Status            txstatus.TransactionStatus
+	BatchID           models.Uint256
+	IndexInBatch      uint8
+	IndexInCommitment uint8
+	BatchHash         *common.Hash
+	MinedTime         *models.Timestamp
+	FromStateID       uint32
+	ToStateID         *uint32
+	ToPublicKey       *models.PublicKey
+	SpokeID           *uint32
+	Amount            models.Uint256
+	Fee               models.Uint256
+	Nonce             models.Uint256
+	Signature         models.Signature
+	ReceiveTime       *models.Timestamp
+}
+
+func newTransactionRecord(tx models.GenericTransaction, batch *models.Batch, status batchstatus.BatchStatus) *transactionRecord {
+	base := tx.GetBase()
+	record := &transactionRecord{
+		Hash:              base.Hash,
+		Type:              base.TxType,
+		Status:            batchToTxStatus(status),
+		BatchID:           base.CommitmentSlot.BatchID,
+		IndexInBatch:      base.CommitmentSlot.IndexInBatch,
+		IndexInCommitment: base.CommitmentSlot.IndexInCommitment,
+		BatchHash:         batch.Hash,
+		MinedTime:         batch.MinedTime,
+		FromStateID:       base.FromStateID,
+		ToStateID:         tx.GetToStateID(),
+		Amount:            base.Amount,
+		Fee:               base.Fee,
+		Nonce:             base.Nonce,
+		Signature:         base.Signature,
+		ReceiveTime:       base.ReceiveTime,
+	}
+
+	switch base.TxType {
+	case txtype.Create2Transfer:
+		record.ToPublicKey = &tx.ToCreate2Transfer().ToPublicKey
+	case txtype.MassMigration:
+		record.SpokeID = &tx.ToMassMigration().SpokeID
+	case txtype.Transfer:
+	}
+	return record
+}
+
+func (r *transactionRecord) csvRow() []string {
+	var toPublicKey string
+	if r.ToPublicKey != nil {
+		toPublicKey = r.ToPublicKey.String()
+	}
+
+	return []string{
+		r.Hash.String(),
+		r.Type.String(),
+		r.Status.String(),
+		r.BatchID.String(),
+		strconv.FormatUint(uint64(r.IndexInBatch), 10),
+		strconv.FormatUint(uint64(r.IndexInCommitment), 10),
+		formatHash(r.BatchHash),
+		formatTimestamp(r.MinedTime),
+		strconv.FormatUint(uint64(r.FromStateID), 10),
+		formatUint32(r.ToStateID),
+		toPublicKey,
+		formatUint32(r.SpokeID),
+		r.Amount.String(),
+		r.Fee.String(),
+		r.Nonce.String(),
+		r.Signature.String(),
+		formatTimestamp(r.ReceiveTime),
+	}
+}
+
+type depositRecord struct {
+	BatchID      models.Uint256
+	IndexInBatch uint8
+	SubtreeID    models.Uint256
+	DepositIndex models.Uint256
+	ToPubKeyID   uint32
+	TokenID      models.Uint256
+	L2Amount     models.Uint256
+}
+
+func newDepositRecord(commitmentID *models.CommitmentID, deposit *models.PendingDeposit) *depositRecord {
+	return &depositRecord{
+		BatchID:      commitmentID.BatchID,
+		IndexInBatch: commitmentID.IndexInBatch,
+		SubtreeID:    deposit.ID.SubtreeID,
+		DepositIndex: deposit.ID.DepositIndex,
+		ToPubKeyID:   deposit.ToPubKeyID,
+		TokenID:      deposit.TokenID,
+		L2Amount:     deposit.L2Amount,
+	}
+}
+
+func (r *depositRecord) csvRow() []string {
+	return []string{
+		r.BatchID.String(),
+		strconv.FormatUint(uint64(r.IndexInBatch), 10),
+		r.SubtreeID.String(),
+		r.DepositIndex.String(),
+		strconv.FormatUint(uint64(r.ToPubKeyID), 10),
+		r.TokenID.String(),
+		r.L2Amount.String(),
+	}
+}
+
+type tokenRecord models.RegisteredToken
+
+func (r *tokenRecord) csvRow() []string {
+	return []string{r.ID.String(), r.Contract.String()}
+}
+
+func batchToTxStatus(status batchstatus.BatchStatus) txstatus.TransactionStatus {
+	switch status {
+	case batchstatus.Mined:
+		return txstatus.Mined
+	case batchstatus.Finalised:
+		return txstatus.Finalised
+	case batchstatus.Submitted:
+	}
+	return txstatus.Submitted
+}
+
+func formatHash(hash *common.Hash) string {
+	if hash == nil {
+		return ""
+	}
+	return hash.String()
+}
+
+func formatUint32(value *uint32) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*value), 10)
+}
+
+func formatUint256(value *models.Uint256) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+// formatTimestamp uses unix seconds, like the JSON encoding of models.Timestamp
+func formatTimestamp(timestamp *models.Timestamp) string {
+	if timestamp == nil {
+		return ""
+	}
+	return strconv.FormatInt(timestamp.Unix(), 10)
+}
@@ -0,0 +1,53 @@
+package scripts
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExportSnapshot saves a snapshot at the latest finalised batch, which can be imported with `start --from-snapshot`
+func ExportSnapshot(filePath string) (err error) {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	storage, err := st.NewStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := storage.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return exportSnapshot(storage, file)
+}
+
+func exportSnapshot(storage *st.Storage, file *os.File) error {
+	writer := bufio.NewWriter(file)
+	header, err := storage.ExportSnapshot(writer)
+	if err != nil {
+		return err
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("exported snapshot at batch #%d", header.Batch.ID.Uint64())
+	return nil
+}
@@ -0,0 +1,70 @@
+package scripts
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+var ExportFormats = []string{FormatJSONL, FormatCSV}
+
+// recordWriter streams records to the export file, nothing is buffered apart from the underlying bufio.Writer
+type recordWriter interface {
+	Write(record exportRecord) error
+	Flush() error
+}
+
+// newRecordWriter writes the CSV header only when writeHeader is set, so that appending to an existing export
+// doesn't repeat it
+func newRecordWriter(format string, output io.Writer, header []string, writeHeader bool) (recordWriter, error) {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(output)}, nil
+	case FormatCSV:
+		writer := &csvWriter{writer: csv.NewWriter(output)}
+		if writeHeader {
+			err := writer.writer.Write(header)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return writer, nil
+	default:
+		return nil, fmt.Errorf("invalid export format %q, supported: %v", format, ExportFormats)
+	}
+}
+
+type jsonlWriter struct {
+	writer *bufio.Writer
+}
+
+func (w *jsonlWriter) Write(record exportRecord) error {
+	err := writeData(w.writer, record)
+	if err != nil {
+		return err
+	}
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(record exportRecord) error {
+	return w.writer.Write(record.csvRow())
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,142 @@
+package scripts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	genesisFileHeader    = "# generated by `commander generateGenesis`, remember to update supply of tokens before deploying\n---\n"
+	maxReportedPubKeyIDs = 10
+)
+
+var ErrUnresolvedPublicKeys = fmt.Errorf("state leaves reference public key IDs missing from the accounts export")
+
+// GenerateGenesis converts the output of `export -type state` and `export -type accounts` into a genesis file
+// which can be used to deploy a new rollup carrying over the balances of the exported one
+func GenerateGenesis(stateFile, accountsFile, genesisFile string, mergeStates bool) error {
+	leaves := make([]models.StateLeaf, 0, 1024)
+	err := readJSONFile(stateFile, &leaves)
+	if err != nil {
+		return err
+	}
+	accounts := make([]models.AccountLeaf, 0, 1024)
+	err = readJSONFile(accountsFile, &accounts)
+	if err != nil {
+		return err
+	}
+
+	genesisAccounts, err := generateGenesisAccounts(leaves, accounts, mergeStates)
+	if err != nil {
+		return err
+	}
+
+	yamlData, err := yaml.Marshal(genesisAccounts)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(genesisFile, append([]byte(genesisFileHeader), yamlData...), 0o600)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("generated %d genesis accounts from %d state leaves", len(genesisAccounts), len(leaves))
+	for tokenID, total := range genesisTotalsByToken(genesisAccounts) {
+		log.Infof("total genesis balance of token %s: %s", tokenID, total.String())
+	}
+	return nil
+}
+
+// generateGenesisAccounts resolves public keys of state leaves. Every genesis account registers its public key
+// on deployment, so pubkey IDs and state IDs are renumbered to the position of the account in the genesis file.
+// Nonces start at 0 on the new rollup.
+func generateGenesisAccounts(
+	leaves []models.StateLeaf,
+	accounts []models.AccountLeaf,
+	mergeStates bool,
+) ([]models.GenesisAccount, error) {
+	publicKeys := make(map[uint32]models.PublicKey, len(accounts))
+	for i := range accounts {
+		publicKeys[accounts[i].PubKeyID] = accounts[i].PublicKey
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].StateID < leaves[j].StateID
+	})
+
+	type stateKey struct {
+		publicKey models.PublicKey
+		tokenID   models.Uint256
+	}
+	mergedStates := make(map[stateKey]int)
+	unresolvedPubKeyIDs := make([]uint32, 0)
+	genesisAccounts := make([]models.GenesisAccount, 0, len(leaves))
+
+	for i := range leaves {
+		leaf := &leaves[i]
+		publicKey, ok := publicKeys[leaf.PubKeyID]
+		if !ok {
+			unresolvedPubKeyIDs = append(unresolvedPubKeyIDs, leaf.PubKeyID)
+			continue
+		}
+
+		key := stateKey{publicKey: publicKey, tokenID: leaf.TokenID}
+		if index, ok := mergedStates[key]; ok && mergeStates {
+			balance := &genesisAccounts[index].State.Balance
+			*balance = *balance.Add(&leaf.Balance)
+			continue
+		}
+
+		index := len(genesisAccounts)
+		mergedStates[key] = index
+		genesisAccounts = append(genesisAccounts, models.GenesisAccount{
+			PublicKey: publicKey,
+			StateID:   uint32(index),
+			State: models.UserState{
+				PubKeyID: uint32(index),
+				TokenID:  leaf.TokenID,
+				Balance:  leaf.Balance,
+				Nonce:    models.MakeUint256(0),
+			},
+		})
+	}
+
+	if len(unresolvedPubKeyIDs) > 0 {
+		shownIDs := unresolvedPubKeyIDs
+		if len(shownIDs) > maxReportedPubKeyIDs {
+			shownIDs = shownIDs[:maxReportedPubKeyIDs]
+		}
+		return nil, errors.WithMessagef(
+			ErrUnresolvedPublicKeys,
+			"%d state leaves, first pubkey IDs %v",
+			len(unresolvedPubKeyIDs),
+			shownIDs,
+		)
+	}
+	return genesisAccounts, nil
+}
+
+func genesisTotalsByToken(accounts []models.GenesisAccount) map[string]models.Uint256 {
+	totals := make(map[string]models.Uint256)
+	for i := range accounts {
+		tokenID := accounts[i].State.TokenID.String()
+		total := totals[tokenID]
+		totals[tokenID] = *total.Add(&accounts[i].State.Balance)
+	}
+	return totals
+}
+
+func readJSONFile(filePath string, data interface{}) error {
+	bytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, data)
+}
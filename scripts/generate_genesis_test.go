@@ -0,0 +1,96 @@
+package scripts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	genesisTestAccounts = []models.AccountLeaf{
+		{PubKeyID: 0, PublicKey: models.PublicKey{1}},
+		{PubKeyID: 1, PublicKey: models.PublicKey{2}},
+		{PubKeyID: 2, PublicKey: models.PublicKey{1}},
+	}
+	genesisTestLeaves = []models.StateLeaf{
+		makeGenesisTestLeaf(0, 0, 0, 100),
+		makeGenesisTestLeaf(1, 1, 0, 200),
+		makeGenesisTestLeaf(2, 2, 0, 300),
+		makeGenesisTestLeaf(3, 0, 1, 400),
+	}
+)
+
+func TestGenerateGenesis(t *testing.T) {
+	dir, err := os.MkdirTemp("", "generate_genesis_test")
+	require.NoError(t, err)
+	defer func() {
+		err = os.RemoveAll(dir)
+		require.NoError(t, err)
+	}()
+
+	stateFile := writeJSONFile(t, dir, "state.json", genesisTestLeaves)
+	accountsFile := writeJSONFile(t, dir, "accounts.json", genesisTestAccounts)
+	genesisFile := filepath.Join(dir, "genesis.yaml")
+
+	err = GenerateGenesis(stateFile, accountsFile, genesisFile, false)
+	require.NoError(t, err)
+
+	bytes, err := os.ReadFile(genesisFile)
+	require.NoError(t, err)
+	var genesisAccounts []models.GenesisAccount
+	err = yaml.Unmarshal(bytes, &genesisAccounts)
+	require.NoError(t, err)
+
+	require.Len(t, genesisAccounts, 4)
+	for i := range genesisAccounts {
+		require.EqualValues(t, i, genesisAccounts[i].StateID)
+		require.EqualValues(t, i, genesisAccounts[i].State.PubKeyID)
+		require.Equal(t, genesisTestLeaves[i].Balance, genesisAccounts[i].State.Balance)
+		require.Equal(t, genesisTestAccounts[genesisTestLeaves[i].PubKeyID].PublicKey, genesisAccounts[i].PublicKey)
+	}
+}
+
+func TestGenerateGenesisAccounts_MergesStatesOfPublicKeyAndToken(t *testing.T) {
+	genesisAccounts, err := generateGenesisAccounts(genesisTestLeaves, genesisTestAccounts, true)
+	require.NoError(t, err)
+
+	require.Len(t, genesisAccounts, 3)
+	require.Equal(t, models.PublicKey{1}, genesisAccounts[0].PublicKey)
+	require.Equal(t, models.MakeUint256(400), genesisAccounts[0].State.Balance)
+	require.Equal(t, models.MakeUint256(200), genesisAccounts[1].State.Balance)
+	require.Equal(t, models.MakeUint256(1), genesisAccounts[2].State.TokenID)
+	require.EqualValues(t, 2, genesisAccounts[2].State.PubKeyID)
+}
+
+func TestGenerateGenesisAccounts_UnresolvedPublicKey(t *testing.T) {
+	leaves := append(genesisTestLeaves, makeGenesisTestLeaf(4, 7, 0, 100))
+	_, err := generateGenesisAccounts(leaves, genesisTestAccounts, false)
+	require.ErrorIs(t, err, ErrUnresolvedPublicKeys)
+}
+
+func makeGenesisTestLeaf(stateID, pubKeyID uint32, tokenID, balance uint64) models.StateLeaf {
+	return models.StateLeaf{
+		StateID: stateID,
+		UserState: models.UserState{
+			PubKeyID: pubKeyID,
+			TokenID:  models.MakeUint256(tokenID),
+			Balance:  models.MakeUint256(balance),
+			Nonce:    models.MakeUint256(3),
+		},
+	}
+}
+
+func writeJSONFile(t *testing.T, dir, name string, data interface{}) string {
+	bytes, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	filePath := filepath.Join(dir, name)
+	err = os.WriteFile(filePath, bytes, 0o600)
+	require.NoError(t, err)
+	return filePath
+}
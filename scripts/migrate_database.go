@@ -0,0 +1,49 @@
+package scripts
+
+import (
+	"github.com/Worldcoin/hubble-commander/config"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// MigrateDatabase runs pending schema migrations of the database in badger.path, the commander must be stopped.
+// With dryRun set it only lists the migrations which would run.
+func MigrateDatabase(dryRun bool) (err error) {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	storage, err := st.NewStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := storage.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	if !dryRun {
+		return storage.MigrateSchema()
+	}
+	return listPendingMigrations(storage)
+}
+
+func listPendingMigrations(storage *st.Storage) error {
+	version, err := storage.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	pending, err := storage.PendingSchemaMigrations()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Database schema version %d, latest version %d", version, st.LatestSchemaVersion())
+	if len(pending) == 0 {
+		log.Info("No migrations to run")
+		return nil
+	}
+	for i := range pending {
+		log.Infof("Would run schema migration #%d %s", pending[i].Version, pending[i].Name)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package scripts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrRestoreTargetNotEmpty = fmt.Errorf("database directory is not empty, remove it before restoring a backup")
+	ErrChainIDMismatch       = fmt.Errorf("chain ID of the restored database does not match the config")
+)
+
+// RestoreDatabase loads a full backup followed by any incremental backups into the badger.path directory
+func RestoreDatabase(backupFiles []string) error {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	return restoreDatabase(cfg, backupFiles)
+}
+
+func restoreDatabase(cfg *config.Config, backupFiles []string) (err error) {
+	targetPath := cfg.Badger.Path
+	isEmpty, err := isEmptyOrMissingDir(targetPath)
+	if err != nil {
+		return err
+	}
+	if !isEmpty {
+		return errors.WithStack(ErrRestoreTargetNotEmpty)
+	}
+
+	restorePath := targetPath + ".restore"
+	err = os.RemoveAll(restorePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(restorePath)
+		}
+	}()
+
+	restoreCfg := *cfg.Badger
+	restoreCfg.Path = restorePath
+	err = loadBackups(&restoreCfg, backupFiles)
+	if err != nil {
+		return err
+	}
+
+	err = verifyRestoredDatabase(&restoreCfg, cfg.Ethereum.ChainID)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(targetPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.MkdirAll(filepath.Dir(targetPath), 0755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.Rename(restorePath, targetPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("Restored %d backup file(s) to %s", len(backupFiles), targetPath)
+	return nil
+}
+
+func loadBackups(cfg *config.BadgerConfig, backupFiles []string) (err error) {
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := database.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for _, backupFile := range backupFiles {
+		err = loadBackup(database, backupFile)
+		if err != nil {
+			return err
+		}
+		log.Infof("Loaded backup %s", backupFile)
+	}
+	return nil
+}
+
+func loadBackup(database *db.Database, backupFile string) error {
+	file, err := os.Open(backupFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return database.Load(file)
+}
+
+func verifyRestoredDatabase(cfg *config.BadgerConfig, chainID uint64) (err error) {
+	storage, err := st.NewStorage(&config.Config{
+		Badger:    cfg,
+		Bootstrap: &config.CommanderBootstrapConfig{},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := storage.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	chainState, err := storage.GetChainState()
+	if err != nil {
+		return err
+	}
+	if chainState.ChainID != models.MakeUint256(chainID) {
+		return errors.WithMessagef(
+			ErrChainIDMismatch,
+			"restored %s, configured %d",
+			chainState.ChainID.String(),
+			chainID,
+		)
+	}
+
+	return storage.VerifyTreeRoots()
+}
+
+func isEmptyOrMissingDir(path string) (bool, error) {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return len(entries) == 0, nil
+}
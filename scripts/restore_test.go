@@ -0,0 +1,117 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const restoreTestChainID = 1337
+
+type RestoreDatabaseTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	dir        string
+	backupFile string
+	cfg        *config.Config
+}
+
+func (s *RestoreDatabaseTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *RestoreDatabaseTestSuite) SetupTest() {
+	var err error
+	s.dir, err = os.MkdirTemp("", "restore_database_test")
+	s.NoError(err)
+
+	s.backupFile = filepath.Join(s.dir, "full.bak")
+	s.createBackup()
+
+	s.cfg = &config.Config{
+		Badger:   &config.BadgerConfig{Path: filepath.Join(s.dir, "data", "hubble")},
+		Ethereum: &config.EthereumConfig{ChainID: restoreTestChainID},
+	}
+}
+
+func (s *RestoreDatabaseTestSuite) TearDownTest() {
+	err := os.RemoveAll(s.dir)
+	s.NoError(err)
+}
+
+func (s *RestoreDatabaseTestSuite) TestRestoreDatabase() {
+	err := restoreDatabase(s.cfg, []string{s.backupFile})
+	s.NoError(err)
+
+	storage, err := st.NewStorage(&config.Config{Badger: s.cfg.Badger, Bootstrap: &config.CommanderBootstrapConfig{}})
+	s.NoError(err)
+	defer func() {
+		s.NoError(storage.Close())
+	}()
+
+	leaf, err := storage.StateTree.Leaf(0)
+	s.NoError(err)
+	s.Equal(models.MakeUint256(1000), leaf.Balance)
+}
+
+func (s *RestoreDatabaseTestSuite) TestRestoreDatabase_ChainIDMismatch() {
+	s.cfg.Ethereum.ChainID = restoreTestChainID + 1
+
+	err := restoreDatabase(s.cfg, []string{s.backupFile})
+	s.ErrorIs(err, ErrChainIDMismatch)
+
+	s.NoDirExists(s.cfg.Badger.Path)
+	s.NoDirExists(s.cfg.Badger.Path + ".restore")
+}
+
+func (s *RestoreDatabaseTestSuite) TestRestoreDatabase_TargetNotEmpty() {
+	err := os.MkdirAll(s.cfg.Badger.Path, 0755)
+	s.NoError(err)
+	err = os.WriteFile(filepath.Join(s.cfg.Badger.Path, "MANIFEST"), []byte{1}, 0600)
+	s.NoError(err)
+
+	err = restoreDatabase(s.cfg, []string{s.backupFile})
+	s.ErrorIs(err, ErrRestoreTargetNotEmpty)
+}
+
+func (s *RestoreDatabaseTestSuite) createBackup() {
+	storage, err := st.NewTestStorage()
+	s.NoError(err)
+	defer func() {
+		s.NoError(storage.Teardown())
+	}()
+
+	err = storage.SetChainState(&models.ChainState{
+		ChainID: models.MakeUint256(restoreTestChainID),
+		Rollup:  utils.RandomAddress(),
+	})
+	s.NoError(err)
+
+	_, err = storage.StateTree.Set(0, &models.UserState{
+		PubKeyID: 0,
+		TokenID:  models.MakeUint256(0),
+		Balance:  models.MakeUint256(1000),
+		Nonce:    models.MakeUint256(0),
+	})
+	s.NoError(err)
+
+	file, err := os.Create(s.backupFile)
+	s.NoError(err)
+	defer func() {
+		s.NoError(file.Close())
+	}()
+
+	_, err = storage.Backup(file, 0)
+	s.NoError(err)
+}
+
+func TestRestoreDatabaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RestoreDatabaseTestSuite))
+}
@@ -0,0 +1,43 @@
+package scripts
+
+import (
+	"github.com/Worldcoin/hubble-commander/config"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	st "github.com/Worldcoin/hubble-commander/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// VerifyDatabase checks the integrity of the database in badger.path, the commander must be stopped
+func VerifyDatabase(repair bool) (report *dto.DatabaseReport, err error) {
+	cfg := config.GetCommanderConfigAndSetupLogger()
+	storage, err := st.NewStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		closeErr := storage.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return verifyDatabase(storage, repair)
+}
+
+func verifyDatabase(storage *st.Storage, repair bool) (*dto.DatabaseReport, error) {
+	report, err := storage.VerifyDatabase(repair)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"stateTreeRootMismatch":   report.StateTreeRoot != nil,
+		"accountTreeRootMismatch": report.AccountTreeRoot != nil,
+		"orphanedTxs":             len(report.OrphanedTxs),
+		"pendingStates":           len(report.PendingStates),
+		"pendingPubkeyBalances":   len(report.PendingPubkeyBalances),
+		"indexes":                 len(report.Indexes),
+		"repaired":                report.Repaired,
+	}).Info("Verified the database")
+	return report, nil
+}
@@ -1,15 +1,15 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (s *Storage) GetFirstPubKeyID(publicKey *models.PublicKey) (*uint32, error) {
 	var account models.AccountLeaf
 	err := s.database.Badger.FindOneUsingIndex(&account, *publicKey, "PublicKey")
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("pub key id"))
 	}
 	if err != nil {
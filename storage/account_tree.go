@@ -4,11 +4,9 @@ import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/utils/merkletree"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 const (
@@ -60,7 +58,7 @@ func (s *AccountTree) Root() (*common.Hash, error) {
 func (s *AccountTree) Leaf(pubKeyID uint32) (*models.AccountLeaf, error) {
 	var leaf models.AccountLeaf
 	err := s.database.Badger.Get(pubKeyID, &leaf)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("account leaf"))
 	}
 	if err != nil {
@@ -73,7 +71,7 @@ func (s *AccountTree) Leaves(publicKey *models.PublicKey) ([]models.AccountLeaf,
 	accounts := make([]models.AccountLeaf, 0, 1)
 	err := s.database.Badger.Find(
 		&accounts,
-		bh.Where("PublicKey").Eq(*publicKey).Index("PublicKey"),
+		db.Where("PublicKey").Eq(*publicKey).Index("PublicKey"),
 	)
 	if err != nil {
 		return nil, err
@@ -91,7 +89,7 @@ func (s *AccountTree) SetSingle(leaf *models.AccountLeaf) error {
 
 	return s.executeInTransaction(TxOptions{}, func(accountTree *AccountTree) error {
 		_, err := accountTree.unsafeSet(leaf)
-		if errors.Is(err, bh.ErrKeyExists) {
+		if errors.Is(err, db.ErrKeyExists) {
 			return errors.WithStack(NewAccountAlreadyExistsError(leaf))
 		}
 		return err
@@ -113,7 +111,7 @@ func (s *AccountTree) SetInBatch(leaves ...models.AccountLeaf) error {
 				return errors.WithStack(NewInvalidPubKeyIDError(leaves[i].PubKeyID))
 			}
 			_, err := accountTree.unsafeSet(&leaves[i])
-			if errors.Is(err, bh.ErrKeyExists) {
+			if errors.Is(err, db.ErrKeyExists) {
 				return errors.WithStack(NewAccountBatchAlreadyExistsError(leaves))
 			}
 			if err != nil {
@@ -146,7 +144,7 @@ func (s *AccountTree) unsafeSet(leaf *models.AccountLeaf) (models.Witness, error
 
 func (s *AccountTree) NextBatchAccountPubKeyID() (*uint32, error) {
 	nextPubKeyID := uint32(AccountBatchOffset)
-	err := s.database.Badger.Iterator(models.AccountLeafPrefix, db.ReverseKeyIteratorOpts, func(item *bdg.Item) (finish bool, err error) {
+	err := s.database.Badger.Iterator(models.AccountLeafPrefix, db.ReverseKeyIteratorOpts, func(item db.Item) (finish bool, err error) {
 		var account models.AccountLeaf
 		err = item.Value(account.SetBytes)
 		if err != nil {
@@ -165,8 +163,60 @@ func (s *AccountTree) NextBatchAccountPubKeyID() (*uint32, error) {
 	return &nextPubKeyID, nil
 }
 
+// RemoveBatchAccountsFrom deletes the batch account leaves with pub key IDs from the given one on. It drops
+// reserved pub key IDs which did not end up registered in the AccountRegistry so they can be synced again.
+func (s *AccountTree) RemoveBatchAccountsFrom(pubKeyID uint32) (removedCount int, err error) {
+	if pubKeyID < AccountBatchOffset {
+		return 0, errors.WithStack(NewInvalidPubKeyIDError(pubKeyID))
+	}
+
+	err = s.executeInTransaction(TxOptions{}, func(accountTree *AccountTree) error {
+		pubKeyIDs, err := accountTree.batchAccountPubKeyIDsFrom(pubKeyID)
+		if err != nil {
+			return err
+		}
+		for i := range pubKeyIDs {
+			err = accountTree.database.Badger.Delete(pubKeyIDs[i], models.AccountLeaf{})
+			if err != nil {
+				return err
+			}
+			path := models.MakeMerklePathFromLeafID(pubKeyIDs[i])
+			_, _, err = accountTree.merkleTree.SetNode(&path, merkletree.GetZeroHash(0))
+			if err != nil {
+				return err
+			}
+		}
+		removedCount = len(pubKeyIDs)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removedCount, nil
+}
+
+func (s *AccountTree) batchAccountPubKeyIDsFrom(pubKeyID uint32) ([]uint32, error) {
+	pubKeyIDs := make([]uint32, 0, AccountBatchSize)
+	err := s.database.Badger.Iterator(models.AccountLeafPrefix, db.ReverseKeyIteratorOpts, func(item db.Item) (finish bool, err error) {
+		var account models.AccountLeaf
+		err = item.Value(account.SetBytes)
+		if err != nil {
+			return false, err
+		}
+		if account.PubKeyID < pubKeyID {
+			return true, nil
+		}
+		pubKeyIDs = append(pubKeyIDs, account.PubKeyID)
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return pubKeyIDs, nil
+}
+
 func (s *AccountTree) IterateLeaves(action func(stateLeaf *models.AccountLeaf) error) error {
-	err := s.database.Badger.Iterator(models.AccountLeafPrefix, db.PrefetchIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(models.AccountLeafPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
 		var accountLeaf models.AccountLeaf
 		err := item.Value(accountLeaf.SetBytes)
 		if err != nil {
@@ -345,6 +345,55 @@ func (s *AccountTreeTestSuite) TestNextBatchAccountPubKeyID_NoAccounts() {
 	s.EqualValues(AccountBatchOffset, *pubKeyID)
 }
 
+func (s *AccountTreeTestSuite) TestRemoveBatchAccountsFrom() {
+	err := s.storage.AccountTree.SetSingle(s.leaf)
+	s.NoError(err)
+	rootWithSingleAccount, err := s.storage.AccountTree.Root()
+	s.NoError(err)
+
+	leaves := make([]models.AccountLeaf, 2*AccountBatchSize)
+	for i := range leaves {
+		leaves[i] = models.AccountLeaf{
+			PubKeyID:  uint32(i + AccountBatchOffset),
+			PublicKey: models.PublicKey{1, 2, byte(i)},
+		}
+	}
+	err = s.storage.AccountTree.SetBatch(leaves[:AccountBatchSize])
+	s.NoError(err)
+	rootWithFirstBatch, err := s.storage.AccountTree.Root()
+	s.NoError(err)
+	err = s.storage.AccountTree.SetBatch(leaves[AccountBatchSize:])
+	s.NoError(err)
+
+	removedCount, err := s.storage.AccountTree.RemoveBatchAccountsFrom(AccountBatchOffset + AccountBatchSize)
+	s.NoError(err)
+	s.Equal(AccountBatchSize, removedCount)
+
+	root, err := s.storage.AccountTree.Root()
+	s.NoError(err)
+	s.Equal(rootWithFirstBatch, root)
+	_, err = s.storage.AccountTree.Leaf(AccountBatchOffset + AccountBatchSize)
+	s.ErrorIs(err, NewNotFoundError("account leaf"))
+
+	removedCount, err = s.storage.AccountTree.RemoveBatchAccountsFrom(AccountBatchOffset)
+	s.NoError(err)
+	s.Equal(AccountBatchSize, removedCount)
+
+	root, err = s.storage.AccountTree.Root()
+	s.NoError(err)
+	s.Equal(rootWithSingleAccount, root)
+	_, err = s.storage.AccountTree.Leaf(s.leaf.PubKeyID)
+	s.NoError(err)
+}
+
+func (s *AccountTreeTestSuite) TestRemoveBatchAccountsFrom_InvalidPubKeyID() {
+	_, err := s.storage.AccountTree.RemoveBatchAccountsFrom(12)
+
+	var invalidPubKeyIDError *InvalidPubKeyIDError
+	s.ErrorAs(err, &invalidPubKeyIDError)
+	s.EqualValues(12, invalidPubKeyIDError.value)
+}
+
 func (s *AccountTreeTestSuite) TestIterateLeaves_SingleAccount() {
 	err := s.storage.AccountTree.SetSingle(s.leaf)
 	s.NoError(err)
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrStateTreeRootMismatch   = fmt.Errorf("recomputed state tree root does not match the stored one")
+	ErrAccountTreeRootMismatch = fmt.Errorf("recomputed account tree root does not match the stored one")
+)
+
+func (s *Storage) Backup(w io.Writer, since uint64) (uint64, error) {
+	return s.database.Badger.Backup(w, since)
+}
+
+func (s *Storage) Load(r io.Reader) error {
+	return s.database.Badger.Load(r)
+}
+
+// VerifyTreeRoots rebuilds the state and account trees from their leaves and compares
+// the resulting roots with the stored ones
+func (s *Storage) VerifyTreeRoots() error {
+	stateRoot, accountRoot, err := s.recomputeTreeRoots()
+	if err != nil {
+		return err
+	}
+
+	err = compareRoots(s.StateTree.Root, stateRoot, ErrStateTreeRootMismatch)
+	if err != nil {
+		return err
+	}
+	return compareRoots(s.AccountTree.Root, accountRoot, ErrAccountTreeRootMismatch)
+}
+
+func (s *Storage) recomputeTreeRoots() (stateRoot, accountRoot *common.Hash, err error) {
+	inMemoryDB, err := db.NewInMemoryDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = inMemoryDB.Close() }()
+
+	scratch, err := newStorageFromDatabase(&Database{Badger: inMemoryDB})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.StateTree.IterateLeaves(func(leaf *models.StateLeaf) error {
+		_, err := scratch.StateTree.Set(leaf.StateID, &leaf.UserState)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.AccountTree.IterateLeaves(func(leaf *models.AccountLeaf) error {
+		_, err := scratch.AccountTree.unsafeSet(leaf)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateRoot, err = scratch.StateTree.Root()
+	if err != nil {
+		return nil, nil, err
+	}
+	accountRoot, err = scratch.AccountTree.Root()
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateRoot, accountRoot, nil
+}
+
+func compareRoots(storedRoot func() (*common.Hash, error), recomputedRoot *common.Hash, mismatchErr error) error {
+	expected, err := storedRoot()
+	if err != nil {
+		return err
+	}
+	if *expected != *recomputedRoot {
+		return errors.WithMessagef(mismatchErr, "stored %s, recomputed %s", expected.String(), recomputedRoot.String())
+	}
+	return nil
+}
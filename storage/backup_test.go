@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type BackupTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *BackupTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *BackupTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+}
+
+func (s *BackupTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *BackupTestSuite) TestBackup_IncrementalBackupsRestoreDatabase() {
+	s.setStateLeaf(0, 100)
+
+	var fullBackup bytes.Buffer
+	version, err := s.storage.Backup(&fullBackup, 0)
+	if errors.Is(err, db.ErrUnsupportedByBackend) {
+		s.T().Skip("backups are not supported by the database backend")
+	}
+	s.NoError(err)
+
+	s.setStateLeaf(1, 200)
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{PubKeyID: 0, PublicKey: models.PublicKey{1, 2, 3}})
+	s.NoError(err)
+
+	var incrementalBackup bytes.Buffer
+	_, err = s.storage.Backup(&incrementalBackup, version)
+	s.NoError(err)
+
+	restored, err := NewTestStorage()
+	s.NoError(err)
+	defer func() {
+		s.NoError(restored.Teardown())
+	}()
+
+	err = restored.Load(&fullBackup)
+	s.NoError(err)
+	err = restored.Load(&incrementalBackup)
+	s.NoError(err)
+
+	leaf, err := restored.StateTree.Leaf(1)
+	s.NoError(err)
+	s.Equal(models.MakeUint256(200), leaf.Balance)
+
+	expectedRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+	restoredRoot, err := restored.StateTree.Root()
+	s.NoError(err)
+	s.Equal(expectedRoot, restoredRoot)
+
+	err = restored.VerifyTreeRoots()
+	s.NoError(err)
+}
+
+func (s *BackupTestSuite) TestVerifyTreeRoots_EmptyTrees() {
+	err := s.storage.VerifyTreeRoots()
+	s.NoError(err)
+}
+
+func (s *BackupTestSuite) TestVerifyTreeRoots_DetectsModifiedStateLeaf() {
+	s.setStateLeaf(0, 100)
+	s.setStateLeaf(1, 200)
+
+	leaf, err := s.storage.StateTree.Leaf(1)
+	s.NoError(err)
+	leaf.Balance = models.MakeUint256(300)
+	err = s.storage.StateTree.upsertStateLeaf(leaf)
+	s.NoError(err)
+
+	err = s.storage.VerifyTreeRoots()
+	s.ErrorIs(err, ErrStateTreeRootMismatch)
+}
+
+func (s *BackupTestSuite) setStateLeaf(stateID uint32, balance uint64) {
+	_, err := s.storage.StateTree.Set(stateID, &models.UserState{
+		PubKeyID: stateID,
+		TokenID:  models.MakeUint256(0),
+		Balance:  models.MakeUint256(balance),
+		Nonce:    models.MakeUint256(0),
+	})
+	s.NoError(err)
+}
+
+func TestBackupTestSuite(t *testing.T) {
+	suite.Run(t, new(BackupTestSuite))
+}
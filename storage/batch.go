@@ -4,10 +4,8 @@ import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/stored"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type BatchStorage struct {
@@ -34,7 +32,7 @@ func (s *BatchStorage) AddBatch(batch *models.Batch) error {
 func (s *BatchStorage) GetBatch(batchID models.Uint256) (*models.Batch, error) {
 	var storedBatch stored.Batch
 	err := s.database.Badger.Get(batchID, &storedBatch)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("batch"))
 	}
 	if err != nil {
@@ -45,7 +43,7 @@ func (s *BatchStorage) GetBatch(batchID models.Uint256) (*models.Batch, error) {
 
 func (s *BatchStorage) UpdateBatch(batch *models.Batch) error {
 	err := s.database.Badger.Update(batch.ID, *stored.NewBatchFromModelsBatch(batch))
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return errors.WithStack(NewNotFoundError("batch"))
 	}
 	return err
@@ -65,7 +63,7 @@ func (s *BatchStorage) GetMinedBatch(batchID models.Uint256) (*models.Batch, err
 func (s *BatchStorage) GetBatchByHash(batchHash common.Hash) (*models.Batch, error) {
 	var batch stored.Batch
 	err := s.database.Badger.FindOneUsingIndex(&batch, &batchHash, "Hash")
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("batch"))
 	}
 	if err != nil {
@@ -80,7 +78,7 @@ func (s *BatchStorage) GetPendingBatches() ([]models.Batch, error) {
 	storedBatches := make([]stored.Batch, 0)
 	err := s.database.Badger.Find(
 		&storedBatches,
-		bh.Where("Hash").Eq(nilHash).Index("Hash"),
+		db.Where("Hash").Eq(nilHash).Index("Hash"),
 	)
 	if err != nil {
 		return nil, err
@@ -127,13 +125,13 @@ func (s *BatchStorage) GetLatestFinalisedBatch(currentBlockNumber uint32) (*mode
 }
 
 func (s *BatchStorage) GetBatchesInRange(from, to *models.Uint256) ([]models.Batch, error) {
-	criteria := bh.Where(bh.Key)
-	var query *bh.Query
+	criteria := db.Where(db.Key)
+	var query *db.Query
 	if from == nil && to == nil {
 		query = criteria.Ge(models.MakeUint256(0))
 	}
 	if from != nil && to != nil {
-		query = criteria.Ge(*from).And(bh.Key).Le(*to)
+		query = criteria.Ge(*from).And(db.Key).Le(*to)
 	} else if from != nil {
 		query = criteria.Ge(*from)
 	} else if to != nil {
@@ -159,7 +157,7 @@ func (s *BatchStorage) RemoveBatches(batchIDs ...models.Uint256) error {
 		storedBatch := stored.Batch{}
 		for i := range batchIDs {
 			err := txDatabase.Badger.Delete(batchIDs[i], storedBatch)
-			if errors.Is(err, bh.ErrNotFound) {
+			if errors.Is(err, db.ErrNotFound) {
 				return errors.WithStack(NewNotFoundError("batch"))
 			}
 			if err != nil {
@@ -172,7 +170,7 @@ func (s *BatchStorage) RemoveBatches(batchIDs ...models.Uint256) error {
 
 func (s *BatchStorage) reverseIterateBatches(filter func(batch *stored.Batch) bool) (*models.Batch, error) {
 	var storedBatch stored.Batch
-	err := s.database.Badger.Iterator(stored.BatchPrefix, db.ReversePrefetchIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(stored.BatchPrefix, db.ReversePrefetchIteratorOpts, func(item db.Item) (bool, error) {
 		err := item.Value(func(v []byte) error {
 			return db.Decode(v, &storedBatch)
 		})
@@ -3,8 +3,8 @@ package storage
 import (
 	"sync/atomic"
 
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models/stored"
-	"github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
 )
 
@@ -21,7 +21,7 @@ func (s *ChainStateStorage) GetLatestBlockNumber() uint32 {
 func (s *ChainStateStorage) SetSyncedBlock(blockNumber uint64) error {
 	atomic.StoreUint64(&s.syncedBlock, blockNumber)
 
-	return s.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	return s.database.Badger.RawUpdate(func(txn db.Txn) error {
 		value := stored.EncodeUint64(blockNumber)
 		return txn.Set(SyncedBlockKey, value)
 	})
@@ -33,7 +33,7 @@ func (s *ChainStateStorage) GetSyncedBlock() (*uint64, error) {
 		return &syncedBlock, nil
 	}
 
-	err := s.database.Badger.View(func(txn *badger.Txn) error {
+	err := s.database.Badger.View(func(txn db.Txn) error {
 		item, err := txn.Get(SyncedBlockKey)
 		if err != nil {
 			return errors.WithStack(err)
@@ -52,7 +52,7 @@ func (s *ChainStateStorage) GetSyncedBlock() (*uint64, error) {
 
 		return nil
 	})
-	if errors.Is(err, badger.ErrKeyNotFound) {
+	if errors.Is(err, db.ErrKeyNotFound) {
 		zero := uint64(0)
 		return &zero, nil
 	}
@@ -3,9 +3,9 @@ package storage
 import (
 	"sync/atomic"
 
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type ChainStateStorage struct {
@@ -33,7 +33,7 @@ func (s *ChainStateStorage) copyWithNewDatabase(database *Database) *ChainStateS
 func (s *ChainStateStorage) GetChainState() (*models.ChainState, error) {
 	var chainState models.ChainState
 	err := s.database.Badger.Get("ChainState", &chainState)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("chain state"))
 	}
 	if err != nil {
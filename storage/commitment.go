@@ -1,11 +1,11 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (s *CommitmentStorage) AddCommitment(commitment models.Commitment) error {
@@ -79,7 +79,7 @@ func (s *CommitmentStorage) UpdateCommitments(commitments []models.Commitment) e
 			}
 
 			err := txDatabase.Badger.Update(commitments[i].GetCommitmentBase().ID, commitment)
-			if errors.Is(err, bh.ErrNotFound) {
+			if errors.Is(err, db.ErrNotFound) {
 				return NewNotFoundError("commitment")
 			}
 			if err != nil {
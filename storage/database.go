@@ -5,7 +5,6 @@ import (
 
 	"github.com/Worldcoin/hubble-commander/config"
 	"github.com/Worldcoin/hubble-commander/db"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
@@ -55,7 +54,7 @@ func (d *Database) ExecuteInTransactionWithSpan(
 ) error {
 	retries := 0
 	err := d.unsafeExecuteInTransactionWithSpan(ctx, retries, opts, fn)
-	for errors.Is(err, bdg.ErrConflict) {
+	for errors.Is(err, db.ErrConflict) {
 		// nb. if we were already inside a transaction when this function is
 		//     called then we run inside the outer transaction, so the `Commit`
 		//     call is a no-op and this retry logic will never get a chance to
@@ -71,7 +70,7 @@ func (d *Database) ExecuteInTransactionWithSpan(
 // all errors are already wrapped w stack traces, except errors fn returns
 func (d *Database) ExecuteInTransaction(opts TxOptions, fn func(txDatabase *Database) error) error {
 	err := d.unsafeExecuteInTransaction(opts, fn)
-	if errors.Is(err, bdg.ErrConflict) {
+	if errors.Is(err, db.ErrConflict) {
 		// nb. if we were already inside a transaction when this function is
 		//     called then we run inside the outer transaction, so the `Commit`
 		//     call is a no-op and this retry logic will never get a chance to
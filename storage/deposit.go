@@ -5,7 +5,6 @@ import (
 
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
 )
 
@@ -51,12 +50,12 @@ func (s *DepositStorage) GetFirstPendingDeposits(amount int) ([]models.PendingDe
 	deposits := make([]models.PendingDeposit, 0, amount)
 
 	// set iterator opts to prefetch pending deposits for performance reasons
-	keyIteratorOpts := bdg.IteratorOptions{
+	keyIteratorOpts := db.IteratorOptions{
 		PrefetchValues: true,
 		PrefetchSize:   amount,
 	}
 
-	err := s.database.Badger.Iterator(models.PendingDepositPrefix, keyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(models.PendingDepositPrefix, keyIteratorOpts, func(item db.Item) (bool, error) {
 		deposit, err := decodeDeposit(item)
 		if err != nil {
 			return false, err
@@ -73,7 +72,7 @@ func (s *DepositStorage) GetFirstPendingDeposits(amount int) ([]models.PendingDe
 	return deposits, nil
 }
 
-func decodeDeposit(item *bdg.Item) (*models.PendingDeposit, error) {
+func decodeDeposit(item db.Item) (*models.PendingDeposit, error) {
 	var deposit models.PendingDeposit
 	err := item.Value(deposit.SetBytes)
 	if err != nil {
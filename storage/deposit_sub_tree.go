@@ -3,9 +3,7 @@ package storage
 import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (s *DepositStorage) AddPendingDepositSubtree(subtree *models.PendingDepositSubtree) error {
@@ -15,7 +13,7 @@ func (s *DepositStorage) AddPendingDepositSubtree(subtree *models.PendingDeposit
 func (s *DepositStorage) GetPendingDepositSubtree(subtreeID models.Uint256) (*models.PendingDepositSubtree, error) {
 	var subtree models.PendingDepositSubtree
 	err := s.database.Badger.Get(subtreeID, &subtree)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("deposit sub tree"))
 	}
 	if err != nil {
@@ -28,7 +26,7 @@ func (s *DepositStorage) GetPendingDepositSubtree(subtreeID models.Uint256) (*mo
 }
 
 func (s *DepositStorage) GetFirstPendingDepositSubtree() (subtree *models.PendingDepositSubtree, err error) {
-	err = s.database.Badger.Iterator(models.PendingDepositSubtreePrefix, db.KeyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err = s.database.Badger.Iterator(models.PendingDepositSubtreePrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
 		subtree, err = decodePendingDepositSubtree(item)
 		if err != nil {
 			return false, err
@@ -45,7 +43,7 @@ func (s *DepositStorage) RemovePendingDepositSubtrees(subtreeIDs ...models.Uint2
 	return s.database.ExecuteInTransaction(TxOptions{}, func(txDatabase *Database) error {
 		for i := range subtreeIDs {
 			err := txDatabase.Badger.Delete(subtreeIDs[i], models.PendingDepositSubtree{})
-			if errors.Is(err, bh.ErrNotFound) {
+			if errors.Is(err, db.ErrNotFound) {
 				return errors.WithStack(NewNotFoundError("deposit sub tree"))
 			}
 			if err != nil {
@@ -56,7 +54,7 @@ func (s *DepositStorage) RemovePendingDepositSubtrees(subtreeIDs ...models.Uint2
 	})
 }
 
-func decodePendingDepositSubtree(item *bdg.Item) (*models.PendingDepositSubtree, error) {
+func decodePendingDepositSubtree(item db.Item) (*models.PendingDepositSubtree, error) {
 	var subtree models.PendingDepositSubtree
 	err := item.Value(subtree.SetBytes)
 	if err != nil {
@@ -9,9 +9,11 @@ import (
 )
 
 var (
-	ErrNoRowsAffected          = fmt.Errorf("no rows were affected by the update")
-	ErrNonexistentState        = fmt.Errorf("cannot revert to nonexistent state")
-	ErrAlreadyMinedTransaction = fmt.Errorf("transaction already mined")
+	ErrNoRowsAffected           = fmt.Errorf("no rows were affected by the update")
+	ErrNonexistentState         = fmt.Errorf("cannot revert to nonexistent state")
+	ErrAlreadyMinedTransaction  = fmt.Errorf("transaction already mined")
+	ErrInconsistentStateUpdates = fmt.Errorf("state updates don't form a continuous history of the state tree")
+	ErrNoFinalisedBatch         = fmt.Errorf("there is no finalised batch to take a snapshot at")
 
 	AnyNotFoundError, anyNotFoundErrorSupport = utils.NewAnyError(&NotFoundError{})
 )
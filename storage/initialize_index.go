@@ -2,9 +2,7 @@ package storage
 
 import (
 	"github.com/Worldcoin/hubble-commander/db"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 // We need to "initialize" the indices on fields of pointer type to make them work with bh.Find operations.
@@ -29,23 +27,23 @@ func initializeIndex(database *Database, typeName []byte, indexName string, zero
 		return err
 	}
 
-	emptyKeyList := make(bh.KeyList, 0)
+	emptyKeyList := make(db.KeyList, 0)
 	encodedEmptyKeyList, err := db.Encode(emptyKeyList)
 	if err != nil {
 		return err
 	}
 
-	return database.Badger.RawUpdate(func(txn *bdg.Txn) error {
+	return database.Badger.RawUpdate(func(txn db.Txn) error {
 		return txn.Set(zeroValueIndexKey, encodedEmptyKeyList)
 	})
 }
 
 func indexAlreadyInitialised(database *Database, indexKey []byte) (bool, error) {
-	err := database.Badger.View(func(txn *bdg.Txn) error {
+	err := database.Badger.View(func(txn db.Txn) error {
 		_, err := txn.Get(indexKey)
 		return err
 	})
-	if errors.Is(err, bdg.ErrKeyNotFound) {
+	if errors.Is(err, db.ErrKeyNotFound) {
 		return false, nil
 	}
 	if err != nil {
@@ -8,7 +8,6 @@ import (
 	"github.com/Worldcoin/hubble-commander/testutils"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type InitializeIndexTestSuite struct {
@@ -81,16 +80,16 @@ func (s *InitializeIndexTestSuite) TestAccountLeaf_PublicKey_FindUsingIndexWorks
 	accounts := make([]models.AccountLeaf, 0, 1)
 	err = s.storage.database.Badger.Find(
 		&accounts,
-		bh.Where("PublicKey").Ge(models.ZeroPublicKey).Index("PublicKey"),
+		db.Where("PublicKey").Ge(models.ZeroPublicKey).Index("PublicKey"),
 	)
 	s.NoError(err)
 	s.Len(accounts, 0)
 }
 
-func (s *InitializeIndexTestSuite) getPublicKeyIndexValues(typeName []byte) map[models.PublicKey]bh.KeyList {
-	indexValues := make(map[models.PublicKey]bh.KeyList)
+func (s *InitializeIndexTestSuite) getPublicKeyIndexValues(typeName []byte) map[models.PublicKey]db.KeyList {
+	indexValues := make(map[models.PublicKey]db.KeyList)
 
-	s.iterateIndex(typeName, "PublicKey", func(encodedKey []byte, keyList bh.KeyList) {
+	s.iterateIndex(typeName, "PublicKey", func(encodedKey []byte, keyList db.KeyList) {
 		var publicKey models.PublicKey
 		err := db.Decode(encodedKey, &publicKey)
 		s.NoError(err)
@@ -104,7 +103,7 @@ func (s *InitializeIndexTestSuite) getPublicKeyIndexValues(typeName []byte) map[
 func (s *InitializeIndexTestSuite) iterateIndex(
 	typeName []byte,
 	indexName string,
-	handleIndex func(encodedKey []byte, keyList bh.KeyList),
+	handleIndex func(encodedKey []byte, keyList db.KeyList),
 ) {
 	testutils.IterateIndex(s.Assertions, s.storage.database.Badger, typeName, indexName, handleIndex)
 }
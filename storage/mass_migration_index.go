@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// The batched mass migrations are indexed by sender with raw keys, one per transaction, so the withdrawals
+// of a public key can be found without reading every batch.
+
+var massMigrationByFromStateIDPrefix = []byte("MassMigrationByFromStateID:")
+
+const massMigrationIndexMigrationBatchSize = 10_000
+
+type IndexedMassMigration struct {
+	Slot models.CommitmentSlot
+	Hash common.Hash
+}
+
+func massMigrationByFromStateIDPrefixOf(fromStateID uint32) []byte {
+	return append(append([]byte{}, massMigrationByFromStateIDPrefix...), stored.EncodeUint32(fromStateID)...)
+}
+
+func massMigrationByFromStateIDKey(batchedTx *stored.BatchedTx) []byte {
+	return append(massMigrationByFromStateIDPrefixOf(batchedTx.FromStateID), batchedTx.ID.Bytes()...)
+}
+
+func setMassMigrationIndex(txn db.Txn, batchedTx *stored.BatchedTx) error {
+	if batchedTx.TxType != txtype.MassMigration {
+		return nil
+	}
+	return errors.WithStack(txn.Set(massMigrationByFromStateIDKey(batchedTx), batchedTx.Hash.Bytes()))
+}
+
+func (s *TransactionStorage) setMassMigrationIndex(batchedTx *stored.BatchedTx) error {
+	return s.database.Badger.RawUpdate(func(txn db.Txn) error {
+		return setMassMigrationIndex(txn, batchedTx)
+	})
+}
+
+func (s *TransactionStorage) deleteMassMigrationIndex(batchedTx *stored.BatchedTx) error {
+	if batchedTx.TxType != txtype.MassMigration {
+		return nil
+	}
+	return s.database.Badger.RawUpdate(func(txn db.Txn) error {
+		return errors.WithStack(txn.Delete(massMigrationByFromStateIDKey(batchedTx)))
+	})
+}
+
+// GetBatchedMassMigrationsByFromStateID returns the slots and hashes of the batched mass migrations sent
+// from the state, ordered by slot
+func (s *TransactionStorage) GetBatchedMassMigrationsByFromStateID(fromStateID uint32) ([]IndexedMassMigration, error) {
+	prefix := massMigrationByFromStateIDPrefixOf(fromStateID)
+
+	massMigrations := make([]IndexedMassMigration, 0, 1)
+	err := s.database.Badger.View(func(txn db.Txn) error {
+		it := txn.NewIterator(db.PrefetchIteratorOpts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var massMigration IndexedMassMigration
+			err := massMigration.Slot.SetBytes(it.Item().Key()[len(prefix):])
+			if err != nil {
+				return err
+			}
+			err = it.Item().Value(func(value []byte) error {
+				massMigration.Hash.SetBytes(value)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			massMigrations = append(massMigrations, massMigration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return massMigrations, nil
+}
+
+// IndexMassMigrations builds the sender index of the mass migrations batched before it was introduced.
+// Transactions are indexed in batches, running it again just rewrites the same entries.
+func (s *Storage) IndexMassMigrations() error {
+	batch := make([]stored.BatchedTx, 0, massMigrationIndexMigrationBatchSize)
+	writeBatch := func() error {
+		err := s.database.Badger.RawUpdate(func(txn db.Txn) error {
+			for i := range batch {
+				err := setMassMigrationIndex(txn, &batch[i])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	err := s.database.Badger.Iterator(stored.BatchedTxPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		var batchedTx stored.BatchedTx
+		err := item.Value(func(value []byte) error {
+			return db.Decode(value, &batchedTx)
+		})
+		if err != nil {
+			return false, err
+		}
+		if batchedTx.TxType != txtype.MassMigration {
+			return false, nil
+		}
+
+		batch = append(batch, batchedTx)
+		if len(batch) < massMigrationIndexMigrationBatchSize {
+			return false, nil
+		}
+		return false, writeBatch()
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return err
+	}
+	return writeBatch()
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"testing"
 
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
 	"github.com/Worldcoin/hubble-commander/utils"
@@ -97,6 +98,70 @@ func (s *MassMigrationTestSuite) TestGetMassMigration_NonexistentMassMigration()
 	s.Nil(res)
 }
 
+func (s *MassMigrationTestSuite) TestGetBatchedMassMigrationsByFromStateID() {
+	slots := []models.CommitmentSlot{
+		{BatchID: models.MakeUint256(3), IndexInCommitment: 1},
+		{BatchID: models.MakeUint256(4), IndexInCommitment: 0},
+	}
+	hashes := s.addBatchedMassMigrations(slots)
+
+	massMigrations, err := s.storage.GetBatchedMassMigrationsByFromStateID(massMigration.FromStateID)
+	s.NoError(err)
+	s.Equal([]IndexedMassMigration{
+		{Slot: slots[0], Hash: hashes[0]},
+		{Slot: slots[1], Hash: hashes[1]},
+	}, massMigrations)
+
+	massMigrations, err = s.storage.GetBatchedMassMigrationsByFromStateID(massMigration.FromStateID + 1)
+	s.NoError(err)
+	s.Len(massMigrations, 0)
+}
+
+func (s *MassMigrationTestSuite) TestGetBatchedMassMigrationsByFromStateID_SkipsRevertedMassMigrations() {
+	slots := []models.CommitmentSlot{
+		{BatchID: models.MakeUint256(3)},
+		{BatchID: models.MakeUint256(4)},
+	}
+	hashes := s.addBatchedMassMigrations(slots)
+
+	err := s.storage.MarkTransactionsAsPending([]models.CommitmentSlot{slots[1]})
+	s.NoError(err)
+
+	massMigrations, err := s.storage.GetBatchedMassMigrationsByFromStateID(massMigration.FromStateID)
+	s.NoError(err)
+	s.Equal([]IndexedMassMigration{{Slot: slots[0], Hash: hashes[0]}}, massMigrations)
+}
+
+func (s *MassMigrationTestSuite) TestIndexMassMigrations_IndexesExistingMassMigrations() {
+	slots := []models.CommitmentSlot{{BatchID: models.MakeUint256(3)}}
+	hashes := s.addBatchedMassMigrations(slots)
+
+	err := s.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
+		return txn.Delete(append(massMigrationByFromStateIDPrefixOf(massMigration.FromStateID), slots[0].Bytes()...))
+	})
+	s.NoError(err)
+
+	err = s.storage.IndexMassMigrations()
+	s.NoError(err)
+
+	massMigrations, err := s.storage.GetBatchedMassMigrationsByFromStateID(massMigration.FromStateID)
+	s.NoError(err)
+	s.Equal([]IndexedMassMigration{{Slot: slots[0], Hash: hashes[0]}}, massMigrations)
+}
+
+func (s *MassMigrationTestSuite) addBatchedMassMigrations(slots []models.CommitmentSlot) []common.Hash {
+	hashes := make([]common.Hash, 0, len(slots))
+	for i := range slots {
+		batchedMassMigration := massMigration
+		batchedMassMigration.Hash = utils.RandomHash()
+		batchedMassMigration.CommitmentSlot = &slots[i]
+		err := s.storage.AddTransaction(&batchedMassMigration)
+		s.NoError(err)
+		hashes = append(hashes, batchedMassMigration.Hash)
+	}
+	return hashes
+}
+
 func TestMassMigrationTestSuite(t *testing.T) {
 	suite.Run(t, new(MassMigrationTestSuite))
 }
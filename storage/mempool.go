@@ -13,7 +13,6 @@ import (
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/Worldcoin/hubble-commander/utils"
 	"github.com/Worldcoin/hubble-commander/utils/consts"
-	"github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -107,7 +106,7 @@ func decodePendingState(data []byte) (nonce, balance models.Uint256) {
 }
 
 func (s *Storage) rawLookup(key []byte) (value []byte, err error) {
-	err = s.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	err = s.database.Badger.RawUpdate(func(txn db.Txn) error {
 		item, innerErr := txn.Get(key)
 		if innerErr != nil {
 			return errors.WithStack(innerErr)
@@ -130,7 +129,7 @@ func (s *Storage) hasKey(key []byte) (bool, error) {
 		return true, nil
 	}
 
-	if errors.Is(err, badger.ErrKeyNotFound) {
+	if errors.Is(err, db.ErrKeyNotFound) {
 		return false, nil
 	}
 
@@ -138,7 +137,7 @@ func (s *Storage) hasKey(key []byte) (bool, error) {
 }
 
 func (s *Storage) rawSet(key, value []byte) error {
-	return s.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	return s.database.Badger.RawUpdate(func(txn db.Txn) error {
 		err := txn.Set(key, value)
 		if err != nil {
 			return errors.WithStack(err)
@@ -176,7 +175,7 @@ func (s *Storage) getPendingState(stateID uint32) (
 		return &decodedNonce, &decodedBalance, nil
 	}
 
-	if errors.Is(err, badger.ErrKeyNotFound) {
+	if errors.Is(err, db.ErrKeyNotFound) {
 		state, innerErr := s.StateTree.Leaf(stateID)
 		if innerErr != nil {
 			return nil, nil, innerErr
@@ -211,7 +210,7 @@ func (s *Storage) getPendingPubkeyBalance(pubkey *models.PublicKey) (*models.Uin
 func (s *Storage) addToPendingPubkeyBalance(pubkey *models.PublicKey, amount *models.Uint256) error {
 	balance, err := s.getPendingPubkeyBalance(pubkey)
 
-	if err != nil && errors.Is(err, badger.ErrKeyNotFound) {
+	if err != nil && errors.Is(err, db.ErrKeyNotFound) {
 		addressableValue := models.MakeUint256(0)
 		balance = &addressableValue
 	} else if err != nil {
@@ -333,7 +332,7 @@ func (s *Storage) GetPendingC2TState(pubkey *models.PublicKey) (*models.UserStat
 	}
 
 	balance, err := s.getPendingPubkeyBalance(pubkey)
-	if err != nil && errors.Is(err, badger.ErrKeyNotFound) {
+	if err != nil && errors.Is(err, db.ErrKeyNotFound) {
 		return nil, nil
 	}
 	if err != nil {
@@ -439,7 +438,7 @@ func (s *Storage) unsafeAddMempoolTx(tx models.GenericTransaction) error {
 	if err == nil {
 		return errors.WithStack(fmt.Errorf("cannot replace transactions"))
 	}
-	if !errors.Is(err, badger.ErrKeyNotFound) {
+	if !errors.Is(err, db.ErrKeyNotFound) {
 		return err
 	}
 
@@ -493,7 +492,7 @@ func (mh *MempoolHeap) nextTxForAccount(stateID uint32) (
 	// Next() and Key() and ValidForPrefix are safe but Item() and Seek() are
 	// dangerous, they add to the read set.
 
-	err = mh.storage.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	err = mh.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
 		iter := txn.NewIterator(db.PrefetchIteratorOpts)
 		defer iter.Close()
 
@@ -541,7 +540,7 @@ func (mh *MempoolHeap) nextTxForAccount(stateID uint32) (
 	return pendingTx, err
 }
 
-func itemToPendingState(item *badger.Item) (nonce, balance *models.Uint256, err error) {
+func itemToPendingState(item db.Item) (nonce, balance *models.Uint256, err error) {
 	value, err := item.ValueCopy(nil)
 	if err != nil {
 		return nil, nil, errors.WithStack(err)
@@ -551,7 +550,7 @@ func itemToPendingState(item *badger.Item) (nonce, balance *models.Uint256, err
 	return &decodedNonce, &decodedBalance, nil
 }
 
-func itemToPendingTx(item *badger.Item) (*stored.PendingTx, error) {
+func itemToPendingTx(item db.Item) (*stored.PendingTx, error) {
 	value, err := item.ValueCopy(nil)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -567,7 +566,7 @@ func itemToPendingTx(item *badger.Item) (*stored.PendingTx, error) {
 	return &result, nil
 }
 
-func itemToPendingPubkeyBalance(item *badger.Item) (*models.Uint256, error) {
+func itemToPendingPubkeyBalance(item db.Item) (*models.Uint256, error) {
 	value, err := item.ValueCopy(nil)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -626,7 +625,7 @@ func (s *Storage) FindOldestMempoolTransaction(txType txtype.TransactionType) (*
 func (s *Storage) lowestNoncePendingTxs() ([]stored.PendingTx, error) {
 	result := make([]stored.PendingTx, 0)
 
-	err := s.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	err := s.database.Badger.RawUpdate(func(txn db.Txn) error {
 		iter := txn.NewIterator(db.PrefetchIteratorOpts)
 		defer iter.Close()
 
@@ -657,7 +656,7 @@ func (s *Storage) lowestNoncePendingTxs() ([]stored.PendingTx, error) {
 }
 
 func (s *Storage) forEachMempoolTransaction(fun func(*stored.PendingTx) error) error {
-	return s.database.Badger.View(func(txn *badger.Txn) error {
+	return s.database.Badger.View(func(txn db.Txn) error {
 		iter := txn.NewIterator(db.PrefetchIteratorOpts)
 		defer iter.Close()
 
@@ -720,7 +719,7 @@ func (s *Storage) unsafeGetPendingStates(startStateID, pageSize uint32) (
 ) {
 	result = make([]dto.UserStateWithID, 0)
 
-	err = s.database.Badger.View(func(txn *badger.Txn) error {
+	err = s.database.Badger.View(func(txn db.Txn) error {
 		iter := txn.NewIterator(db.PrefetchIteratorOpts)
 		defer iter.Close()
 
@@ -768,7 +767,7 @@ func (s *Storage) unsafeGetPendingStates(startStateID, pageSize uint32) (
 func (s *Storage) GetPendingPubkeyBalances(startPrefix []byte, pageSize uint32) ([]dto.PubkeyBalance, error) {
 	result := make([]dto.PubkeyBalance, 0)
 
-	err := s.database.Badger.View(func(txn *badger.Txn) error {
+	err := s.database.Badger.View(func(txn db.Txn) error {
 		iter := txn.NewIterator(db.PrefetchIteratorOpts)
 		defer iter.Close()
 
@@ -987,6 +986,21 @@ func (s *Storage) GetAllMempoolTransactions() ([]stored.PendingTx, error) {
 	return result, nil
 }
 
+// CountMempoolTransactions returns how many transactions of each type are in the mempool
+func (s *Storage) CountMempoolTransactions() (map[txtype.TransactionType]uint32, error) {
+	result := make(map[txtype.TransactionType]uint32)
+
+	err := s.forEachMempoolTransaction(func(pendingTx *stored.PendingTx) error {
+		result[pendingTx.TxType] += 1
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (s *Storage) CountPendingTxsOfType(txType txtype.TransactionType) (uint32, error) {
 	result := uint32(0)
 
@@ -1221,7 +1235,7 @@ func (mh *MempoolHeap) scheduleDeletion(pendingTx *stored.PendingTx) {
 
 // Write all our changes back to the Storage transaction.
 func (mh *MempoolHeap) Savepoint() error {
-	err := mh.storage.database.Badger.RawUpdate(func(txn *badger.Txn) error {
+	err := mh.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
 		for _, key := range mh.toBeDeleted {
 			innerErr := txn.Delete(key)
 			if innerErr != nil {
@@ -10,7 +10,6 @@ import (
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/Worldcoin/hubble-commander/testutils"
 	"github.com/Worldcoin/hubble-commander/utils/consts"
-	"github.com/dgraph-io/badger/v3"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -132,6 +131,18 @@ func (s *MempoolTestSuite) TestMempool_PeekEmptyMempool() {
 	s.Nil(firstTx)
 }
 
+func (s *MempoolTestSuite) TestCountMempoolTransactions() {
+	s.rawInsert(testutils.NewTransfer(1, 2, 0, 10))
+	s.rawInsert(testutils.NewTransfer(1, 2, 1, 10))
+	s.rawInsert(testutils.NewMassMigration(1, 1, 2, 10))
+
+	counts, err := s.storage.CountMempoolTransactions()
+	s.NoError(err)
+	s.Equal(uint32(2), counts[txtype.Transfer])
+	s.Equal(uint32(0), counts[txtype.Create2Transfer])
+	s.Equal(uint32(1), counts[txtype.MassMigration])
+}
+
 func (s *MempoolTestSuite) randomPublicKey() *models.PublicKey {
 	domain := bls.Domain{1, 2, 3, 4}
 	wallet, err := bls.NewRandomWallet(domain)
@@ -197,11 +208,11 @@ func (s *ConflictTestSuite) SetupSuite() {
 }
 
 func (s *ConflictTestSuite) SetupTest() {
-	memoryDB, err := db.NewInMemoryDatabase()
+	testDB, err := db.NewTestDB()
 	s.NoError(err)
 
 	database := &Database{
-		Badger: memoryDB,
+		Badger: testDB.DB,
 	}
 
 	rollupStorage, err := newStorageFromDatabase(database)
@@ -266,7 +277,7 @@ func (s *ConflictTestSuite) TestConflict_ConflictsWithGet() {
 
 	key := pendingTxKey(1, 0)
 	_, err = txRollupStorage.rawLookup(key)
-	s.ErrorIs(err, badger.ErrKeyNotFound) // the API has not yet Commit()
+	s.ErrorIs(err, db.ErrKeyNotFound) // the API has not yet Commit()
 
 	// (IV) do a write so that badger knows to try to fail this tx in case of conflict
 	key = pendingTxKey(10, 10)
@@ -281,7 +292,7 @@ func (s *ConflictTestSuite) TestConflict_ConflictsWithGet() {
 	// (VI) commit rollupTx and notice that it fails
 
 	err = rollupTxController.Commit()
-	s.ErrorIs(err, badger.ErrConflict)
+	s.ErrorIs(err, db.ErrConflict)
 }
 
 // now, confirm that there is no conflict even if the rollup loop has "read" the key.
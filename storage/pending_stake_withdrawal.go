@@ -3,9 +3,7 @@ package storage
 import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type PendingStakeWithdrawalStorage struct {
@@ -32,7 +30,7 @@ func (s *PendingStakeWithdrawalStorage) AddPendingStakeWithdrawal(stake *models.
 func (s *PendingStakeWithdrawalStorage) RemovePendingStakeWithdrawal(batchID models.Uint256) error {
 	var stake models.PendingStakeWithdrawal
 	err := s.database.Badger.Delete(batchID, &stake)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return errors.WithStack(NewNotFoundError("pending stake withdrawal"))
 	}
 	if err != nil {
@@ -41,11 +39,30 @@ func (s *PendingStakeWithdrawalStorage) RemovePendingStakeWithdrawal(batchID mod
 	return nil
 }
 
+// TakePendingStakeWithdrawal removes the pending stake withdrawal and returns it. The stake has to be
+// taken before its withdrawal is sent, concurrent callers get a NotFoundError so the same stake is not
+// withdrawn twice. It has to be added back when sending the withdrawal fails.
+func (s *PendingStakeWithdrawalStorage) TakePendingStakeWithdrawal(batchID models.Uint256) (*models.PendingStakeWithdrawal, error) {
+	var stake *models.PendingStakeWithdrawal
+	err := s.database.ExecuteInTransaction(TxOptions{}, func(txDatabase *Database) (err error) {
+		txStorage := s.copyWithNewDatabase(txDatabase)
+		stake, err = txStorage.GetPendingStakeWithdrawal(batchID)
+		if err != nil {
+			return err
+		}
+		return txStorage.RemovePendingStakeWithdrawal(batchID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stake, nil
+}
+
 func (s *PendingStakeWithdrawalStorage) GetReadyStateWithdrawals(currentBlock uint32) ([]models.PendingStakeWithdrawal, error) {
 	stakes := make([]models.PendingStakeWithdrawal, 0)
 	var stake models.PendingStakeWithdrawal
 	err := s.database.Badger.Iterator(models.PendingStakeWithdrawalPrefix, db.PrefetchIteratorOpts,
-		func(item *bdg.Item) (bool, error) {
+		func(item db.Item) (bool, error) {
 			err := item.Value(stake.SetBytes)
 			if err != nil {
 				return false, err
@@ -61,3 +78,24 @@ func (s *PendingStakeWithdrawalStorage) GetReadyStateWithdrawals(currentBlock ui
 	}
 	return stakes, nil
 }
+
+func (s *PendingStakeWithdrawalStorage) GetPendingStakeWithdrawals() ([]models.PendingStakeWithdrawal, error) {
+	stakes := make([]models.PendingStakeWithdrawal, 0)
+	err := s.database.Badger.Find(&stakes, nil)
+	if err != nil {
+		return nil, err
+	}
+	return stakes, nil
+}
+
+func (s *PendingStakeWithdrawalStorage) GetPendingStakeWithdrawal(batchID models.Uint256) (*models.PendingStakeWithdrawal, error) {
+	var stake models.PendingStakeWithdrawal
+	err := s.database.Badger.Get(batchID, &stake)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, errors.WithStack(NewNotFoundError("pending stake withdrawal"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stake, nil
+}
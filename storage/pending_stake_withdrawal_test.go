@@ -46,6 +46,22 @@ func (s *PendingStakeWithdrawalTestSuite) TestRemovePendingStakeWithdrawal_Nonex
 	s.ErrorIs(err, NewNotFoundError("pending stake withdrawal"))
 }
 
+func (s *PendingStakeWithdrawalTestSuite) TestTakePendingStakeWithdrawal() {
+	stake := models.PendingStakeWithdrawal{
+		BatchID:           models.MakeUint256(1),
+		FinalisationBlock: 100,
+	}
+	err := s.storage.AddPendingStakeWithdrawal(&stake)
+	s.NoError(err)
+
+	takenStake, err := s.storage.TakePendingStakeWithdrawal(stake.BatchID)
+	s.NoError(err)
+	s.Equal(stake, *takenStake)
+
+	_, err = s.storage.TakePendingStakeWithdrawal(stake.BatchID)
+	s.ErrorIs(err, NewNotFoundError("pending stake withdrawal"))
+}
+
 func (s *PendingStakeWithdrawalTestSuite) TestGetReadyStateWithdrawals_AddAndGet() {
 	stakes := []models.PendingStakeWithdrawal{
 		{
@@ -76,6 +92,33 @@ func (s *PendingStakeWithdrawalTestSuite) TestGetReadyStateWithdrawals_Nonexiste
 	s.Len(stakes, 0)
 }
 
+func (s *PendingStakeWithdrawalTestSuite) TestGetPendingStakeWithdrawals() {
+	stakes := []models.PendingStakeWithdrawal{
+		{
+			BatchID:           models.MakeUint256(1),
+			FinalisationBlock: 10,
+		},
+		{
+			BatchID:           models.MakeUint256(2),
+			FinalisationBlock: 12,
+		},
+	}
+
+	for i := range stakes {
+		err := s.storage.AddPendingStakeWithdrawal(&stakes[i])
+		s.NoError(err)
+	}
+
+	actualStakes, err := s.storage.GetPendingStakeWithdrawals()
+	s.NoError(err)
+	s.Equal(stakes, actualStakes)
+}
+
+func (s *PendingStakeWithdrawalTestSuite) TestGetPendingStakeWithdrawal_NonexistentStake() {
+	_, err := s.storage.GetPendingStakeWithdrawal(models.MakeUint256(42))
+	s.ErrorIs(err, NewNotFoundError("pending stake withdrawal"))
+}
+
 func TestPendingStakeWithdrawalTestSuiteTestSuite(t *testing.T) {
 	suite.Run(t, new(PendingStakeWithdrawalTestSuite))
 }
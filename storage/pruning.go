@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const pruneChunkSize = 1000
+
+type PruningResult struct {
+	BatchID            *models.Uint256 // nil when there was nothing old enough to prune
+	PrunedStateUpdates uint64
+	ReclaimedBytes     uint64
+}
+
+// PruneStateUpdates deletes state updates which are only needed to revert the state tree past the batch
+// which is safetyMargin batches older than the latest finalised one. Finalised batches can never be
+// reverted, so StateTree.RevertTo and StateTree.IterateLeavesAt keep working for every batch after it.
+// State updates are the only records read solely by reverts, commitments, batches and transactions are
+// served by the API and are kept.
+//
+// stateMutex is the mutex held while batches are applied or reverted. It is taken to find the state updates
+// to prune and then once per deleted chunk, so pruning never blocks the rollup loop for long.
+func (s *Storage) PruneStateUpdates(safetyMargin uint32, stateMutex sync.Locker) (*PruningResult, error) {
+	result := &PruningResult{}
+
+	stateMutex.Lock()
+	batchID, lastStateUpdateID, err := s.findPrunableStateUpdates(safetyMargin)
+	stateMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if lastStateUpdateID == nil {
+		return result, nil
+	}
+
+	result.BatchID = batchID
+	for {
+		stateMutex.Lock()
+		removedCount, removedBytes, err := s.StateTree.removeStateUpdatesChunk(*lastStateUpdateID)
+		stateMutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if removedCount == 0 {
+			return result, nil
+		}
+		result.PrunedStateUpdates += removedCount
+		result.ReclaimedBytes += removedBytes
+	}
+}
+
+// findPrunableStateUpdates returns the batch up to which state updates can be pruned and the ID of the last state
+// update of that batch, the ID is nil when there is nothing to prune
+func (s *Storage) findPrunableStateUpdates(safetyMargin uint32) (*models.Uint256, *uint64, error) {
+	syncedBlock, err := s.GetSyncedBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	latestFinalisedBatch, err := s.GetLatestFinalisedBatch(uint32(*syncedBlock))
+	if IsNotFoundError(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	margin := models.MakeUint256(uint64(safetyMargin))
+	if latestFinalisedBatch.ID.Cmp(&margin) <= 0 {
+		return nil, nil, nil
+	}
+	batchID := latestFinalisedBatch.ID.Sub(&margin)
+
+	// batches imported from a snapshot come without commitments, there is nothing to prune before them
+	commitments, err := s.getStoredCommitmentsByBatchID(*batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(commitments) == 0 {
+		return nil, nil, nil
+	}
+
+	lastStateUpdateID, err := s.StateTree.findStateUpdateResultingIn(commitments[len(commitments)-1].PostStateRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batchID, lastStateUpdateID, nil
+}
+
+// findStateUpdateResultingIn returns the ID of the latest state update which changed the root to stateRoot,
+// or nil when it was already pruned. The search starts from the newest state update, so only the updates
+// applied after stateRoot are read.
+func (s *StateTree) findStateUpdateResultingIn(stateRoot common.Hash) (*uint64, error) {
+	var stateUpdateID *uint64
+	appliedOnStateRoot := false
+	err := s.database.Badger.Iterator(models.StateUpdatePrefix, db.ReversePrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		stateUpdate, err := decodeStateUpdate(item)
+		if err != nil {
+			return false, err
+		}
+		if stateUpdate.CurrentRoot == stateRoot {
+			stateUpdateID = &stateUpdate.ID
+			return true, nil
+		}
+		if appliedOnStateRoot {
+			// the update preceding the one applied on top of stateRoot was already pruned
+			return true, nil
+		}
+		appliedOnStateRoot = stateUpdate.PrevRoot == stateRoot
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return stateUpdateID, nil
+}
+
+// removeStateUpdatesChunk removes at most pruneChunkSize of the oldest state updates with IDs up to lastID
+func (s *StateTree) removeStateUpdatesChunk(lastID uint64) (removedCount, removedBytes uint64, err error) {
+	keys := make([][]byte, 0, pruneChunkSize)
+	err = s.database.Badger.Iterator(models.StateUpdatePrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
+		var id uint64
+		err = db.DecodeKey(item.Key(), &id, models.StateUpdatePrefix)
+		if err != nil {
+			return false, err
+		}
+		if id > lastID {
+			return true, nil
+		}
+
+		keys = append(keys, item.KeyCopy(nil))
+		removedBytes += uint64(item.EstimatedSize())
+		return len(keys) == pruneChunkSize, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return 0, 0, err
+	}
+	if len(keys) == 0 {
+		return 0, 0, nil
+	}
+
+	err = s.database.Badger.RawUpdate(func(txn db.Txn) error {
+		for i := range keys {
+			innerErr := txn.Delete(keys[i])
+			if innerErr != nil {
+				return innerErr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint64(len(keys)), removedBytes, nil
+}
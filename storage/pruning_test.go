@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PruningTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *PruningTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *PruningTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+
+	err = s.storage.SetSyncedBlock(25)
+	s.NoError(err)
+
+	s.addBatch(0, 5)
+	s.setStateLeaf(0, 100)
+	s.setStateLeaf(1, 200)
+	s.addBatch(1, 10)
+	s.setStateLeaf(0, 150)
+	s.addBatch(2, 20)
+	s.setStateLeaf(1, 250)
+	s.addBatch(3, 30)
+}
+
+func (s *PruningTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *PruningTestSuite) TestPruneStateUpdates_KeepsUpdatesAfterSafetyMargin() {
+	result, err := s.storage.PruneStateUpdates(1, &sync.Mutex{})
+	s.NoError(err)
+	s.Equal(models.NewUint256(1), result.BatchID)
+	s.EqualValues(2, result.PrunedStateUpdates)
+	s.Greater(result.ReclaimedBytes, uint64(0))
+	s.Equal(2, s.countStateUpdates())
+
+	// batch #2 is the latest finalised one, with a safety margin of 1 we can still revert to batch #1
+	batchRoot := s.getBatchRoot(1)
+	err = s.storage.StateTree.RevertTo(batchRoot)
+	s.NoError(err)
+}
+
+func (s *PruningTestSuite) TestPruneStateUpdates_IsIdempotent() {
+	_, err := s.storage.PruneStateUpdates(0, &sync.Mutex{})
+	s.NoError(err)
+	s.Equal(1, s.countStateUpdates())
+
+	result, err := s.storage.PruneStateUpdates(0, &sync.Mutex{})
+	s.NoError(err)
+	s.Nil(result.BatchID)
+	s.EqualValues(0, result.PrunedStateUpdates)
+	s.Equal(1, s.countStateUpdates())
+}
+
+func (s *PruningTestSuite) TestPruneStateUpdates_SafetyMarginLargerThanBatchCount() {
+	result, err := s.storage.PruneStateUpdates(2, &sync.Mutex{})
+	s.NoError(err)
+	s.Nil(result.BatchID)
+	s.Equal(4, s.countStateUpdates())
+}
+
+func (s *PruningTestSuite) TestPruneStateUpdates_RemovesUpdatesInChunks() {
+	for i := uint32(0); i < pruneChunkSize; i++ {
+		s.setStateLeaf(2, uint64(i))
+	}
+	s.addBatch(4, 24)
+
+	result, err := s.storage.PruneStateUpdates(0, &sync.Mutex{})
+	s.NoError(err)
+	s.EqualValues(pruneChunkSize+4, result.PrunedStateUpdates)
+	s.Equal(0, s.countStateUpdates())
+}
+
+func (s *PruningTestSuite) TestPruneStateUpdates_WaitsForStateMutex() {
+	var stateMutex sync.Mutex
+	stateMutex.Lock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.storage.PruneStateUpdates(0, &stateMutex)
+		s.NoError(err)
+	}()
+
+	select {
+	case <-done:
+		s.Fail("pruning did not wait for the state mutex")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.Equal(4, s.countStateUpdates())
+
+	stateMutex.Unlock()
+	<-done
+	s.Equal(1, s.countStateUpdates())
+}
+
+func (s *PruningTestSuite) setStateLeaf(stateID uint32, balance uint64) {
+	_, err := s.storage.StateTree.Set(stateID, &models.UserState{
+		PubKeyID: stateID,
+		TokenID:  models.MakeUint256(0),
+		Balance:  models.MakeUint256(balance),
+		Nonce:    models.MakeUint256(0),
+	})
+	s.NoError(err)
+}
+
+// addBatch adds a batch with a single commitment whose post state root is the current state root
+func (s *PruningTestSuite) addBatch(batchID uint64, finalisationBlock uint32) {
+	err := s.storage.AddBatch(&models.Batch{
+		ID:                models.MakeUint256(batchID),
+		Type:              batchtype.Transfer,
+		TransactionHash:   utils.RandomHash(),
+		Hash:              utils.NewRandomHash(),
+		FinalisationBlock: ref.Uint32(finalisationBlock),
+	})
+	s.NoError(err)
+
+	stateRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+	err = s.storage.AddCommitment(&models.TxCommitment{
+		CommitmentBase: models.CommitmentBase{
+			ID: models.CommitmentID{
+				BatchID:      models.MakeUint256(batchID),
+				IndexInBatch: 0,
+			},
+			Type:          batchtype.Transfer,
+			PostStateRoot: *stateRoot,
+		},
+		CombinedSignature: models.MakeRandomSignature(),
+		BodyHash:          utils.NewRandomHash(),
+	})
+	s.NoError(err)
+}
+
+func (s *PruningTestSuite) getBatchRoot(batchID uint64) common.Hash {
+	commitments, err := s.storage.GetCommitmentsByBatchID(models.MakeUint256(batchID))
+	s.NoError(err)
+	return commitments[0].GetPostStateRoot()
+}
+
+func (s *PruningTestSuite) countStateUpdates() int {
+	count := 0
+	err := s.storage.database.Badger.Iterator(models.StateUpdatePrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
+		count++
+		return false, nil
+	})
+	if err != nil {
+		s.ErrorIs(err, db.ErrIteratorFinished)
+	}
+	return count
+}
+
+func TestPruningTestSuite(t *testing.T) {
+	suite.Run(t, new(PruningTestSuite))
+}
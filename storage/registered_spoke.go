@@ -1,9 +1,10 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type RegisteredSpokeStorage struct {
@@ -30,7 +31,7 @@ func (s *RegisteredSpokeStorage) AddRegisteredSpoke(registeredSpoke *models.Regi
 func (s *RegisteredSpokeStorage) GetRegisteredSpoke(spokeID models.Uint256) (*models.RegisteredSpoke, error) {
 	var registeredSpoke models.RegisteredSpoke
 	err := s.database.Badger.Get(spokeID, &registeredSpoke)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("registered spoke"))
 	}
 	if err != nil {
@@ -39,3 +40,36 @@ func (s *RegisteredSpokeStorage) GetRegisteredSpoke(spokeID models.Uint256) (*mo
 	registeredSpoke.ID = spokeID
 	return &registeredSpoke, nil
 }
+
+func (s *RegisteredSpokeStorage) GetRegisteredSpokes() ([]models.RegisteredSpoke, error) {
+	registeredSpokes := make([]models.RegisteredSpoke, 0, 8)
+	err := s.database.Badger.Iterator(models.RegisteredSpokePrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		registeredSpoke, err := decodeRegisteredSpoke(item)
+		if err != nil {
+			return false, err
+		}
+		registeredSpokes = append(registeredSpokes, *registeredSpoke)
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return registeredSpokes, nil
+}
+
+func decodeRegisteredSpoke(item db.Item) (*models.RegisteredSpoke, error) {
+	var registeredSpoke models.RegisteredSpoke
+	err := item.Value(func(value []byte) error {
+		registeredSpoke.Contract = common.BytesToAddress(value)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = db.DecodeKey(item.Key(), &registeredSpoke.ID, models.RegisteredSpokePrefix)
+	if err != nil {
+		return nil, err
+	}
+	return &registeredSpoke, nil
+}
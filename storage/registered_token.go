@@ -1,9 +1,10 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type RegisteredTokenStorage struct {
@@ -30,7 +31,7 @@ func (s *RegisteredTokenStorage) AddRegisteredToken(registeredToken *models.Regi
 func (s *RegisteredTokenStorage) GetRegisteredToken(tokenID models.Uint256) (*models.RegisteredToken, error) {
 	var registeredToken models.RegisteredToken
 	err := s.database.Badger.Get(tokenID, &registeredToken)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("registered token"))
 	}
 	if err != nil {
@@ -39,3 +40,36 @@ func (s *RegisteredTokenStorage) GetRegisteredToken(tokenID models.Uint256) (*mo
 	registeredToken.ID = tokenID
 	return &registeredToken, nil
 }
+
+func (s *RegisteredTokenStorage) GetRegisteredTokens() ([]models.RegisteredToken, error) {
+	registeredTokens := make([]models.RegisteredToken, 0, 8)
+	err := s.database.Badger.Iterator(models.RegisteredTokenPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		registeredToken, err := decodeRegisteredToken(item)
+		if err != nil {
+			return false, err
+		}
+		registeredTokens = append(registeredTokens, *registeredToken)
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return registeredTokens, nil
+}
+
+func decodeRegisteredToken(item db.Item) (*models.RegisteredToken, error) {
+	var registeredToken models.RegisteredToken
+	err := item.Value(func(value []byte) error {
+		registeredToken.Contract = common.BytesToAddress(value)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = db.DecodeKey(item.Key(), &registeredToken.ID, models.RegisteredTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return &registeredToken, nil
+}
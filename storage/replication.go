@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Worldcoin/hubble-commander/db"
+)
+
+// StreamChanges sends a snapshot of the database followed by its changes, see db.Database.StreamChanges
+func (s *Storage) StreamChanges(ctx context.Context, send func(changes []db.Change, snapshotDone bool) error) error {
+	return s.database.Badger.StreamChanges(ctx, send)
+}
+
+func (s *Storage) ApplyChanges(changes []db.Change) error {
+	return s.database.Badger.ApplyChanges(changes)
+}
+
+func (s *Storage) NewSnapshotWriter() *db.SnapshotWriter {
+	return s.database.Badger.NewSnapshotWriter()
+}
+
+// RefreshTransactionCount recounts the batched transactions, replicas call it as they don't create or sync batches
+func (s *Storage) RefreshTransactionCount() error {
+	count, err := s.getTransactionCount()
+	if err != nil {
+		return err
+	}
+	atomic.StoreUint64(s.batchedTxsCount, *count)
+	return nil
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	schemaVersionKey = []byte("migration:SchemaVersion")
+
+	ErrSchemaVersionTooNew = fmt.Errorf("database schema is newer than the one supported by this binary")
+)
+
+type SchemaMigration struct {
+	Version uint32
+	Name    string
+	run     func(txStorage *Storage) error
+
+	// runInBatches is used instead of run by migrations too big for a single transaction. It commits
+	// its own transactions and must be safe to run again after being interrupted.
+	runInBatches func(storage *Storage) error
+}
+
+// schemaMigrations must be sorted by Version, which starts at 1 and has no gaps. Never change or
+// remove a migration which was released, add a new one instead.
+var schemaMigrations = []SchemaMigration{
+	{
+		Version: 1,
+		Name:    "compute pending balances of public keys from the mempool",
+		run: func(txStorage *Storage) error {
+			return txStorage.MigratePubKeyPendingState()
+		},
+	},
+	{
+		Version: 2,
+		Name:    "index state leaves by token and balance",
+		runInBatches: func(storage *Storage) error {
+			return storage.IndexStateLeaves()
+		},
+	},
+	{
+		Version: 3,
+		Name:    "index batched mass migrations by sender",
+		runInBatches: func(storage *Storage) error {
+			return storage.IndexMassMigrations()
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version of databases written by this binary
+func LatestSchemaVersion() uint32 {
+	return schemaMigrations[len(schemaMigrations)-1].Version
+}
+
+// GetSchemaVersion returns 0 for databases created before schema versioning was introduced
+func (s *Storage) GetSchemaVersion() (uint32, error) {
+	value, err := s.rawLookup(schemaVersionKey)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var version uint32
+	err = stored.DecodeUint32(value, &version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (s *Storage) setSchemaVersion(version uint32) error {
+	return s.rawSet(schemaVersionKey, stored.EncodeUint32(version))
+}
+
+// PendingSchemaMigrations returns the migrations MigrateSchema would run, in order
+func (s *Storage) PendingSchemaMigrations() ([]SchemaMigration, error) {
+	version, err := s.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	return pendingSchemaMigrations(schemaMigrations, version)
+}
+
+func pendingSchemaMigrations(migrations []SchemaMigration, version uint32) ([]SchemaMigration, error) {
+	latestVersion := migrations[len(migrations)-1].Version
+	if version > latestVersion {
+		return nil, errors.WithMessagef(
+			ErrSchemaVersionTooNew,
+			"database version %d, latest supported version %d",
+			version,
+			latestVersion,
+		)
+	}
+	return migrations[version:], nil
+}
+
+// MigrateSchema runs all pending migrations in order. Each migration is committed in its own
+// transaction together with the new schema version, so an interrupted upgrade resumes from
+// the first migration which did not finish. Migrations run in batches bump the version once
+// all of their batches are committed.
+func (s *Storage) MigrateSchema() error {
+	return s.migrateSchema(schemaMigrations)
+}
+
+func (s *Storage) migrateSchema(migrations []SchemaMigration) error {
+	version, err := s.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	pending, err := pendingSchemaMigrations(migrations, version)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		log.Debugf("Database schema is up to date at version %d", version)
+		return nil
+	}
+
+	log.Infof("Migrating database schema from version %d to %d", version, pending[len(pending)-1].Version)
+	for i := range pending {
+		migration := &pending[i]
+		startTime := time.Now()
+		log.Infof("Running schema migration %d/%d: #%d %s", i+1, len(pending), migration.Version, migration.Name)
+
+		err = s.runSchemaMigration(migration)
+		if err != nil {
+			return errors.WithMessagef(err, "schema migration #%d failed", migration.Version)
+		}
+		log.Infof("Finished schema migration #%d in %s", migration.Version, time.Since(startTime).Round(time.Millisecond))
+	}
+	return nil
+}
+
+func (s *Storage) runSchemaMigration(migration *SchemaMigration) error {
+	if migration.runInBatches != nil {
+		err := migration.runInBatches(s)
+		if err != nil {
+			return err
+		}
+		return s.setSchemaVersion(migration.Version)
+	}
+
+	return s.ExecuteInReadWriteTransaction(func(txStorage *Storage) error {
+		err := migration.run(txStorage)
+		if err != nil {
+			return err
+		}
+		return txStorage.setSchemaVersion(migration.Version)
+	})
+}
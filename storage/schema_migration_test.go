@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaMigrationTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *SchemaMigrationTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *SchemaMigrationTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+}
+
+func (s *SchemaMigrationTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *SchemaMigrationTestSuite) TestGetSchemaVersion_NewDatabase() {
+	version, err := s.storage.GetSchemaVersion()
+	s.NoError(err)
+	s.EqualValues(0, version)
+}
+
+func (s *SchemaMigrationTestSuite) TestMigrateSchema_RunsMigrationsInOrder() {
+	ranVersions := make([]uint32, 0)
+	migrations := s.recordingMigrations(&ranVersions, 3)
+
+	err := s.storage.migrateSchema(migrations[:2])
+	s.NoError(err)
+	s.Equal([]uint32{1, 2}, ranVersions)
+
+	err = s.storage.migrateSchema(migrations)
+	s.NoError(err)
+	s.Equal([]uint32{1, 2, 3}, ranVersions)
+
+	version, err := s.storage.GetSchemaVersion()
+	s.NoError(err)
+	s.EqualValues(3, version)
+}
+
+func (s *SchemaMigrationTestSuite) TestMigrateSchema_FailedMigrationIsRetried() {
+	ranVersions := make([]uint32, 0)
+	migrations := s.recordingMigrations(&ranVersions, 2)
+	migrations[1].run = func(txStorage *Storage) error {
+		return errors.New("migration failed")
+	}
+
+	err := s.storage.migrateSchema(migrations)
+	s.ErrorContains(err, "schema migration #2 failed")
+
+	version, err := s.storage.GetSchemaVersion()
+	s.NoError(err)
+	s.EqualValues(1, version)
+
+	pending, err := pendingSchemaMigrations(migrations, version)
+	s.NoError(err)
+	s.Len(pending, 1)
+	s.EqualValues(2, pending[0].Version)
+}
+
+func (s *SchemaMigrationTestSuite) TestMigrateSchema_RefusesNewerDatabase() {
+	err := s.storage.setSchemaVersion(LatestSchemaVersion() + 1)
+	s.NoError(err)
+
+	err = s.storage.MigrateSchema()
+	s.ErrorIs(err, ErrSchemaVersionTooNew)
+
+	_, err = s.storage.PendingSchemaMigrations()
+	s.ErrorIs(err, ErrSchemaVersionTooNew)
+}
+
+func (s *SchemaMigrationTestSuite) TestMigrateSchema_UpToDateDatabase() {
+	err := s.storage.MigrateSchema()
+	s.NoError(err)
+
+	pending, err := s.storage.PendingSchemaMigrations()
+	s.NoError(err)
+	s.Len(pending, 0)
+
+	version, err := s.storage.GetSchemaVersion()
+	s.NoError(err)
+	s.Equal(LatestSchemaVersion(), version)
+}
+
+func (s *SchemaMigrationTestSuite) TestSchemaMigrations_VersionsAreSequential() {
+	for i := range schemaMigrations {
+		s.EqualValues(i+1, schemaMigrations[i].Version)
+	}
+}
+
+func (s *SchemaMigrationTestSuite) recordingMigrations(ranVersions *[]uint32, count uint32) []SchemaMigration {
+	migrations := make([]SchemaMigration, 0, count)
+	for version := uint32(1); version <= count; version++ {
+		version := version
+		migrations = append(migrations, SchemaMigration{
+			Version: version,
+			Name:    "test migration",
+			run: func(txStorage *Storage) error {
+				*ranVersions = append(*ranVersions, version)
+				return nil
+			},
+		})
+	}
+	return migrations
+}
+
+func TestSchemaMigrationTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaMigrationTestSuite))
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+const snapshotImportChunkSize = 1000
+
+var errInvalidSnapshotRecord = fmt.Errorf("snapshot record must hold exactly one item")
+
+// ExportSnapshot streams a snapshot taken at the latest batch finalised before the synced block to the writer
+func (s *Storage) ExportSnapshot(writer io.Writer) (*models.SnapshotHeader, error) {
+	header, postStateRoot, err := s.getSnapshotHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := json.NewEncoder(writer)
+	err = encoder.Encode(header)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	writeRecord := func(record *models.SnapshotRecord) error {
+		return errors.WithStack(encoder.Encode(record))
+	}
+
+	err = s.iterateBatchesUpTo(header.Batch.ID, func(batch *models.Batch) error {
+		return writeRecord(&models.SnapshotRecord{Batch: batch})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.StateTree.IterateLeavesAt(*postStateRoot, func(stateLeaf *models.StateLeaf) error {
+		return writeRecord(&models.SnapshotRecord{StateLeaf: stateLeaf})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.AccountTree.IterateLeaves(func(accountLeaf *models.AccountLeaf) error {
+		return writeRecord(&models.SnapshotRecord{Account: accountLeaf})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.GetRegisteredTokens()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		err = writeRecord(&models.SnapshotRecord{RegisteredToken: &tokens[i]})
+		if err != nil {
+			return nil, err
+		}
+	}
+	spokes, err := s.GetRegisteredSpokes()
+	if err != nil {
+		return nil, err
+	}
+	for i := range spokes {
+		err = writeRecord(&models.SnapshotRecord{RegisteredSpoke: &spokes[i]})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return header, nil
+}
+
+// getSnapshotHeader returns the header of the snapshot and the state root of the snapshot batch
+func (s *Storage) getSnapshotHeader() (*models.SnapshotHeader, *common.Hash, error) {
+	syncedBlock, err := s.GetSyncedBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	batch, err := s.GetLatestFinalisedBatch(uint32(*syncedBlock))
+	if IsNotFoundError(err) || (err == nil && batch.ID.IsZero()) {
+		return nil, nil, errors.WithStack(ErrNoFinalisedBatch)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chainState, err := s.GetChainState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitments, err := s.getStoredCommitmentsByBatchID(batch.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(commitments) == 0 {
+		return nil, nil, errors.WithStack(NewNotFoundError("commitments"))
+	}
+	encodedCommitments := make([]hexutil.Bytes, 0, len(commitments))
+	for i := range commitments {
+		encodedCommitments = append(encodedCommitments, commitments[i].Bytes())
+	}
+
+	lastDepositSubtreeID, err := s.getLastDepositSubtreeID(&batch.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := &models.SnapshotHeader{
+		ChainState:           *chainState,
+		GenesisAccounts:      chainState.GenesisAccounts,
+		Batch:                *batch,
+		Commitments:          encodedCommitments,
+		LastDepositSubtreeID: lastDepositSubtreeID,
+	}
+	return header, &commitments[len(commitments)-1].PostStateRoot, nil
+}
+
+func (s *Storage) iterateBatchesUpTo(batchID models.Uint256, action func(batch *models.Batch) error) error {
+	err := s.database.Badger.Iterator(stored.BatchPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		var storedBatch stored.Batch
+		err := item.Value(func(v []byte) error {
+			return db.Decode(v, &storedBatch)
+		})
+		if err != nil {
+			return false, err
+		}
+		if storedBatch.ID.Cmp(&batchID) > 0 {
+			return true, nil
+		}
+		return false, action(storedBatch.ToModelsBatch())
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) getLastDepositSubtreeID(batchID *models.Uint256) (*models.Uint256, error) {
+	batch, err := s.reverseIterateBatches(func(batch *stored.Batch) bool {
+		return batch.BType == batchtype.Deposit && batch.ID.Cmp(batchID) <= 0
+	})
+	if IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	commitment, err := s.GetCommitment(&models.CommitmentID{
+		BatchID:      batch.ID,
+		IndexInBatch: 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &commitment.ToDepositCommitment().SubtreeID, nil
+}
+
+// SnapshotReader decodes a snapshot streamed by ExportSnapshot, the header has to be read first
+type SnapshotReader struct {
+	decoder *json.Decoder
+}
+
+func NewSnapshotReader(reader io.Reader) *SnapshotReader {
+	return &SnapshotReader{decoder: json.NewDecoder(reader)}
+}
+
+func (r *SnapshotReader) ReadHeader() (*models.SnapshotHeader, error) {
+	var header models.SnapshotHeader
+	err := r.decoder.Decode(&header)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &header, nil
+}
+
+// readRecords returns at most count records, fewer only at the end of the snapshot
+func (r *SnapshotReader) readRecords(count int) ([]models.SnapshotRecord, error) {
+	records := make([]models.SnapshotRecord, 0, count)
+	for len(records) < count {
+		var record models.SnapshotRecord
+		err := r.decoder.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ImportSnapshot saves the data of the snapshot into an empty database. The chain state is not saved,
+// it's up to the caller to set it once the snapshot is verified against the chain.
+func (s *Storage) ImportSnapshot(header *models.SnapshotHeader, reader *SnapshotReader) error {
+	for i := range header.Commitments {
+		var commitment stored.Commitment
+		err := commitment.SetBytes(header.Commitments[i])
+		if err != nil {
+			return err
+		}
+		err = s.database.Badger.Insert(commitment.ID, commitment)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		records, err := reader.readRecords(snapshotImportChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		err = s.ExecuteInReadWriteTransaction(func(txStorage *Storage) error {
+			for i := range records {
+				err := txStorage.importSnapshotRecord(&records[i])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// DropImportedSnapshot removes the data of a snapshot which failed to import or verify, so that the
+// import can be retried. It wipes the whole database, which has to be empty before the import.
+func (s *Storage) DropImportedSnapshot() error {
+	return s.database.Badger.Prune()
+}
+
+func (s *Storage) importSnapshotRecord(record *models.SnapshotRecord) error {
+	switch {
+	case record.Batch != nil:
+		return s.AddBatch(record.Batch)
+	case record.StateLeaf != nil:
+		return s.StateTree.setWithoutStateUpdate(record.StateLeaf.StateID, &record.StateLeaf.UserState)
+	case record.Account != nil:
+		_, err := s.AccountTree.unsafeSet(record.Account)
+		return err
+	case record.RegisteredToken != nil:
+		return s.AddRegisteredToken(record.RegisteredToken)
+	case record.RegisteredSpoke != nil:
+		return s.AddRegisteredSpoke(record.RegisteredSpoke)
+	default:
+		return errors.WithStack(errInvalidSnapshotRecord)
+	}
+}
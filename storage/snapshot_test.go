@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/enums/batchtype"
+	"github.com/Worldcoin/hubble-commander/utils"
+	"github.com/Worldcoin/hubble-commander/utils/ref"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SnapshotTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *SnapshotTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+}
+
+func (s *SnapshotTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *SnapshotTestSuite) TestLeavesAt_UndoesStateUpdates() {
+	s.setStateLeaf(0, 100)
+	s.setStateLeaf(1, 200)
+	root, err := s.storage.StateTree.Root()
+	s.NoError(err)
+	expectedLeaves := s.getStateLeaves()
+
+	s.setStateLeaf(0, 150)
+	s.setStateLeaf(2, 300)
+
+	s.Equal(expectedLeaves, s.getStateLeavesAt(*root))
+}
+
+func (s *SnapshotTestSuite) TestLeavesAt_NonexistentState() {
+	s.setStateLeaf(0, 100)
+
+	err := s.storage.StateTree.IterateLeavesAt(utils.RandomHash(), func(stateLeaf *models.StateLeaf) error {
+		return nil
+	})
+	s.ErrorIs(err, ErrNonexistentState)
+}
+
+func (s *SnapshotTestSuite) TestExportSnapshot_NoFinalisedBatch() {
+	err := s.storage.SetSyncedBlock(10)
+	s.NoError(err)
+	s.addBatch(0, batchtype.Genesis, 5)
+	s.addBatch(1, batchtype.Transfer, 20)
+
+	_, err = s.storage.ExportSnapshot(&bytes.Buffer{})
+	s.ErrorIs(err, ErrNoFinalisedBatch)
+}
+
+func (s *SnapshotTestSuite) TestExportSnapshot_ImportSnapshot() {
+	err := s.storage.SetChainState(&models.ChainState{ChainID: models.MakeUint256(1337), Rollup: utils.RandomAddress()})
+	s.NoError(err)
+	err = s.storage.SetSyncedBlock(25)
+	s.NoError(err)
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{PubKeyID: 0, PublicKey: models.PublicKey{1, 2, 3}})
+	s.NoError(err)
+	err = s.storage.AddRegisteredToken(&models.RegisteredToken{ID: models.MakeUint256(0), Contract: utils.RandomAddress()})
+	s.NoError(err)
+	err = s.storage.AddRegisteredSpoke(&models.RegisteredSpoke{ID: models.MakeUint256(1), Contract: utils.RandomAddress()})
+	s.NoError(err)
+
+	s.addBatch(0, batchtype.Genesis, 5)
+	s.setStateLeaf(0, 100)
+	s.setStateLeaf(1, 200)
+	s.addBatch(1, batchtype.Transfer, 20)
+	s.addTxCommitment(1)
+	expectedLeaves := s.getStateLeaves()
+
+	s.setStateLeaf(1, 250)
+	s.addBatch(2, batchtype.Transfer, 30)
+	s.addTxCommitment(2)
+
+	var snapshot bytes.Buffer
+	header, err := s.storage.ExportSnapshot(&snapshot)
+	s.NoError(err)
+	s.EqualValues(1, header.Batch.ID.Uint64())
+	s.Len(header.Commitments, 1)
+	s.Nil(header.LastDepositSubtreeID)
+
+	records := s.decodeSnapshotRecords(snapshot.Bytes())
+	s.Len(records.batches, 2)
+	// data hashes are not encoded, they are recomputed on import
+	for i := range expectedLeaves {
+		expectedLeaves[i].DataHash = common.Hash{}
+	}
+	s.Equal(expectedLeaves, records.stateLeaves)
+	s.Len(records.accounts, 1)
+	s.Len(records.tokens, 1)
+	s.Len(records.spokes, 1)
+
+	imported, err := NewTestStorage()
+	s.NoError(err)
+	defer func() {
+		s.NoError(imported.Teardown())
+	}()
+
+	reader := NewSnapshotReader(&snapshot)
+	header, err = reader.ReadHeader()
+	s.NoError(err)
+	err = imported.ImportSnapshot(header, reader)
+	s.NoError(err)
+
+	batches, err := imported.GetBatchesInRange(nil, nil)
+	s.NoError(err)
+	s.Equal(records.batches, batches)
+
+	commitments, err := imported.GetCommitmentsByBatchID(models.MakeUint256(1))
+	s.NoError(err)
+	s.Len(commitments, 1)
+	stateRoot, err := imported.StateTree.Root()
+	s.NoError(err)
+	s.Equal(commitments[0].GetPostStateRoot(), *stateRoot)
+
+	expectedAccountRoot, err := s.storage.AccountTree.Root()
+	s.NoError(err)
+	accountRoot, err := imported.AccountTree.Root()
+	s.NoError(err)
+	s.Equal(expectedAccountRoot, accountRoot)
+
+	tokens, err := imported.GetRegisteredTokens()
+	s.NoError(err)
+	s.Equal(records.tokens, tokens)
+	spokes, err := imported.GetRegisteredSpokes()
+	s.NoError(err)
+	s.Equal(records.spokes, spokes)
+}
+
+func (s *SnapshotTestSuite) TestImportSnapshot_InvalidRecord() {
+	reader := NewSnapshotReader(bytes.NewBufferString("{}\n"))
+	err := s.storage.ImportSnapshot(&models.SnapshotHeader{}, reader)
+	s.ErrorIs(err, errInvalidSnapshotRecord)
+}
+
+func (s *SnapshotTestSuite) setStateLeaf(stateID uint32, balance uint64) {
+	_, err := s.storage.StateTree.Set(stateID, &models.UserState{
+		PubKeyID: stateID,
+		TokenID:  models.MakeUint256(0),
+		Balance:  models.MakeUint256(balance),
+		Nonce:    models.MakeUint256(0),
+	})
+	s.NoError(err)
+}
+
+type snapshotRecords struct {
+	batches     []models.Batch
+	stateLeaves []models.StateLeaf
+	accounts    []models.AccountLeaf
+	tokens      []models.RegisteredToken
+	spokes      []models.RegisteredSpoke
+}
+
+func (s *SnapshotTestSuite) decodeSnapshotRecords(snapshot []byte) *snapshotRecords {
+	decoder := json.NewDecoder(bytes.NewReader(snapshot))
+	var header models.SnapshotHeader
+	s.NoError(decoder.Decode(&header))
+
+	records := &snapshotRecords{}
+	for decoder.More() {
+		var record models.SnapshotRecord
+		s.NoError(decoder.Decode(&record))
+		switch {
+		case record.Batch != nil:
+			records.batches = append(records.batches, *record.Batch)
+		case record.StateLeaf != nil:
+			records.stateLeaves = append(records.stateLeaves, *record.StateLeaf)
+		case record.Account != nil:
+			records.accounts = append(records.accounts, *record.Account)
+		case record.RegisteredToken != nil:
+			records.tokens = append(records.tokens, *record.RegisteredToken)
+		case record.RegisteredSpoke != nil:
+			records.spokes = append(records.spokes, *record.RegisteredSpoke)
+		}
+	}
+	return records
+}
+
+func (s *SnapshotTestSuite) getStateLeavesAt(root common.Hash) []models.StateLeaf {
+	leaves := make([]models.StateLeaf, 0, 2)
+	err := s.storage.StateTree.IterateLeavesAt(root, func(stateLeaf *models.StateLeaf) error {
+		leaves = append(leaves, *stateLeaf)
+		return nil
+	})
+	s.NoError(err)
+	return leaves
+}
+
+func (s *SnapshotTestSuite) getStateLeaves() []models.StateLeaf {
+	leaves := make([]models.StateLeaf, 0, 2)
+	err := s.storage.StateTree.IterateLeaves(func(stateLeaf *models.StateLeaf) error {
+		leaves = append(leaves, *stateLeaf)
+		return nil
+	})
+	s.NoError(err)
+	return leaves
+}
+
+func (s *SnapshotTestSuite) addBatch(batchID uint64, batchType batchtype.BatchType, finalisationBlock uint32) {
+	err := s.storage.AddBatch(&models.Batch{
+		ID:                models.MakeUint256(batchID),
+		Type:              batchType,
+		TransactionHash:   utils.RandomHash(),
+		Hash:              utils.NewRandomHash(),
+		FinalisationBlock: ref.Uint32(finalisationBlock),
+	})
+	s.NoError(err)
+}
+
+func (s *SnapshotTestSuite) addTxCommitment(batchID uint64) {
+	stateRoot, err := s.storage.StateTree.Root()
+	s.NoError(err)
+
+	err = s.storage.AddCommitment(&models.TxCommitment{
+		CommitmentBase: models.CommitmentBase{
+			ID: models.CommitmentID{
+				BatchID:      models.MakeUint256(batchID),
+				IndexInBatch: 0,
+			},
+			Type:          batchtype.Transfer,
+			PostStateRoot: *stateRoot,
+		},
+		FeeReceiver:       0,
+		CombinedSignature: models.MakeRandomSignature(),
+		BodyHash:          utils.NewRandomHash(),
+	})
+	s.NoError(err)
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
@@ -1,16 +1,29 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/Worldcoin/hubble-commander/utils"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (s *StateTree) upsertStateLeaf(leaf *models.StateLeaf) error {
+	var prevLeaf *stored.FlatStateLeaf
+	var storedPrevLeaf stored.FlatStateLeaf
+	err := s.database.Badger.Get(leaf.StateID, &storedPrevLeaf)
+	if err == nil {
+		prevLeaf = &storedPrevLeaf
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return errors.WithStack(err)
+	}
+
 	storedLeaf := stored.MakeStateLeaf(leaf)
-	return s.database.Badger.Upsert(leaf.StateID, storedLeaf)
+	err = s.database.Badger.Upsert(leaf.StateID, storedLeaf)
+	if err != nil {
+		return err
+	}
+	return s.updateStateLeafIndexes(prevLeaf, &storedLeaf)
 }
 
 func (s *Storage) GetStateLeavesByPublicKey(publicKey *models.PublicKey) (stateLeaves []models.StateLeaf, err error) {
@@ -24,7 +37,7 @@ func (s *Storage) GetStateLeavesByPublicKey(publicKey *models.PublicKey) (stateL
 	storedStateLeaves := make([]stored.FlatStateLeaf, 0, 1)
 	err = s.database.Badger.Find(
 		&storedStateLeaves,
-		bh.Where("PubKeyID").In(pubKeyIDs...).Index("PubKeyID").SortBy("StateID"),
+		db.Where("PubKeyID").In(pubKeyIDs...).Index("PubKeyID").SortBy("StateID"),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"bytes"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/Worldcoin/hubble-commander/utils/merkletree"
+	"github.com/pkg/errors"
+)
+
+// The state leaves are indexed by token and by token and balance with raw keys, one per leaf. BadgerHold
+// indexes keep a single list of keys per value, which would have to be rewritten on every balance change
+// of e.g. the millions of leaves with zero balance.
+
+var (
+	stateLeafByTokenPrefix   = []byte("StateLeafByToken")
+	stateLeafByBalancePrefix = []byte("StateLeafByBalance")
+)
+
+const stateLeafIndexMigrationBatchSize = 10_000
+
+func stateLeafByTokenPrefixOf(tokenID *models.Uint256) []byte {
+	return bytes.Join(
+		[][]byte{stateLeafByTokenPrefix, tokenID.Bytes(), {}},
+		[]byte(":"),
+	)
+}
+
+func stateLeafByTokenKey(tokenID *models.Uint256, stateID uint32) []byte {
+	return append(stateLeafByTokenPrefixOf(tokenID), stored.EncodeUint32(stateID)...)
+}
+
+func stateLeafByBalancePrefixOf(tokenID *models.Uint256) []byte {
+	return bytes.Join(
+		[][]byte{stateLeafByBalancePrefix, tokenID.Bytes(), {}},
+		[]byte(":"),
+	)
+}
+
+func stateLeafByBalanceKey(tokenID, balance *models.Uint256, stateID uint32) []byte {
+	return bytes.Join(
+		[][]byte{stateLeafByBalancePrefixOf(tokenID), balance.Bytes(), stored.EncodeUint32(stateID)},
+		nil,
+	)
+}
+
+// isIndexedStateLeaf is false for the empty leaves written back when reverting the creation of a state
+func isIndexedStateLeaf(leaf *stored.FlatStateLeaf) bool {
+	return leaf.DataHash != merkletree.GetZeroHash(0)
+}
+
+func setStateLeafIndexes(txn db.Txn, leaf *stored.FlatStateLeaf) error {
+	if !isIndexedStateLeaf(leaf) {
+		return nil
+	}
+	value := stored.EncodeUint32(leaf.StateID)
+	err := txn.Set(stateLeafByTokenKey(&leaf.TokenID, leaf.StateID), value)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(txn.Set(stateLeafByBalanceKey(&leaf.TokenID, &leaf.Balance, leaf.StateID), value))
+}
+
+func deleteStateLeafIndexes(txn db.Txn, leaf *stored.FlatStateLeaf) error {
+	if !isIndexedStateLeaf(leaf) {
+		return nil
+	}
+	err := txn.Delete(stateLeafByTokenKey(&leaf.TokenID, leaf.StateID))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(txn.Delete(stateLeafByBalanceKey(&leaf.TokenID, &leaf.Balance, leaf.StateID)))
+}
+
+func (s *StateTree) updateStateLeafIndexes(prevLeaf, leaf *stored.FlatStateLeaf) error {
+	if prevLeaf != nil && prevLeaf.TokenID == leaf.TokenID && prevLeaf.Balance == leaf.Balance &&
+		isIndexedStateLeaf(prevLeaf) == isIndexedStateLeaf(leaf) {
+		return nil
+	}
+
+	return s.database.Badger.RawUpdate(func(txn db.Txn) error {
+		if prevLeaf != nil {
+			err := deleteStateLeafIndexes(txn, prevLeaf)
+			if err != nil {
+				return err
+			}
+		}
+		return setStateLeafIndexes(txn, leaf)
+	})
+}
+
+// GetStateLeavesByTokenID returns up to limit leaves of the token ordered by state ID, starting at startStateID,
+// together with the state ID the next page starts at, which is nil on the last page
+func (s *Storage) GetStateLeavesByTokenID(
+	tokenID models.Uint256,
+	startStateID, limit uint32,
+) (leaves []models.StateLeaf, nextStateID *uint32, err error) {
+	err = s.ExecuteInTransaction(TxOptions{ReadOnly: true}, func(txStorage *Storage) error {
+		prefix := stateLeafByTokenPrefixOf(&tokenID)
+		stateIDs, next, innerErr := txStorage.indexedStateIDs(prefix, stateLeafByTokenKey(&tokenID, startStateID), limit, false)
+		if innerErr != nil {
+			return innerErr
+		}
+		nextStateID = next
+
+		leaves, innerErr = txStorage.stateLeaves(stateIDs)
+		return innerErr
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return leaves, nextStateID, nil
+}
+
+// GetRichestStateLeaves returns up to limit leaves of the token with the highest balances, in descending order
+func (s *Storage) GetRichestStateLeaves(tokenID models.Uint256, limit uint32) (leaves []models.StateLeaf, err error) {
+	err = s.ExecuteInTransaction(TxOptions{ReadOnly: true}, func(txStorage *Storage) error {
+		prefix := stateLeafByBalancePrefixOf(&tokenID)
+		stateIDs, _, innerErr := txStorage.indexedStateIDs(prefix, prefix, limit, true)
+		if innerErr != nil {
+			return innerErr
+		}
+
+		leaves, innerErr = txStorage.stateLeaves(stateIDs)
+		return innerErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+func (s *Storage) GetStateLeavesByPubKeyID(pubKeyID uint32) ([]models.StateLeaf, error) {
+	storedStateLeaves := make([]stored.FlatStateLeaf, 0, 1)
+	err := s.database.Badger.Find(
+		&storedStateLeaves,
+		db.Where("PubKeyID").Eq(pubKeyID).Index("PubKeyID").SortBy("StateID"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stateLeaves := make([]models.StateLeaf, 0, len(storedStateLeaves))
+	for i := range storedStateLeaves {
+		stateLeaves = append(stateLeaves, *storedStateLeaves[i].ToModelsStateLeaf())
+	}
+	return stateLeaves, nil
+}
+
+// indexedStateIDs reads up to limit state IDs, all of them when limit is 0, from the index entries under prefix
+// starting at seekKey, and returns the state ID of the entry following them
+func (s *Storage) indexedStateIDs(
+	prefix, seekKey []byte,
+	limit uint32,
+	reverse bool,
+) (stateIDs []uint32, nextStateID *uint32, err error) {
+	opts := db.PrefetchIteratorOpts
+	if reverse {
+		opts = db.ReversePrefetchIteratorOpts
+		// sorts after every entry under the prefix, which ends with a balance and a state ID
+		seekKey = append(append([]byte{}, seekKey...), bytes.Repeat([]byte{0xFF}, 37)...)
+	}
+
+	stateIDs = make([]uint32, 0, limit)
+	err = s.database.Badger.View(func(txn db.Txn) error {
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(seekKey); it.ValidForPrefix(prefix); it.Next() {
+			var stateID uint32
+			innerErr := it.Item().Value(func(value []byte) error {
+				return stored.DecodeUint32(value, &stateID)
+			})
+			if innerErr != nil {
+				return innerErr
+			}
+			if limit != 0 && uint32(len(stateIDs)) == limit {
+				nextStateID = &stateID
+				return nil
+			}
+			stateIDs = append(stateIDs, stateID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateIDs, nextStateID, nil
+}
+
+func (s *Storage) stateLeaves(stateIDs []uint32) ([]models.StateLeaf, error) {
+	leaves := make([]models.StateLeaf, 0, len(stateIDs))
+	for _, stateID := range stateIDs {
+		leaf, err := s.StateTree.Leaf(stateID)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, *leaf)
+	}
+	return leaves, nil
+}
+
+// IndexStateLeaves builds the token and balance indexes of the leaves stored before they were introduced.
+// Leaves are indexed in batches, running it again just rewrites the same entries.
+func (s *Storage) IndexStateLeaves() error {
+	batch := make([]stored.FlatStateLeaf, 0, stateLeafIndexMigrationBatchSize)
+	writeBatch := func() error {
+		err := s.database.Badger.RawUpdate(func(txn db.Txn) error {
+			for i := range batch {
+				err := setStateLeafIndexes(txn, &batch[i])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+
+	err := s.StateTree.IterateLeaves(func(stateLeaf *models.StateLeaf) error {
+		batch = append(batch, stored.MakeStateLeaf(stateLeaf))
+		if len(batch) < stateLeafIndexMigrationBatchSize {
+			return nil
+		}
+		return writeBatch()
+	})
+	if err != nil {
+		return err
+	}
+	return writeBatch()
+}
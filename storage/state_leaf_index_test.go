@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type StateLeafIndexTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *StateLeafIndexTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *StateLeafIndexTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+
+	s.setState(0, 1, 100)
+	s.setState(1, 2, 500)
+	s.setState(2, 1, 300)
+	s.setState(3, 1, 0)
+	s.setState(4, 1, 300)
+}
+
+func (s *StateLeafIndexTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *StateLeafIndexTestSuite) TestGetStateLeavesByTokenID_Paginates() {
+	leaves, next, err := s.storage.GetStateLeavesByTokenID(models.MakeUint256(1), 0, 2)
+	s.NoError(err)
+	s.Equal([]uint32{0, 2}, stateIDs(leaves))
+	s.EqualValues(3, *next)
+
+	leaves, next, err = s.storage.GetStateLeavesByTokenID(models.MakeUint256(1), *next, 2)
+	s.NoError(err)
+	s.Equal([]uint32{3, 4}, stateIDs(leaves))
+	s.Nil(next)
+}
+
+func (s *StateLeafIndexTestSuite) TestGetRichestStateLeaves_SortsByBalanceDescending() {
+	leaves, err := s.storage.GetRichestStateLeaves(models.MakeUint256(1), 3)
+	s.NoError(err)
+	s.Equal([]uint32{4, 2, 0}, stateIDs(leaves))
+}
+
+func (s *StateLeafIndexTestSuite) TestGetRichestStateLeaves_FollowsBalanceChanges() {
+	s.setState(3, 1, 1000)
+
+	leaves, err := s.storage.GetRichestStateLeaves(models.MakeUint256(1), 0)
+	s.NoError(err)
+	s.Equal([]uint32{3, 4, 2, 0}, stateIDs(leaves))
+	s.Equal(models.MakeUint256(1000), leaves[0].Balance)
+}
+
+func (s *StateLeafIndexTestSuite) TestRevertTo_RemovesRevertedStatesFromIndexes() {
+	root, err := s.storage.StateTree.Root()
+	s.NoError(err)
+
+	s.setState(5, 1, 2000)
+	err = s.storage.StateTree.RevertTo(*root)
+	s.NoError(err)
+
+	leaves, _, err := s.storage.GetStateLeavesByTokenID(models.MakeUint256(1), 5, 0)
+	s.NoError(err)
+	s.Len(leaves, 0)
+	leaves, err = s.storage.GetRichestStateLeaves(models.MakeUint256(1), 1)
+	s.NoError(err)
+	s.Equal([]uint32{4}, stateIDs(leaves))
+}
+
+func (s *StateLeafIndexTestSuite) TestIndexStateLeaves_IndexesExistingLeaves() {
+	err := s.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
+		return s.storage.StateTree.IterateLeaves(func(stateLeaf *models.StateLeaf) error {
+			storedLeaf := stored.MakeStateLeaf(stateLeaf)
+			return deleteStateLeafIndexes(txn, &storedLeaf)
+		})
+	})
+	s.NoError(err)
+
+	leaves, _, err := s.storage.GetStateLeavesByTokenID(models.MakeUint256(1), 0, 0)
+	s.NoError(err)
+	s.Len(leaves, 0)
+
+	err = s.storage.IndexStateLeaves()
+	s.NoError(err)
+
+	leaves, _, err = s.storage.GetStateLeavesByTokenID(models.MakeUint256(1), 0, 0)
+	s.NoError(err)
+	s.Equal([]uint32{0, 2, 3, 4}, stateIDs(leaves))
+}
+
+func (s *StateLeafIndexTestSuite) TestGetStateLeavesByPubKeyID() {
+	leaves, err := s.storage.GetStateLeavesByPubKeyID(4)
+	s.NoError(err)
+	s.Equal([]uint32{4}, stateIDs(leaves))
+}
+
+func (s *StateLeafIndexTestSuite) setState(stateID uint32, tokenID, balance uint64) {
+	_, err := s.storage.StateTree.Set(stateID, &models.UserState{
+		PubKeyID: stateID,
+		TokenID:  models.MakeUint256(tokenID),
+		Balance:  models.MakeUint256(balance),
+		Nonce:    models.MakeUint256(0),
+	})
+	s.NoError(err)
+}
+
+func stateIDs(leaves []models.StateLeaf) []uint32 {
+	ids := make([]uint32, 0, len(leaves))
+	for i := range leaves {
+		ids = append(ids, leaves[i].StateID)
+	}
+	return ids
+}
+
+func TestStateLeafIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(StateLeafIndexTestSuite))
+}
@@ -7,10 +7,8 @@ import (
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/Worldcoin/hubble-commander/utils/merkletree"
 	"github.com/Worldcoin/hubble-commander/utils/ref"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 const (
@@ -41,7 +39,7 @@ func (s *StateTree) Root() (*common.Hash, error) {
 func (s *StateTree) Leaf(stateID uint32) (stateLeaf *models.StateLeaf, err error) {
 	var storedLeaf stored.FlatStateLeaf
 	err = s.database.Badger.Get(stateID, &storedLeaf)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("state leaf"))
 	}
 	if err != nil {
@@ -76,7 +74,7 @@ func (s *StateTree) NextVacantSubtree(subtreeDepth uint8) (*uint32, error) {
 	// The iterator will scan over the state tree left-to-right detecting any gaps along the way.
 	// If a gap is detected its checked if its suitable for the given subtree regarding both alignment and size.
 	// An iterator will return the index of the first such gap it detects.
-	err := s.database.Badger.Iterator(stored.StateLeafPrefix, db.KeyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(stored.StateLeafPrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
 		var key uint32
 		err := db.DecodeKey(item.Key(), &key, stored.StateLeafPrefix)
 		if err != nil {
@@ -159,7 +157,7 @@ func (s *StateTree) RevertTo(targetRootHash common.Hash) error {
 	return s.database.ExecuteInTransaction(TxOptions{}, func(txDatabase *Database) (err error) {
 		stateTree := NewStateTree(txDatabase)
 
-		err = txDatabase.Badger.Iterator(models.StateUpdatePrefix, db.ReversePrefetchIteratorOpts, func(item *bdg.Item) (bool, error) {
+		err = txDatabase.Badger.Iterator(models.StateUpdatePrefix, db.ReversePrefetchIteratorOpts, func(item db.Item) (bool, error) {
 			var stateUpdate *models.StateUpdate
 			stateUpdate, err = decodeStateUpdate(item)
 			if err != nil {
@@ -186,7 +184,50 @@ func (s *StateTree) RevertTo(targetRootHash common.Hash) error {
 	})
 }
 
-func decodeStateUpdate(item *bdg.Item) (*models.StateUpdate, error) {
+// IterateLeavesAt calls action with the non-empty leaves of the state tree as they were when its root was equal to
+// targetRootHash. Unlike RevertTo it doesn't modify the database, state updates are undone in memory.
+func (s *StateTree) IterateLeavesAt(targetRootHash common.Hash, action func(stateLeaf *models.StateLeaf) error) error {
+	currentRootHash, err := s.Root()
+	if err != nil {
+		return err
+	}
+
+	prevLeaves := make(map[uint32]*models.StateLeaf)
+	if *currentRootHash != targetRootHash {
+		err = s.database.Badger.Iterator(models.StateUpdatePrefix, db.ReversePrefetchIteratorOpts, func(item db.Item) (bool, error) {
+			stateUpdate, err := decodeStateUpdate(item)
+			if err != nil {
+				return false, err
+			}
+			if stateUpdate.CurrentRoot != *currentRootHash {
+				return false, errors.WithStack(ErrInconsistentStateUpdates)
+			}
+
+			prevLeaves[stateUpdate.PrevStateLeaf.StateID] = &stateUpdate.PrevStateLeaf
+			currentRootHash = &stateUpdate.PrevRoot
+			return *currentRootHash == targetRootHash, nil
+		})
+		if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+			return err
+		}
+		if *currentRootHash != targetRootHash {
+			return errors.WithStack(ErrNonexistentState)
+		}
+	}
+
+	emptyLeafHash := merkletree.GetZeroHash(0)
+	return s.IterateLeaves(func(stateLeaf *models.StateLeaf) error {
+		if prevLeaf, ok := prevLeaves[stateLeaf.StateID]; ok {
+			stateLeaf = prevLeaf
+		}
+		if stateLeaf.DataHash == emptyLeafHash {
+			return nil
+		}
+		return action(stateLeaf)
+	})
+}
+
+func decodeStateUpdate(item db.Item) (*models.StateUpdate, error) {
 	var stateUpdate models.StateUpdate
 	err := item.Value(func(v []byte) error {
 		return db.Decode(v, &stateUpdate)
@@ -240,11 +281,28 @@ func (s *StateTree) unsafeSet(index uint32, state *models.UserState) (models.Wit
 	return witness, nil
 }
 
+// setWithoutStateUpdate doesn't record a state update so the change can't be reverted
+func (s *StateTree) setWithoutStateUpdate(index uint32, state *models.UserState) error {
+	leaf, err := NewStateLeaf(index, state)
+	if err != nil {
+		return err
+	}
+
+	err = s.upsertStateLeaf(leaf)
+	if err != nil {
+		return err
+	}
+
+	leafPath := models.MakeMerklePathFromLeafID(index)
+	_, _, err = s.merkleTree.SetNode(&leafPath, leaf.DataHash)
+	return err
+}
+
 func (s *StateTree) getLeafByPubKeyIDAndTokenID(pubKeyID uint32, tokenID models.Uint256) (*models.StateLeaf, error) {
 	stateLeaves := make([]stored.FlatStateLeaf, 0, 1)
 	err := s.database.Badger.Find(
 		&stateLeaves,
-		bh.Where("PubKeyID").Eq(pubKeyID).Index("PubKeyID").And("TokenID").Eq(tokenID),
+		db.Where("PubKeyID").Eq(pubKeyID).Index("PubKeyID").And("TokenID").Eq(tokenID),
 	)
 	if err != nil {
 		return nil, err
@@ -280,7 +338,7 @@ func (s *StateTree) revertState(stateUpdate *models.StateUpdate) (*common.Hash,
 }
 
 func (s *StateTree) IterateLeaves(action func(stateLeaf *models.StateLeaf) error) error {
-	err := s.database.Badger.Iterator(stored.StateLeafPrefix, db.PrefetchIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(stored.StateLeafPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
 		var stateLeaf stored.FlatStateLeaf
 		err := item.Value(stateLeaf.SetBytes)
 		if err != nil {
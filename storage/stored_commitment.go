@@ -4,9 +4,7 @@ import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/stored"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type CommitmentStorage struct {
@@ -29,7 +27,7 @@ func (s *CommitmentStorage) copyWithNewDatabase(database *Database) *CommitmentS
 func (s *CommitmentStorage) getStoredCommitment(id *models.CommitmentID) (*stored.Commitment, error) {
 	storedCommitment := new(stored.Commitment)
 	err := s.database.Badger.Get(*id, storedCommitment)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("commitment"))
 	}
 	if err != nil {
@@ -41,7 +39,7 @@ func (s *CommitmentStorage) getStoredCommitment(id *models.CommitmentID) (*store
 func (s *CommitmentStorage) GetLatestCommitment() (*models.CommitmentBase, error) {
 	var storedCommitment *stored.Commitment
 	var err error
-	err = s.database.Badger.Iterator(stored.CommitmentPrefix, db.ReverseKeyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err = s.database.Badger.Iterator(stored.CommitmentPrefix, db.ReverseKeyIteratorOpts, func(item db.Item) (bool, error) {
 		storedCommitment, err = decodeStoredCommitment(item)
 		return true, err
 	})
@@ -84,7 +82,7 @@ func (s *CommitmentStorage) RemoveCommitmentsByBatchIDs(batchIDs ...models.Uint2
 func (s *CommitmentStorage) getStoredCommitmentsByBatchID(batchID models.Uint256) ([]stored.Commitment, error) {
 	storedCommitments := make([]stored.Commitment, 0, 32)
 	prefix := getCommitmentPrefixWithBatchID(&batchID)
-	err := s.database.Badger.Iterator(prefix, bdg.DefaultIteratorOptions, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(prefix, db.DefaultIteratorOptions, func(item db.Item) (bool, error) {
 		commitment, err := decodeStoredCommitment(item)
 		if err != nil {
 			return false, err
@@ -98,7 +96,7 @@ func (s *CommitmentStorage) getStoredCommitmentsByBatchID(batchID models.Uint256
 	return storedCommitments, nil
 }
 
-func decodeStoredCommitment(item *bdg.Item) (*stored.Commitment, error) {
+func decodeStoredCommitment(item db.Item) (*stored.Commitment, error) {
 	var storedCommitment stored.Commitment
 	err := item.Value(storedCommitment.SetBytes)
 	if err != nil {
@@ -110,7 +108,7 @@ func decodeStoredCommitment(item *bdg.Item) (*stored.Commitment, error) {
 func getCommitmentIDsByBatchID(txn *Database, batchID models.Uint256) ([]models.CommitmentID, error) {
 	ids := make([]models.CommitmentID, 0, 32)
 	prefix := getCommitmentPrefixWithBatchID(&batchID)
-	err := txn.Badger.Iterator(prefix, db.KeyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := txn.Badger.Iterator(prefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
 		var id models.CommitmentID
 		err := db.DecodeKey(item.Key(), &id, stored.CommitmentPrefix)
 		if err != nil {
@@ -1,12 +1,12 @@
 package storage
 
 import (
+	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/utils"
 	"github.com/Worldcoin/hubble-commander/utils/merkletree"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 var ErrExceededTreeDepth = errors.New("node depth exceeds the tree depth")
@@ -32,7 +32,7 @@ func (s *StoredMerkleTree) keyFor(path models.MerklePath) models.NamespacedMerkl
 func (s *StoredMerkleTree) Get(path models.MerklePath) (*models.MerkleTreeNode, error) {
 	node := models.MerkleTreeNode{MerklePath: path}
 	err := s.database.Badger.Get(s.keyFor(path), &node)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return s.newZeroNode(&path), nil
 	}
 	if err != nil {
@@ -11,11 +11,9 @@ import (
 	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
 	"github.com/Worldcoin/hubble-commander/models/stored"
 	"github.com/Worldcoin/hubble-commander/utils/ref"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 type TransactionStorage struct {
@@ -61,7 +59,7 @@ func (s *TransactionStorage) updateInMultipleTransactions(operations []dbOperati
 
 	for _, op := range operations {
 		err = op(txStorage)
-		if errors.Is(err, bdg.ErrTxnTooBig) {
+		if errors.Is(err, db.ErrTxnTooBig) {
 			// Commit and start new DB transaction
 			err = txController.Commit()
 			if err != nil {
@@ -101,6 +99,10 @@ func (s *TransactionStorage) unsafeMarkTransactionAsPending(txSlot *models.Commi
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	err = s.deleteMassMigrationIndex(&batchedTx)
+	if err != nil {
+		return err
+	}
 
 	s.decrementTransactionCount()
 
@@ -178,7 +180,7 @@ func (s *Storage) unsafeGetTransactionCount() (*uint64, error) {
 
 	count, err := s.database.Badger.Count(
 		&stored.BatchedTx{},
-		bh.Where("ID.BatchID").Le(latestBatch.ID),
+		db.Where("ID.BatchID").Le(latestBatch.ID),
 	)
 	if err != nil {
 		return nil, err
@@ -202,7 +204,7 @@ func (s *TransactionStorage) getTransactionIDsByBatchID(batchID models.Uint256)
 	// BatchID.
 
 	var id models.CommitmentSlot
-	err := s.database.Badger.Iterator(seekPrefix, db.KeyIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(seekPrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
 		err := db.DecodeKey(item.Key(), &id, stored.BatchedTxPrefix)
 		if err != nil {
 			return false, err
@@ -338,7 +340,11 @@ func (s *TransactionStorage) MarkTransactionsAsIncluded(
 
 func (s *TransactionStorage) insertBatchedTx(batchedTx *stored.BatchedTx) error {
 	key := batchedTx.ID
-	return s.database.Badger.Insert(key, *batchedTx)
+	err := s.database.Badger.Insert(key, *batchedTx)
+	if err != nil {
+		return err
+	}
+	return s.setMassMigrationIndex(batchedTx)
 }
 
 func (s *TransactionStorage) getBatchedTxByHash(hash common.Hash) (*stored.BatchedTx, error) {
@@ -379,7 +385,7 @@ func (s *TransactionStorage) getTransactionByHash(hash common.Hash) (models.Gene
 		return batchedTx.ToGenericTransaction(), nil
 	}
 
-	if err != nil && !errors.Is(err, bh.ErrNotFound) {
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
 		return nil, err
 	}
 
@@ -393,7 +399,7 @@ func (s *TransactionStorage) getTransactionByHash(hash common.Hash) (models.Gene
 
 	var failedTx stored.FailedTx
 	err = s.database.Badger.Get(hash, &failedTx)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		return nil, errors.WithStack(NewNotFoundError("transaction"))
 	}
 	if err != nil {
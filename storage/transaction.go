@@ -6,10 +6,8 @@ import (
 	"github.com/Worldcoin/hubble-commander/db"
 	"github.com/Worldcoin/hubble-commander/models"
 	"github.com/Worldcoin/hubble-commander/models/stored"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func (s *Storage) GetTransactionWithBatchDetails(hash common.Hash) (tx *models.TransactionWithBatchDetails, err error) {
@@ -64,7 +62,7 @@ func (s *TransactionStorage) GetTransactionsByCommitmentID(id models.CommitmentI
 	// nolint: gocritic
 	seekPrefix := append(stored.BatchedTxPrefix, id.Bytes()...)
 
-	err := s.database.Badger.Iterator(seekPrefix, db.PrefetchIteratorOpts, func(item *bdg.Item) (bool, error) {
+	err := s.database.Badger.Iterator(seekPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
 		var batchedTx stored.BatchedTx
 		err := item.Value(batchedTx.SetBytes)
 		if err != nil {
@@ -144,26 +142,26 @@ func (s *TransactionStorage) unsafeAddTransaction(tx models.GenericTransaction)
 
 func (s *TransactionStorage) checkNoBatchedTx(hash *common.Hash) error {
 	_, err := s.getBatchedTxByHash(*hash)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		// there is no tx, so we're free to insert a tx!
 		return nil
 	}
 	if err == nil {
 		// we successfully fetched a tx, so our caller should fail
-		return bh.ErrKeyExists
+		return db.ErrKeyExists
 	}
 	return err
 }
 
 func (s *TransactionStorage) checkNoTx(hash *common.Hash, result interface{}) error {
 	err := s.database.Badger.Get(*hash, result)
-	if errors.Is(err, bh.ErrNotFound) {
+	if errors.Is(err, db.ErrNotFound) {
 		// there is no tx, so we're free to insert a tx!
 		return nil
 	}
 	if err == nil {
 		// we successfully fetched a tx, so our caller should fail
-		return bh.ErrKeyExists
+		return db.ErrKeyExists
 	}
 	return err
 }
@@ -197,7 +195,7 @@ func (s *TransactionStorage) BatchUpsertTransaction(txs models.GenericTransactio
 	return s.executeInTransaction(TxOptions{}, func(txStorage *TransactionStorage) error {
 		for i := 0; i < txs.Len(); i++ {
 			err := txStorage.AddTransaction(txs.At(i))
-			if errors.Is(err, bh.ErrKeyExists) {
+			if errors.Is(err, db.ErrKeyExists) {
 				err = txStorage.MarkTransactionAsIncluded(
 					txs.At(i), txs.At(i).GetBase().CommitmentSlot,
 				)
@@ -0,0 +1,660 @@
+package storage
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/dto"
+	"github.com/Worldcoin/hubble-commander/models/enums/txtype"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// indexedTypes lists every badgerhold type which implements Indexes() together with
+// a decoder of its stored value
+var indexedTypes = []struct {
+	storer db.Storer
+	decode func(data []byte) (interface{}, error)
+}{
+	{
+		storer: models.AccountLeaf{},
+		decode: func(data []byte) (interface{}, error) {
+			var leaf models.AccountLeaf
+			err := db.Decode(data, &leaf)
+			return &leaf, err
+		},
+	},
+	{
+		storer: stored.BatchedTx{},
+		decode: func(data []byte) (interface{}, error) {
+			var tx stored.BatchedTx
+			err := db.Decode(data, &tx)
+			return &tx, err
+		},
+	},
+	{
+		storer: stored.FailedTx{},
+		decode: func(data []byte) (interface{}, error) {
+			var tx stored.FailedTx
+			err := db.Decode(data, &tx)
+			return &tx, err
+		},
+	},
+}
+
+// databaseRepairChunkSize is the number of repaired entries committed in a single transaction,
+// repairing everything at once fails with ErrTxnTooBig on large databases
+const databaseRepairChunkSize = 1000
+
+type pendingState struct {
+	nonce   models.Uint256
+	balance models.Uint256
+}
+
+type indexEntry struct {
+	key      []byte
+	expected db.KeyList
+}
+
+// rawIndexEntry is a raw-key index entry to write, or to delete when value is nil
+type rawIndexEntry struct {
+	key   []byte
+	value []byte
+}
+
+// VerifyDatabase rebuilds the data derived from other stored data and reports every place where
+// it differs from what is stored. With repair set the pending states, pending pubkey balances,
+// badgerhold indexes and raw-key indexes are overwritten with the recomputed values. Tree roots and orphaned
+// transactions are only reported, those require restoring a backup or resyncing.
+// Repairs are committed in chunks, an interrupted repair is finished by running it again.
+func (s *Storage) VerifyDatabase(repair bool) (*dto.DatabaseReport, error) {
+	report := &dto.DatabaseReport{}
+
+	err := s.verifyTreeRootsInto(report)
+	if err != nil {
+		return nil, err
+	}
+
+	report.OrphanedTxs, err = s.findOrphanedBatchedTxs()
+	if err != nil {
+		return nil, err
+	}
+
+	report.PendingStates, err = s.findPendingStateMismatches()
+	if err != nil {
+		return nil, err
+	}
+
+	report.PendingPubkeyBalances, err = s.findPendingPubkeyBalanceMismatches()
+	if err != nil {
+		return nil, err
+	}
+
+	indexEntries, err := s.findIndexMismatches(report)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIndexEntries, err := s.findRawIndexMismatches(report)
+	if err != nil {
+		return nil, err
+	}
+
+	if !repair {
+		return report, nil
+	}
+
+	err = s.repairDatabase(report, indexEntries, rawIndexEntries)
+	if err != nil {
+		return nil, err
+	}
+	report.Repaired = true
+	return report, nil
+}
+
+func (s *Storage) verifyTreeRootsInto(report *dto.DatabaseReport) error {
+	stateRoot, accountRoot, err := s.recomputeTreeRoots()
+	if err != nil {
+		return err
+	}
+
+	report.StateTreeRoot, err = findTreeRootMismatch(s.StateTree.Root, stateRoot)
+	if err != nil {
+		return err
+	}
+	report.AccountTreeRoot, err = findTreeRootMismatch(s.AccountTree.Root, accountRoot)
+	return err
+}
+
+func findTreeRootMismatch(storedRoot func() (*common.Hash, error), recomputedRoot *common.Hash) (*dto.TreeRootMismatch, error) {
+	root, err := storedRoot()
+	if err != nil {
+		return nil, err
+	}
+	if *root == *recomputedRoot {
+		return nil, nil
+	}
+	return &dto.TreeRootMismatch{
+		Stored:     *root,
+		Recomputed: *recomputedRoot,
+	}, nil
+}
+
+func (s *Storage) findOrphanedBatchedTxs() ([]models.CommitmentSlot, error) {
+	orphanedTxs := make([]models.CommitmentSlot, 0)
+	commitmentExists := make(map[models.CommitmentID]bool)
+
+	err := s.database.Badger.Iterator(stored.BatchedTxPrefix, db.KeyIteratorOpts, func(item db.Item) (bool, error) {
+		var slot models.CommitmentSlot
+		err := db.DecodeKey(item.Key(), &slot, stored.BatchedTxPrefix)
+		if err != nil {
+			return false, err
+		}
+
+		commitmentID := *slot.CommitmentID()
+		exists, checked := commitmentExists[commitmentID]
+		if !checked {
+			_, err = s.getStoredCommitment(&commitmentID)
+			if err != nil && !IsNotFoundError(err) {
+				return false, err
+			}
+			exists = err == nil
+			commitmentExists[commitmentID] = exists
+		}
+
+		if !exists {
+			orphanedTxs = append(orphanedTxs, slot)
+		}
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return orphanedTxs, nil
+}
+
+// findPendingStateMismatches recomputes the pending state the same way RecomputePendingState
+// does, but with a single pass over the mempool
+func (s *Storage) findPendingStateMismatches() ([]dto.PendingStateMismatch, error) {
+	storedStates, err := s.allPendingStates()
+	if err != nil {
+		return nil, err
+	}
+
+	recomputedStates := make(map[uint32]*pendingState, len(storedStates))
+	getRecomputed := func(stateID uint32) (*pendingState, error) {
+		state, ok := recomputedStates[stateID]
+		if ok {
+			return state, nil
+		}
+		state, err := getLeafState(s.StateTree, stateID)
+		if err != nil {
+			return nil, err
+		}
+		recomputedStates[stateID] = state
+		return state, nil
+	}
+
+	for stateID := range storedStates {
+		_, err = getRecomputed(stateID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.forEachMempoolTransaction(func(pendingTx *stored.PendingTx) error {
+		sender, err := getRecomputed(pendingTx.FromStateID)
+		if err != nil {
+			return err
+		}
+		sender.nonce = *sender.nonce.AddN(1)
+		sender.balance = *sender.balance.Sub(pendingTx.Amount.Add(&pendingTx.Fee))
+
+		if pendingTx.TxType != txtype.Transfer {
+			return nil
+		}
+		receiver, err := getRecomputed(pendingTx.ToTransfer().ToStateID)
+		if err != nil {
+			return err
+		}
+		receiver.balance = *receiver.balance.Add(&pendingTx.Amount)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]dto.PendingStateMismatch, 0)
+	for stateID, recomputed := range recomputedStates {
+		storedState, ok := storedStates[stateID]
+		if !ok {
+			// getPendingState falls back to the state leaf when there is no pending state
+			storedState, err = getLeafState(s.StateTree, stateID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if *storedState == *recomputed {
+			continue
+		}
+		mismatches = append(mismatches, dto.PendingStateMismatch{
+			StateID:           stateID,
+			StoredNonce:       storedState.nonce,
+			StoredBalance:     storedState.balance,
+			RecomputedNonce:   recomputed.nonce,
+			RecomputedBalance: recomputed.balance,
+		})
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].StateID < mismatches[j].StateID
+	})
+	return mismatches, nil
+}
+
+func getLeafState(stateTree *StateTree, stateID uint32) (*pendingState, error) {
+	leaf, err := stateTree.LeafOrEmpty(stateID)
+	if err != nil {
+		return nil, err
+	}
+	return &pendingState{nonce: leaf.Nonce, balance: leaf.Balance}, nil
+}
+
+func (s *Storage) allPendingStates() (map[uint32]*pendingState, error) {
+	states := make(map[uint32]*pendingState)
+
+	err := s.database.Badger.Iterator(pendingStatePrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		nonce, balance, err := itemToPendingState(item)
+		if err != nil {
+			return false, err
+		}
+		states[decodePendingStateKey(item.Key())] = &pendingState{nonce: *nonce, balance: *balance}
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (s *Storage) findPendingPubkeyBalanceMismatches() ([]dto.PendingPubkeyBalanceMismatch, error) {
+	recomputedBalances, err := s.pendingPubkeyBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	storedBalances, err := s.GetPendingPubkeyBalances(nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]dto.PendingPubkeyBalanceMismatch, 0)
+	for i := range storedBalances {
+		storedBalance := &storedBalances[i]
+		recomputed, ok := recomputedBalances[storedBalance.PubKey]
+		delete(recomputedBalances, storedBalance.PubKey)
+		if !ok {
+			recomputed = models.NewUint256(0)
+		}
+		if storedBalance.Balance.Cmp(recomputed) == 0 {
+			continue
+		}
+		mismatches = append(mismatches, dto.PendingPubkeyBalanceMismatch{
+			PubKey:            storedBalance.PubKey,
+			StoredBalance:     storedBalance.Balance,
+			RecomputedBalance: *recomputed,
+		})
+	}
+
+	for pubKey, recomputed := range recomputedBalances {
+		mismatches = append(mismatches, dto.PendingPubkeyBalanceMismatch{
+			PubKey:            pubKey,
+			StoredBalance:     models.MakeUint256(0),
+			RecomputedBalance: *recomputed,
+		})
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return bytes.Compare(mismatches[i].PubKey.Bytes(), mismatches[j].PubKey.Bytes()) < 0
+	})
+	return mismatches, nil
+}
+
+func (s *Storage) findIndexMismatches(report *dto.DatabaseReport) ([]indexEntry, error) {
+	report.Indexes = make([]dto.IndexMismatch, 0)
+	entries := make([]indexEntry, 0)
+
+	for i := range indexedTypes {
+		storer := indexedTypes[i].storer
+		typeName := storer.Type()
+		for indexName, index := range storer.Indexes() {
+			prefix := models.GetBadgerHoldPrefix(storer)
+			expected, err := s.expectedIndex(prefix, indexName, index, indexedTypes[i].decode)
+			if err != nil {
+				return nil, err
+			}
+
+			typeEntries, err := s.compareIndex(report, typeName, indexName, expected)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, typeEntries...)
+		}
+	}
+	return entries, nil
+}
+
+// expectedIndex maps every index value to the sorted keys of items which should be found under it
+func (s *Storage) expectedIndex(
+	prefix []byte,
+	indexName string,
+	index db.Index,
+	decode func(data []byte) (interface{}, error),
+) (map[string]db.KeyList, error) {
+	expected := make(map[string]db.KeyList)
+
+	err := s.database.Badger.Iterator(prefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		data, err := item.ValueCopy(nil)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		value, err := decode(data)
+		if err != nil {
+			return false, err
+		}
+
+		indexValue, err := index.IndexFunc(indexName, value)
+		if err != nil {
+			return false, err
+		}
+		if indexValue == nil {
+			return false, nil
+		}
+
+		// badgerhold keeps keys of an index entry sorted and the iterator visits them in order
+		expected[string(indexValue)] = append(expected[string(indexValue)], item.KeyCopy(nil))
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return expected, nil
+}
+
+func (s *Storage) compareIndex(
+	report *dto.DatabaseReport,
+	typeName, indexName string,
+	expected map[string]db.KeyList,
+) ([]indexEntry, error) {
+	entries := make([]indexEntry, 0)
+	compare := func(value []byte, storedKeys db.KeyList) {
+		expectedKeys := expected[string(value)]
+		missing, dangling := diffKeyLists(expectedKeys, storedKeys)
+		if missing == 0 && dangling == 0 {
+			return
+		}
+		report.Indexes = append(report.Indexes, dto.IndexMismatch{
+			Type:         typeName,
+			Index:        indexName,
+			Value:        value,
+			MissingKeys:  missing,
+			DanglingKeys: dangling,
+		})
+		entries = append(entries, indexEntry{
+			key:      db.IndexKey([]byte(typeName), indexName, value),
+			expected: expectedKeys,
+		})
+	}
+
+	prefix := db.IndexKeyPrefix([]byte(typeName), indexName)
+	err := s.database.Badger.Iterator(prefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		var storedKeys db.KeyList
+		err := item.Value(func(data []byte) error {
+			return db.Decode(data, &storedKeys)
+		})
+		if err != nil {
+			return false, err
+		}
+
+		value := item.KeyCopy(nil)[len(prefix):]
+		compare(value, storedKeys)
+		delete(expected, string(value))
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+
+	// index values of items which have no stored index entry at all
+	values := make([]string, 0, len(expected))
+	for value := range expected {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		compare([]byte(value), nil)
+	}
+	return entries, nil
+}
+
+// diffKeyLists counts keys present only in the expected and only in the stored sorted lists
+func diffKeyLists(expected, storedKeys db.KeyList) (missing, dangling int) {
+	i, j := 0, 0
+	for i < len(expected) && j < len(storedKeys) {
+		switch bytes.Compare(expected[i], storedKeys[j]) {
+		case -1:
+			missing++
+			i++
+		case 1:
+			dangling++
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return missing + len(expected) - i, dangling + len(storedKeys) - j
+}
+
+// findRawIndexMismatches compares the indexes stored with one raw key per item, see state_leaf_index.go
+// and mass_migration_index.go. The Value of their mismatches is the raw key of the entry.
+func (s *Storage) findRawIndexMismatches(report *dto.DatabaseReport) ([]rawIndexEntry, error) {
+	byToken, byBalance, err := s.expectedStateLeafIndexes()
+	if err != nil {
+		return nil, err
+	}
+	byFromStateID, err := s.expectedMassMigrationIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	stateLeafType := string(models.GetTypeName(stored.FlatStateLeaf{}))
+	rawIndexes := []struct {
+		typeName string
+		prefix   []byte
+		expected map[string][]byte
+	}{
+		{typeName: stateLeafType, prefix: stateLeafByTokenPrefix, expected: byToken},
+		{typeName: stateLeafType, prefix: stateLeafByBalancePrefix, expected: byBalance},
+		{typeName: string(stored.BatchedTxName), prefix: massMigrationByFromStateIDPrefix, expected: byFromStateID},
+	}
+
+	entries := make([]rawIndexEntry, 0)
+	for i := range rawIndexes {
+		index := &rawIndexes[i]
+		indexEntries, err := s.compareRawIndex(report, index.typeName, index.prefix, index.expected)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, indexEntries...)
+	}
+	return entries, nil
+}
+
+func (s *Storage) expectedStateLeafIndexes() (byToken, byBalance map[string][]byte, err error) {
+	byToken = make(map[string][]byte)
+	byBalance = make(map[string][]byte)
+
+	err = s.StateTree.IterateLeaves(func(stateLeaf *models.StateLeaf) error {
+		leaf := stored.MakeStateLeaf(stateLeaf)
+		if !isIndexedStateLeaf(&leaf) {
+			return nil
+		}
+		value := stored.EncodeUint32(leaf.StateID)
+		byToken[string(stateLeafByTokenKey(&leaf.TokenID, leaf.StateID))] = value
+		byBalance[string(stateLeafByBalanceKey(&leaf.TokenID, &leaf.Balance, leaf.StateID))] = value
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return byToken, byBalance, nil
+}
+
+func (s *Storage) expectedMassMigrationIndex() (map[string][]byte, error) {
+	expected := make(map[string][]byte)
+
+	err := s.database.Badger.Iterator(stored.BatchedTxPrefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		var batchedTx stored.BatchedTx
+		err := item.Value(func(value []byte) error {
+			return db.Decode(value, &batchedTx)
+		})
+		if err != nil {
+			return false, err
+		}
+		if batchedTx.TxType == txtype.MassMigration {
+			expected[string(massMigrationByFromStateIDKey(&batchedTx))] = batchedTx.Hash.Bytes()
+		}
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+	return expected, nil
+}
+
+func (s *Storage) compareRawIndex(
+	report *dto.DatabaseReport,
+	typeName string,
+	prefix []byte,
+	expected map[string][]byte,
+) ([]rawIndexEntry, error) {
+	indexName := string(bytes.TrimSuffix(prefix, []byte(":")))
+	entries := make([]rawIndexEntry, 0)
+	addMismatch := func(key, value []byte, missing, dangling int) {
+		report.Indexes = append(report.Indexes, dto.IndexMismatch{
+			Type:         typeName,
+			Index:        indexName,
+			Value:        key,
+			MissingKeys:  missing,
+			DanglingKeys: dangling,
+		})
+		entries = append(entries, rawIndexEntry{key: key, value: value})
+	}
+
+	err := s.database.Badger.Iterator(prefix, db.PrefetchIteratorOpts, func(item db.Item) (bool, error) {
+		key := item.KeyCopy(nil)
+		expectedValue, ok := expected[string(key)]
+		delete(expected, string(key))
+		if !ok {
+			addMismatch(key, nil, 0, 1)
+			return false, nil
+		}
+
+		storedValue, err := item.ValueCopy(nil)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if !bytes.Equal(storedValue, expectedValue) {
+			addMismatch(key, expectedValue, 1, 1)
+		}
+		return false, nil
+	})
+	if err != nil && !errors.Is(err, db.ErrIteratorFinished) {
+		return nil, err
+	}
+
+	// indexed items which have no stored entry
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		addMismatch([]byte(key), expected[key], 1, 0)
+	}
+	return entries, nil
+}
+
+func (s *Storage) repairDatabase(report *dto.DatabaseReport, indexEntries []indexEntry, rawIndexEntries []rawIndexEntry) error {
+	repairs := make(
+		[]func(txStorage *Storage) error,
+		0,
+		len(report.PendingStates)+len(report.PendingPubkeyBalances)+len(indexEntries)+len(rawIndexEntries),
+	)
+	for i := range report.PendingStates {
+		mismatch := &report.PendingStates[i]
+		repairs = append(repairs, func(txStorage *Storage) error {
+			return txStorage.UnsafeSetPendingState(mismatch.StateID, mismatch.RecomputedNonce, mismatch.RecomputedBalance)
+		})
+	}
+
+	for i := range report.PendingPubkeyBalances {
+		mismatch := &report.PendingPubkeyBalances[i]
+		repairs = append(repairs, func(txStorage *Storage) error {
+			return txStorage.setPendingPubkeyBalance(&mismatch.PubKey, &mismatch.RecomputedBalance)
+		})
+	}
+
+	for i := range indexEntries {
+		entry := &indexEntries[i]
+		repairs = append(repairs, func(txStorage *Storage) error {
+			// an empty list is a valid entry, see initializeIndex
+			keyList := entry.expected
+			if keyList == nil {
+				keyList = make(db.KeyList, 0)
+			}
+			encodedKeyList, err := db.Encode(keyList)
+			if err != nil {
+				return err
+			}
+			return txStorage.rawSet(entry.key, encodedKeyList)
+		})
+	}
+
+	for i := range rawIndexEntries {
+		entry := &rawIndexEntries[i]
+		repairs = append(repairs, func(txStorage *Storage) error {
+			return txStorage.database.Badger.RawUpdate(func(txn db.Txn) error {
+				if entry.value == nil {
+					return errors.WithStack(txn.Delete(entry.key))
+				}
+				return errors.WithStack(txn.Set(entry.key, entry.value))
+			})
+		})
+	}
+
+	for start := 0; start < len(repairs); start += databaseRepairChunkSize {
+		end := start + databaseRepairChunkSize
+		if end > len(repairs) {
+			end = len(repairs)
+		}
+
+		err := s.ExecuteInReadWriteTransaction(func(txStorage *Storage) error {
+			for i := start; i < end; i++ {
+				err := repairs[i](txStorage)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
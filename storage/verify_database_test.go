@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Worldcoin/hubble-commander/db"
+	"github.com/Worldcoin/hubble-commander/models"
+	"github.com/Worldcoin/hubble-commander/models/stored"
+	"github.com/Worldcoin/hubble-commander/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyDatabaseTestSuite struct {
+	*require.Assertions
+	suite.Suite
+	storage *TestStorage
+}
+
+func (s *VerifyDatabaseTestSuite) SetupSuite() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *VerifyDatabaseTestSuite) SetupTest() {
+	var err error
+	s.storage, err = NewTestStorage()
+	s.NoError(err)
+
+	err = s.storage.AccountTree.SetSingle(&models.AccountLeaf{
+		PubKeyID:  1,
+		PublicKey: models.PublicKey{1, 2, 3},
+	})
+	s.NoError(err)
+
+	for stateID := uint32(1); stateID <= 2; stateID++ {
+		_, err = s.storage.StateTree.Set(stateID, &models.UserState{
+			PubKeyID: 1,
+			TokenID:  models.MakeUint256(0),
+			Balance:  models.MakeUint256(100),
+			Nonce:    models.MakeUint256(0),
+		})
+		s.NoError(err)
+	}
+
+	err = s.storage.AddMempoolTx(testutils.NewTransfer(1, 2, 0, 10))
+	s.NoError(err)
+	err = s.storage.AddMempoolTx(testutils.NewCreate2Transfer(2, nil, 0, 20, &models.PublicKey{4, 5, 6}))
+	s.NoError(err)
+}
+
+func (s *VerifyDatabaseTestSuite) TearDownTest() {
+	err := s.storage.Teardown()
+	s.NoError(err)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_ConsistentDatabase() {
+	report, err := s.storage.VerifyDatabase(false)
+	s.NoError(err)
+	s.True(report.IsConsistent())
+	s.False(report.Repaired)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_DetectsModifiedStateLeaf() {
+	leaf, err := s.storage.StateTree.Leaf(1)
+	s.NoError(err)
+	leaf.Balance = models.MakeUint256(300)
+	err = s.storage.StateTree.upsertStateLeaf(leaf)
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.NotNil(report.StateTreeRoot)
+	s.NotEqual(report.StateTreeRoot.Stored, report.StateTreeRoot.Recomputed)
+	s.Nil(report.AccountTreeRoot)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_DetectsOrphanedBatchedTx() {
+	tx := testutils.NewTransfer(1, 2, 1, 10)
+	tx.CommitmentSlot = models.NewCommitmentSlot(models.CommitmentID{BatchID: models.MakeUint256(5)}, 0)
+	err := s.storage.insertBatchedTx(stored.NewBatchedTx(tx))
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(false)
+	s.NoError(err)
+	s.Equal([]models.CommitmentSlot{*tx.CommitmentSlot}, report.OrphanedTxs)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsPendingState() {
+	err := s.storage.UnsafeSetPendingState(1, models.MakeUint256(7), models.MakeUint256(1))
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.PendingStates, 1)
+	s.Equal(uint32(1), report.PendingStates[0].StateID)
+	s.Equal(models.MakeUint256(7), report.PendingStates[0].StoredNonce)
+	s.Equal(models.MakeUint256(1), report.PendingStates[0].RecomputedNonce)
+	s.Equal(models.MakeUint256(80), report.PendingStates[0].RecomputedBalance)
+	s.True(report.Repaired)
+
+	s.requireConsistent()
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsPendingPubkeyBalance() {
+	pubKey := models.PublicKey{4, 5, 6}
+	err := s.storage.setPendingPubkeyBalance(&pubKey, models.NewUint256(1000))
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.PendingPubkeyBalances, 1)
+	s.Equal(models.MakeUint256(1000), report.PendingPubkeyBalances[0].StoredBalance)
+	s.Equal(models.MakeUint256(20), report.PendingPubkeyBalances[0].RecomputedBalance)
+
+	s.requireConsistent()
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsInChunks() {
+	mismatchCount := 2*databaseRepairChunkSize + 1
+	for i := 0; i < mismatchCount; i++ {
+		pubKey := models.PublicKey{}
+		binary.BigEndian.PutUint32(pubKey[:], uint32(i+1))
+		err := s.storage.setPendingPubkeyBalance(&pubKey, models.NewUint256(1))
+		s.NoError(err)
+	}
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.PendingPubkeyBalances, mismatchCount)
+	s.True(report.Repaired)
+
+	s.requireConsistent()
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsMissingIndexEntry() {
+	publicKey := models.PublicKey{1, 2, 3}
+	indexKey := db.IndexKey(models.AccountLeafName, "PublicKey", publicKey.Bytes())
+	err := s.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
+		return txn.Delete(indexKey)
+	})
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.Indexes, 1)
+	s.Equal("PublicKey", report.Indexes[0].Index)
+	s.Equal(1, report.Indexes[0].MissingKeys)
+	s.Equal(0, report.Indexes[0].DanglingKeys)
+
+	s.requireConsistent()
+
+	leaves, err := s.storage.AccountTree.Leaves(&publicKey)
+	s.NoError(err)
+	s.Len(leaves, 1)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsStateLeafIndexes() {
+	tokenID := models.MakeUint256(0)
+	err := s.storage.database.Badger.RawUpdate(func(txn db.Txn) error {
+		err := txn.Delete(stateLeafByTokenKey(&tokenID, 1))
+		if err != nil {
+			return err
+		}
+		return txn.Set(stateLeafByBalanceKey(&tokenID, models.NewUint256(50), 2), stored.EncodeUint32(2))
+	})
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.Indexes, 2)
+	s.Equal("StateLeafByToken", report.Indexes[0].Index)
+	s.Equal(1, report.Indexes[0].MissingKeys)
+	s.Equal("StateLeafByBalance", report.Indexes[1].Index)
+	s.Equal(1, report.Indexes[1].DanglingKeys)
+
+	s.requireConsistent()
+
+	leaves, _, err := s.storage.GetStateLeavesByTokenID(tokenID, 0, 0)
+	s.NoError(err)
+	s.Len(leaves, 2)
+	leaves, err = s.storage.GetRichestStateLeaves(tokenID, 0)
+	s.NoError(err)
+	s.Len(leaves, 2)
+}
+
+func (s *VerifyDatabaseTestSuite) TestVerifyDatabase_RepairsDanglingMassMigrationIndexEntry() {
+	tx := testutils.NewMassMigration(1, 2, 1, 10)
+	tx.CommitmentSlot = models.NewCommitmentSlot(models.CommitmentID{BatchID: models.MakeUint256(5)}, 0)
+	err := s.storage.rawSet(massMigrationByFromStateIDKey(stored.NewBatchedTx(tx)), tx.Hash.Bytes())
+	s.NoError(err)
+
+	report, err := s.storage.VerifyDatabase(true)
+	s.NoError(err)
+	s.Len(report.Indexes, 1)
+	s.Equal("MassMigrationByFromStateID", report.Indexes[0].Index)
+	s.Equal(0, report.Indexes[0].MissingKeys)
+	s.Equal(1, report.Indexes[0].DanglingKeys)
+
+	s.requireConsistent()
+
+	massMigrations, err := s.storage.GetBatchedMassMigrationsByFromStateID(1)
+	s.NoError(err)
+	s.Len(massMigrations, 0)
+}
+
+func (s *VerifyDatabaseTestSuite) requireConsistent() {
+	report, err := s.storage.VerifyDatabase(false)
+	s.NoError(err)
+	s.True(report.IsConsistent())
+}
+
+func TestVerifyDatabaseTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifyDatabaseTestSuite))
+}
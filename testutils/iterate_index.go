@@ -2,9 +2,7 @@ package testutils
 
 import (
 	"github.com/Worldcoin/hubble-commander/db"
-	bdg "github.com/dgraph-io/badger/v3"
 	"github.com/stretchr/testify/require"
-	bh "github.com/timshannon/badgerhold/v4"
 )
 
 func IterateIndex(
@@ -12,15 +10,15 @@ func IterateIndex(
 	badger *db.Database,
 	typeName []byte,
 	indexName string,
-	handleIndex func(encodedKey []byte, keyList bh.KeyList),
+	handleIndex func(encodedKey []byte, keyList db.KeyList),
 ) {
 	indexPrefix := db.IndexKeyPrefix(typeName, indexName)
-	err := badger.Iterator(indexPrefix, db.PrefetchIteratorOpts, func(item *bdg.Item) (finish bool, err error) {
+	err := badger.Iterator(indexPrefix, db.PrefetchIteratorOpts, func(item db.Item) (finish bool, err error) {
 		// Get key value
 		encodedKeyValue := item.Key()[len(indexPrefix):]
 
 		// Decode value
-		var keyList bh.KeyList
+		var keyList db.KeyList
 		err = item.Value(func(val []byte) error {
 			return db.Decode(val, &keyList)
 		})
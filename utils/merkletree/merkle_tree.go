@@ -137,6 +137,19 @@ func (m *MerkleTree) GetWitness(leafIndex uint32) models.Witness {
 	return witness
 }
 
+// ComputeRoot computes the root of a tree from a node at the given path and its witness, bottom-up as in the
+// MerkleTree library of the contracts
+func ComputeRoot(node common.Hash, path uint32, witness models.Witness) common.Hash {
+	for i := range witness {
+		if (path>>i)&1 == 0 {
+			node = utils.HashTwo(node, witness[i])
+		} else {
+			node = utils.HashTwo(witness[i], node)
+		}
+	}
+	return node
+}
+
 func getRequiredTreeHeight(leafCount int32) uint8 {
 	if leafCount == 1 {
 		return 2
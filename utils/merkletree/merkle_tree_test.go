@@ -103,3 +103,13 @@ func TestMerkleTree_GetWitness_ThreeLeaves(t *testing.T) {
 	require.Equal(t, models.Witness{leaf1, h30}, tree.GetWitness(1))
 	require.Equal(t, models.Witness{GetZeroHash(0), h12}, tree.GetWitness(2))
 }
+
+func TestComputeRoot(t *testing.T) {
+	leaves := []common.Hash{utils.RandomHash(), utils.RandomHash(), utils.RandomHash()}
+	tree, err := NewMerkleTree(leaves)
+	require.NoError(t, err)
+
+	for i := range leaves {
+		require.Equal(t, tree.Root(), ComputeRoot(leaves[i], uint32(i), tree.GetWitness(uint32(i))))
+	}
+}